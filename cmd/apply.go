@@ -1,26 +1,53 @@
 package cmd
 
 import (
-	"github/szpinc/nacosctl/pkg/nacos"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/diff"
+	"github.com/Talbot3/nacos-cli/pkg/nacos"
+	"github.com/Talbot3/nacos-cli/pkg/render"
 
 	"github.com/spf13/cobra"
 )
 
-var (
-	file   string
-	dataId string
-)
+// newApplyCmd 创建 apply 命令
+func newApplyCmd(f *factory) *cobra.Command {
+	var (
+		file     string
+		dataId   string
+		fileType string   // 配置类型
+		betaIps  []string // 灰度发布的客户端 IP
+		metadata metadataFlags
+
+		valueFiles []string // 模板变量文件
+		setValues  []string // 命令行模板变量
+		renderOnly bool     // 只输出渲染结果
+		dryRun     bool     // 只输出与服务器的差异，不发布
+	)
 
-// applyCmd represents the apply command
-var applyCmd = &cobra.Command{
-	Use:   "apply",
-	Short: "创建或更新配置",
-	Long: `在 Nacos 服务器上创建或更新配置。
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "创建或更新配置",
+		Long: `在 Nacos 服务器上创建或更新配置。
 
 apply 命令会创建新配置或更新现有配置。
 默认情况下，dataId 从文件名派生，但可以通过 --id 参数覆盖。
-文件类型会从文件扩展名自动检测。`,
-	Example: `  # 使用文件创建或更新配置
+文件类型会从文件扩展名自动检测。
+描述、标签、所属应用等元数据只在显式指定时更新，否则保留服务器上的值。
+
+文件以 .tmpl 结尾或指定了 --values/--set 时，先按 Go text/template 渲染再发布，
+dataId 和类型从去掉 .tmpl 后的文件名推断。变量在模板中直接引用（如 {{ .db.host }}），
+可使用 default、required、env、b64enc、b64dec、quote 函数。
+引用了未提供的变量时报错，不会发布，包括 if、quote、printf 中的引用；只有 default 和 required 的参数允许缺失。
+
+渲染后展开密钥引用，密码等敏感值无需写入配置文件：
+  ${env:DB_PASS}              环境变量
+  ${file:/run/secrets/db}     文件内容（去掉结尾换行）
+  ${secret:vault/db#password} 调用 NACOS_SECRET_HELPER 指定的命令，以引用为参数，输出作为值
+其他 ${...} 占位符保持原样，需要保留字面量时写作 $${env:X}。
+--render-only 和 --dry-run 的输出中解析出的值及敏感键的值默认显示为 ******，使用 --show-secrets 显示明文。`,
+		Example: `  # 使用文件创建或更新配置
   nacosctl apply config --file ./app.yaml -n public -g DEFAULT_GROUP
 
   # 指定自定义 dataId
@@ -36,26 +63,99 @@ apply 命令会创建新配置或更新现有配置。
   nacosctl apply config --file ./app.yaml -n public
 
   # 使用自定义分组
-  nacosctl apply config --file ./app.yaml -n public -g PROD_GROUP`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		return nacosClient.ApplyConfig(nacos.ConfigApplyOperation{
-			NacosOperation: &nacos.NacosOperation{
-				Namespace: namespace,
-				Group:     group,
-			},
-			DataId: dataId,
-			File:   file,
-			Type:   fileType,
-		})
-	},
-}
+  nacosctl apply config --file ./app.yaml -n public -g PROD_GROUP
+
+  # 同时设置描述和标签
+  nacosctl apply config --file ./app.yaml -n public --desc "订单服务主配置" --tags team=payments,tier=prod
+
+  # 灰度发布到指定客户端 IP
+  nacosctl apply config --file ./app.yaml -n public --beta-ips 10.0.0.1,10.0.0.2
+
+  # 按环境渲染模板后发布
+  nacosctl apply config --file ./app.yaml.tmpl --values prod.yaml --set db.host=db.prod -n prod
 
-func init() {
-	applyCmd.Flags().StringVarP(&file, "file", "f", "", "配置文件路径 (必填)")
-	applyCmd.Flags().StringVarP(&dataId, "id", "d", "", "自定义 dataId (默认为文件名)")
-	applyCmd.Flags().StringVarP(&fileType, "type", "t", "", "配置文件类型 (如: yaml, properties, json)。默认从文件扩展名自动检测")
+  # 只查看渲染结果
+  nacosctl apply config --file ./app.yaml.tmpl --values prod.yaml -n prod --render-only
+
+  # 从环境变量展开 ${env:DB_PASS}，发布前查看与服务器的差异
+  DB_PASS=xxx nacosctl apply config --file ./app.yaml -n prod --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			operation := nacos.ConfigApplyOperation{
+				NacosOperation: &nacos.NacosOperation{
+					Namespace: f.namespace,
+					Group:     f.group,
+				},
+				ConfigMetadata: metadata.metadata(cmd),
+				DataId:         dataId,
+				File:           file,
+				Type:           fileType,
+				BetaIps:        betaIps,
+			}
+
+			if len(valueFiles) > 0 || len(setValues) > 0 {
+				values, err := render.LoadValues(valueFiles, setValues)
+				if err != nil {
+					return err
+				}
+				operation.Values = values
+			}
+
+			if renderOnly || dryRun {
+				edit, err := operation.ToEdit()
+				if err != nil {
+					return err
+				}
+				if renderOnly {
+					fmt.Fprint(f.Out, f.redactContent(f.maskSecrets(edit.Content, edit.SecretValues), edit.Type, edit.DataId))
+					return nil
+				}
+				return f.printApplyDiff(cmd.Context(), edit)
+			}
+
+			if err := f.client.ApplyConfigContext(cmd.Context(), operation); err != nil {
+				return err
+			}
+			fmt.Fprintln(f.Out, "OK!")
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", "", "配置文件路径 (必填)")
+	cmd.Flags().StringVarP(&dataId, "id", "d", "", "自定义 dataId (默认为文件名)")
+	cmd.Flags().StringVarP(&fileType, "type", "t", "", "配置文件类型 (如: yaml, properties, json)。默认从文件扩展名自动检测")
+
+	metadata.add(cmd, true)
+	cmd.Flags().StringSliceVar(&betaIps, "beta-ips", nil, "灰度发布的客户端 IP，逗号分隔 (默认正式发布)")
+
+	cmd.Flags().StringArrayVar(&valueFiles, "values", nil, "模板变量文件 (YAML)，可多次指定，后者覆盖前者")
+	cmd.Flags().StringArrayVar(&setValues, "set", nil, "模板变量 key=value，支持 a.b.c 形式的嵌套 key，覆盖 --values")
+	cmd.Flags().BoolVar(&renderOnly, "render-only", false, "只输出渲染后的内容，不发布")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "只输出与服务器上配置的差异，不发布")
+
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
 
-	applyCmd.MarkFlagRequired("file")
+// printApplyDiff 输出服务器上的配置与待发布内容的差异
+func (f *factory) printApplyDiff(ctx context.Context, edit nacos.ConfigEditOperation) error {
+	current := ""
+	config, err := f.client.GetContext(ctx, nacos.ConfigGetOperation{
+		NacosOperation: edit.NacosOperation,
+		DataId:         edit.DataId,
+	})
+	switch {
+	case err == nil:
+		current = config.Content
+	case !errors.Is(err, nacos.ErrConfigNotExist):
+		return err
+	}
 
-	rootCmd.AddCommand(applyCmd)
+	out := diff.Unified(edit.DataId+" (remote)", edit.DataId+" (local)",
+		f.redactContent(f.maskSecrets(current, edit.SecretValues), edit.Type, edit.DataId),
+		f.redactContent(f.maskSecrets(edit.Content, edit.SecretValues), edit.Type, edit.DataId))
+	if out == "" {
+		fmt.Fprintln(f.Out, "配置未修改")
+		return nil
+	}
+	fmt.Fprint(f.Out, out)
+	return nil
 }
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"github.com/Talbot3/nacos-cli/pkg/nacos/nacostest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestApplyConfig(t *testing.T) {
+	server := newConfigServer(t)
+	file := writeFile(t, "order.yaml", "order:\n  timeout: 30s\n")
+
+	out, _, err := runCommand(t, server, "apply", "config", "-f", file, "-n", "dev", "--desc", "订单服务", "--tags", "team=orders")
+	require.NoError(t, err)
+	assertGolden(t, "apply-config", out)
+
+	config, ok := server.Config("dev", "DEFAULT_GROUP", "order.yaml")
+	require.True(t, ok)
+	assert.Equal(t, "order:\n  timeout: 30s\n", config.Content)
+	assert.Equal(t, "yaml", config.Type)
+	assert.Equal(t, "订单服务", config.Desc)
+	assert.Equal(t, "team=orders", config.Tags)
+}
+
+func TestApplyConfigDryRun(t *testing.T) {
+	server := newConfigServer(t)
+	file := writeFile(t, "app.yaml", "server:\n  port: 9090\ndb:\n  host: db.dev\n  password: n3w\n")
+
+	out, _, err := runCommand(t, server, "apply", "config", "-f", file, "-n", "dev", "--dry-run")
+	require.NoError(t, err)
+	assertGolden(t, "apply-config-diff", out)
+
+	// --dry-run 不发布
+	config, ok := server.Config("dev", "DEFAULT_GROUP", "app.yaml")
+	require.True(t, ok)
+	assert.Contains(t, config.Content, "port: 8080")
+
+	out, _, err = runCommand(t, server, "apply", "config", "-f", file, "-n", "dev", "--dry-run", "--show-secrets")
+	require.NoError(t, err)
+	assertGolden(t, "apply-config-diff-show-secrets", out)
+}
+
+func TestApplyConfigDryRunNew(t *testing.T) {
+	server := nacostest.NewServer()
+	defer server.Close()
+	file := writeFile(t, "app.properties", "timeout=30\n")
+
+	out, _, err := runCommand(t, server, "apply", "config", "-f", file, "-n", "dev", "--dry-run")
+	require.NoError(t, err)
+	assertGolden(t, "apply-config-diff-new", out)
+	assert.Empty(t, server.Configs())
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/nacos"
+
+	"github.com/spf13/cobra"
+)
+
+// newBetaCmd 创建 beta 命令
+func newBetaCmd(f *factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "beta",
+		Short: "管理配置的灰度发布",
+		Long: `管理配置的灰度 (beta) 发布。
+
+通过 apply 或 edit 的 --beta-ips 参数将配置只发布给指定 IP 的客户端，
+验证无误后使用 beta promote 正式发布给所有客户端，或使用 beta stop 撤销灰度。`,
+		Example: `  # 灰度发布到两个客户端
+  nacosctl apply config --file ./app.yaml -n public --beta-ips 10.0.0.1,10.0.0.2
+
+  # 查看灰度内容
+  nacosctl beta get config app.yaml -n public
+
+  # 正式发布灰度内容
+  nacosctl beta promote config app.yaml -n public
+
+  # 撤销灰度
+  nacosctl beta stop config app.yaml -n public`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	getCmd := &cobra.Command{
+		Use:   "get",
+		Short: "查看灰度发布",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+	getCmd.AddCommand(newBetaGetConfigCmd(f))
+
+	promoteCmd := &cobra.Command{
+		Use:   "promote",
+		Short: "正式发布灰度内容",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+	promoteCmd.AddCommand(newBetaPromoteConfigCmd(f))
+
+	stopCmd := &cobra.Command{
+		Use:   "stop",
+		Short: "停止灰度发布",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+	stopCmd.AddCommand(newBetaStopConfigCmd(f))
+
+	cmd.AddCommand(getCmd, promoteCmd, stopCmd)
+	return cmd
+}
+
+// newBetaGetConfigCmd 创建 beta get config 命令
+func newBetaGetConfigCmd(f *factory) *cobra.Command {
+	return &cobra.Command{
+		Use:     "config",
+		Short:   "查看配置的灰度内容和灰度 IP",
+		Long:    `查看配置的灰度内容，灰度 IP 输出到标准错误，内容输出到标准输出。`,
+		Example: `  nacosctl beta get config app.yaml -n public -g DEFAULT_GROUP`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errors.New("请指定 dataId")
+			}
+
+			beta, err := f.client.GetBetaContext(cmd.Context(), nacos.ConfigGetOperation{
+				NacosOperation: &nacos.NacosOperation{
+					Namespace: f.namespace,
+					Group:     f.group,
+				},
+				DataId: args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(f.ErrOut, "灰度 IP:", beta.BetaIps)
+			fmt.Fprintln(f.Out, f.redactContent(beta.Content, beta.Type, args[0]))
+			return nil
+		},
+	}
+}
+
+// newBetaPromoteConfigCmd 创建 beta promote config 命令
+func newBetaPromoteConfigCmd(f *factory) *cobra.Command {
+	return &cobra.Command{
+		Use:     "config",
+		Short:   "将灰度内容正式发布给所有客户端",
+		Long:    `将配置的灰度内容正式发布给所有客户端，并停止灰度。`,
+		Example: `  nacosctl beta promote config app.yaml -n public -g DEFAULT_GROUP`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errors.New("请指定 dataId")
+			}
+
+			err := f.client.PromoteBetaContext(cmd.Context(), nacos.ConfigGetOperation{
+				NacosOperation: &nacos.NacosOperation{
+					Namespace: f.namespace,
+					Group:     f.group,
+				},
+				DataId: args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(f.Out, "灰度配置已正式发布")
+			return nil
+		},
+	}
+}
+
+// newBetaStopConfigCmd 创建 beta stop config 命令
+func newBetaStopConfigCmd(f *factory) *cobra.Command {
+	return &cobra.Command{
+		Use:     "config",
+		Short:   "停止配置的灰度发布",
+		Long:    `停止配置的灰度发布，灰度客户端恢复使用正式配置。`,
+		Example: `  nacosctl beta stop config app.yaml -n public -g DEFAULT_GROUP`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errors.New("请指定 dataId")
+			}
+
+			err := f.client.StopBetaContext(cmd.Context(), nacos.ConfigDeleteOperation{
+				NacosOperation: &nacos.NacosOperation{
+					Namespace: f.namespace,
+					Group:     f.group,
+				},
+				DataId: args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(f.Out, "灰度发布已停止")
+			return nil
+		},
+	}
+}
@@ -0,0 +1,372 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/content"
+	"github.com/Talbot3/nacos-cli/pkg/diff"
+	"github.com/Talbot3/nacos-cli/pkg/encrypt"
+	"github.com/Talbot3/nacos-cli/pkg/nacos"
+	"github.com/Talbot3/nacos-cli/pkg/util"
+	"sort"
+	"strings"
+
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+)
+
+const (
+	compareIdentical     = "identical"      // 所有环境都存在且内容相同
+	compareEquivalent    = "equivalent"     // 文本不同但解析后的键值相同（仅 --semantic）
+	compareDifferent     = "different"      // 所有环境都存在但内容不同
+	compareMissing       = "missing"        // 部分环境缺失
+	compareNotComparable = "not-comparable" // cipher- 配置未提供密钥无法解密，无法比较
+)
+
+// compareOptions compare 命令的参数
+type compareOptions struct {
+	*factory
+
+	envs     []string // 参与比较的环境，格式为 [context:]namespace
+	semantic bool     // 对内容不同的配置按键值比较
+	output   string   // 输出格式
+}
+
+// compareEnv 参与比较的一个环境
+type compareEnv struct {
+	Name      string
+	Namespace string
+	client    *nacos.Client
+	items     map[compareKey]nacos.NacosPageItem
+}
+
+type compareKey struct {
+	Group  string
+	DataId string
+}
+
+// compareCell 配置在某个环境中的状态
+type compareCell struct {
+	Present bool   `json:"present"`
+	Md5     string `json:"md5,omitempty"`
+	Version string `json:"version,omitempty"` // 相同内容的环境使用相同的版本标记 (A, B, ...)，无法解密时为 ?
+}
+
+// compareRow 一个配置在所有环境中的比较结果
+type compareRow struct {
+	DataId       string                      `json:"dataId"`
+	Group        string                      `json:"group"`
+	Status       string                      `json:"status"`
+	Environments map[string]compareCell      `json:"environments"`
+	Changes      map[string][]content.Change `json:"changes,omitempty"` // --semantic 时各环境相对基准环境的键值差异
+	Diffs        map[string]string           `json:"diffs,omitempty"`   // --semantic 时无法结构化解析的配置的文本差异
+	order        []string
+}
+
+// newCompareCmd 创建 compare 命令
+func newCompareCmd(f *factory) *cobra.Command {
+	o := &compareOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "compare",
+		Short: "比较多个命名空间或集群之间的配置差异",
+		Long: `比较多个环境中的配置，输出每个 dataId 在各环境中的存在情况和内容是否一致。
+
+环境通过多次指定 -n 给出，格式为 [context:]namespace，
+context 为 ~/.nacosctl/config.yaml 中定义的上下文，省略时使用环境变量配置的服务器。
+此处的 -n 覆盖全局参数 -n，每个值都是一个环境，至少需要指定两个。
+
+表格中内容相同的环境使用相同的版本标记 (A, B, ...)，- 表示缺失。状态含义：
+  identical       所有环境都存在且内容相同
+  equivalent      文本不同但解析后的键值相同 (仅 --semantic)
+  different       所有环境都存在但内容不同
+  missing         部分环境缺失
+  not-comparable  cipher- 配置未提供密钥无法解密，无法比较 (版本标记为 ?)
+
+cipher- 配置每次加密的密文都不同，提供密钥时解密后比较明文。
+
+--semantic 会对内容不同的配置按 YAML/JSON/Properties 解析后逐键比较，
+以第一个包含该配置的环境为基准输出差异。`,
+		Example: `  # 比较三个命名空间
+  nacosctl compare -n dev -n test -n prod
+
+  # 只比较指定分组，并输出键值级别的差异
+  nacosctl compare -n dev -n prod -g PAY_GROUP --semantic
+
+  # 比较两个集群中的同名命名空间，输出 JSON
+  nacosctl compare -n test:app -n prod:app -o json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(o.envs) < 2 {
+				return errors.New("请至少通过 -n 指定两个环境")
+			}
+			if o.output != "table" && o.output != "json" {
+				return fmt.Errorf("不支持的输出格式: %s (可选: table, json)", o.output)
+			}
+
+			filterGroup := ""
+			if cmd.Flags().Changed("group") {
+				filterGroup = f.group
+			}
+
+			envs := make([]*compareEnv, 0, len(o.envs))
+			for _, spec := range o.envs {
+				env, err := o.loadCompareEnv(cmd.Context(), spec, filterGroup)
+				if err != nil {
+					return err
+				}
+				envs = append(envs, env)
+			}
+
+			rows, err := o.compareConfigs(cmd.Context(), envs)
+			if err != nil {
+				return err
+			}
+
+			if o.output == "json" {
+				names := make([]string, 0, len(envs))
+				for _, env := range envs {
+					names = append(names, env.Name)
+				}
+				encoder := json.NewEncoder(f.Out)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(map[string]interface{}{
+					"environments": names,
+					"configs":      rows,
+				})
+			}
+
+			o.printCompareTable(envs, rows)
+			return nil
+		},
+	}
+	// 覆盖根命令的 --namespace，允许多次指定。根命令的 namespace 字段不会被赋值，compare 只使用 o.envs
+	cmd.Flags().StringArrayVarP(&o.envs, "namespace", "n", nil, "参与比较的环境 [context:]namespace，可多次指定")
+	cmd.Flags().BoolVar(&o.semantic, "semantic", false, "对内容不同的配置按键值比较并输出差异")
+	cmd.Flags().StringVarP(&o.output, "output", "o", "table", "输出格式 (table, json)")
+	return cmd
+}
+
+// loadCompareEnv 解析环境并列出其中的配置
+func (o *compareOptions) loadCompareEnv(ctx context.Context, spec, filterGroup string) (*compareEnv, error) {
+	env := &compareEnv{Name: spec, Namespace: spec, client: o.client}
+
+	if contextName, ns, ok := strings.Cut(spec, ":"); ok {
+		client, err := o.clientFor(contextName)
+		if err != nil {
+			return nil, err
+		}
+		env.client = client
+		env.Namespace = ns
+	}
+
+	items, err := env.client.AllConfigContext(ctx, nacos.ConfigGetOperation{
+		NacosOperation: &nacos.NacosOperation{
+			Namespace: env.Namespace,
+			Group:     filterGroup,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("列出 %s 的配置失败: %w", spec, err)
+	}
+
+	env.items = make(map[compareKey]nacos.NacosPageItem, len(items))
+	for _, item := range items {
+		env.items[compareKey{Group: item.Group, DataId: item.DataId}] = item
+	}
+	return env, nil
+}
+
+// compareConfigs 计算所有配置在各环境中的状态
+func (o *compareOptions) compareConfigs(ctx context.Context, envs []*compareEnv) ([]*compareRow, error) {
+	keySet := make(map[compareKey]bool)
+	for _, env := range envs {
+		for key := range env.items {
+			keySet[key] = true
+		}
+	}
+
+	keys := make([]compareKey, 0, len(keySet))
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Group != keys[j].Group {
+			return keys[i].Group < keys[j].Group
+		}
+		return keys[i].DataId < keys[j].DataId
+	})
+
+	rows := make([]*compareRow, 0, len(keys))
+	for _, key := range keys {
+		row := &compareRow{
+			DataId:       key.DataId,
+			Group:        key.Group,
+			Environments: make(map[string]compareCell, len(envs)),
+		}
+
+		// contents 仅在列表未返回 MD5、需要语义比较或需要解密时拉取。
+		// 加密配置的密文每次加密都不同，只能解密后比较明文
+		contents := make(map[string]*nacos.NacosConfigDetail)
+		versions := make(map[string]string)
+		present, encrypted := 0, 0
+		for _, env := range envs {
+			row.order = append(row.order, env.Name)
+
+			item, ok := env.items[key]
+			if !ok {
+				row.Environments[env.Name] = compareCell{}
+				continue
+			}
+			present++
+
+			md5 := item.Md5
+			if md5 == "" || o.semantic || encrypt.IsCipherDataId(key.DataId) {
+				detail, err := env.client.GetContext(ctx, getOperation(nacos.ConfigKey{Namespace: env.Namespace, Group: key.Group, DataId: key.DataId}))
+				if err != nil {
+					return nil, fmt.Errorf("获取 %s 中的 %s 失败: %w", env.Name, key.DataId, err)
+				}
+				if detail.EncryptedDataKey != "" {
+					// 未提供密钥，内容仍是密文
+					encrypted++
+					row.Environments[env.Name] = compareCell{Present: true, Version: "?"}
+					continue
+				}
+				if detail.Type == "" {
+					detail.Type = item.Type
+				}
+				contents[env.Name] = detail
+				md5 = util.Md5ToString(detail.Content)
+			}
+
+			version, ok := versions[md5]
+			if !ok {
+				version = string(rune('A' + len(versions)))
+				versions[md5] = version
+			}
+			row.Environments[env.Name] = compareCell{Present: true, Md5: md5, Version: version}
+		}
+
+		switch {
+		case present < len(envs):
+			row.Status = compareMissing
+		case encrypted > 0:
+			row.Status = compareNotComparable
+		case len(versions) == 1:
+			row.Status = compareIdentical
+		default:
+			row.Status = compareDifferent
+		}
+
+		if o.semantic && encrypted == 0 && len(versions) > 1 {
+			o.semanticCompare(row, contents)
+		}
+
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// semanticCompare 以第一个包含该配置的环境为基准，按键值比较其余环境
+func (o *compareOptions) semanticCompare(row *compareRow, contents map[string]*nacos.NacosConfigDetail) {
+	var base string
+	for _, name := range row.order {
+		if contents[name] != nil {
+			base = name
+			break
+		}
+	}
+
+	baseConfig := contents[base]
+	configType := content.Type(baseConfig.Type, row.DataId)
+	baseFlat, err := content.Flatten(baseConfig.Content, configType)
+
+	equivalent := true
+	for _, name := range row.order {
+		other := contents[name]
+		if name == base || other == nil || row.Environments[name].Version == row.Environments[base].Version {
+			continue
+		}
+
+		if err == nil {
+			if otherFlat, otherErr := content.Flatten(other.Content, configType); otherErr == nil {
+				changes := content.Compare(baseFlat, otherFlat)
+				if len(changes) > 0 {
+					equivalent = false
+					if row.Changes == nil {
+						row.Changes = make(map[string][]content.Change)
+					}
+					row.Changes[name] = o.redactChanges(changes)
+				}
+				continue
+			}
+		}
+
+		// 无法解析时退化为文本差异
+		equivalent = false
+		if row.Diffs == nil {
+			row.Diffs = make(map[string]string)
+		}
+		row.Diffs[name] = diff.Unified(base, name,
+			o.redactContent(baseConfig.Content, configType, row.DataId),
+			o.redactContent(other.Content, configType, row.DataId))
+	}
+
+	if equivalent && row.Status == compareDifferent {
+		row.Status = compareEquivalent
+	}
+}
+
+func (o *compareOptions) printCompareTable(envs []*compareEnv, rows []*compareRow) {
+	table := uitable.New()
+	table.MaxColWidth = 50
+
+	header := []interface{}{"DataID", "GROUP"}
+	for _, env := range envs {
+		header = append(header, strings.ToUpper(env.Name))
+	}
+	header = append(header, "STATUS")
+	table.AddRow(header...)
+
+	for _, row := range rows {
+		cells := []interface{}{row.DataId, row.Group}
+		for _, env := range envs {
+			cell := row.Environments[env.Name]
+			if !cell.Present {
+				cells = append(cells, "-")
+			} else {
+				cells = append(cells, cell.Version)
+			}
+		}
+		cells = append(cells, row.Status)
+		table.AddRow(cells...)
+	}
+
+	fmt.Fprintln(o.Out, table)
+
+	for _, row := range rows {
+		if len(row.Changes) == 0 && len(row.Diffs) == 0 {
+			continue
+		}
+		fmt.Fprintf(o.Out, "\n== %s (%s)\n", row.DataId, row.Group)
+		for _, name := range row.order {
+			if changes, ok := row.Changes[name]; ok {
+				fmt.Fprintf(o.Out, "-- %s:\n", name)
+				for _, change := range changes {
+					switch change.Kind {
+					case content.Added:
+						fmt.Fprintf(o.Out, "  + %s: %s\n", change.Key, change.New)
+					case content.Removed:
+						fmt.Fprintf(o.Out, "  - %s: %s\n", change.Key, change.Old)
+					case content.Modified:
+						fmt.Fprintf(o.Out, "  ~ %s: %s -> %s\n", change.Key, change.Old, change.New)
+					}
+				}
+			}
+			if d, ok := row.Diffs[name]; ok {
+				fmt.Fprint(o.Out, d)
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"encoding/json"
+	"github.com/Talbot3/nacos-cli/pkg/encrypt"
+	"github.com/Talbot3/nacos-cli/pkg/nacos/nacostest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCompareServer(t *testing.T) *nacostest.Server {
+	server := nacostest.NewServer()
+	t.Cleanup(server.Close)
+
+	for _, config := range []nacostest.Config{
+		{Namespace: "dev", DataId: "same.yaml", Content: "port: 8080\n"},
+		{Namespace: "prod", DataId: "same.yaml", Content: "port: 8080\n"},
+		{Namespace: "dev", DataId: "reordered.yaml", Content: "a: 1\nb: 2\n"},
+		{Namespace: "prod", DataId: "reordered.yaml", Content: "b: 2\na: 1\n"},
+		{Namespace: "dev", DataId: "changed.yaml", Content: "timeout: 30\n"},
+		{Namespace: "prod", DataId: "changed.yaml", Content: "timeout: 60\n"},
+		{Namespace: "dev", DataId: "dev-only.yaml", Content: "debug: true\n"},
+	} {
+		config.Group = "DEFAULT_GROUP"
+		config.Type = "yaml"
+		server.SetConfig(config)
+	}
+	return server
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want map[string]string // dataId -> status
+	}{
+		{
+			name: "text",
+			args: []string{"-n", "dev", "-n", "prod"},
+			want: map[string]string{
+				"same.yaml":      compareIdentical,
+				"reordered.yaml": compareDifferent,
+				"changed.yaml":   compareDifferent,
+				"dev-only.yaml":  compareMissing,
+			},
+		},
+		{
+			name: "semantic",
+			args: []string{"-n", "dev", "-n", "prod", "--semantic"},
+			want: map[string]string{
+				"same.yaml":      compareIdentical,
+				"reordered.yaml": compareEquivalent,
+				"changed.yaml":   compareDifferent,
+				"dev-only.yaml":  compareMissing,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newCompareServer(t)
+			out, _, err := runCommand(t, server, append([]string{"compare", "-o", "json"}, tt.args...)...)
+			require.NoError(t, err)
+
+			var result struct {
+				Environments []string     `json:"environments"`
+				Configs      []compareRow `json:"configs"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(out), &result))
+			assert.Equal(t, []string{"dev", "prod"}, result.Environments)
+
+			got := map[string]string{}
+			for _, row := range result.Configs {
+				got[row.DataId] = row.Status
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompareSemanticChanges(t *testing.T) {
+	server := newCompareServer(t)
+
+	out, _, err := runCommand(t, server, "compare", "-n", "dev", "-n", "prod", "--semantic")
+	require.NoError(t, err)
+	assertGolden(t, "compare-semantic", out)
+}
+
+func TestCompareRequiresTwoEnvironments(t *testing.T) {
+	server := newCompareServer(t)
+
+	// -n 只指定一个环境时不会与全局 -n 合并
+	_, _, err := runCommand(t, server, "compare", "-n", "dev")
+	assert.EqualError(t, err, "请至少通过 -n 指定两个环境")
+}
+
+func TestCompareCipherConfig(t *testing.T) {
+	const masterKey = "MDEyMzQ1Njc4OWFiY2RlZg==" // base64("0123456789abcdef")
+	key, err := encrypt.NewLocalKeyProvider([]byte("0123456789abcdef"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		key         string
+		prodContent string
+		want        string
+		wantVersion string // prod 的版本标记
+	}{
+		{name: "same plaintext", key: masterKey, prodContent: "password: s3cr3t\n", want: compareIdentical, wantVersion: "A"},
+		{name: "different plaintext", key: masterKey, prodContent: "password: other\n", want: compareDifferent, wantVersion: "B"},
+		{name: "without key", prodContent: "password: s3cr3t\n", want: compareNotComparable, wantVersion: "?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(encrypt.KeyFileEnv, "")
+			t.Setenv(encrypt.KeyEnv, tt.key)
+
+			server := nacostest.NewServer()
+			t.Cleanup(server.Close)
+			for namespace, plaintext := range map[string]string{"dev": "password: s3cr3t\n", "prod": tt.prodContent} {
+				// 相同明文每次加密得到不同的密文
+				encrypted, dataKey, err := encrypt.EncryptContent(key, plaintext)
+				require.NoError(t, err)
+				server.SetConfig(nacostest.Config{
+					Namespace:        namespace,
+					Group:            "DEFAULT_GROUP",
+					DataId:           "cipher-db.yaml",
+					Content:          encrypted,
+					EncryptedDataKey: dataKey,
+				})
+			}
+
+			out, _, err := runCommand(t, server, "compare", "-n", "dev", "-n", "prod", "-o", "json")
+			require.NoError(t, err)
+
+			var result struct {
+				Configs []compareRow `json:"configs"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(out), &result))
+			require.Len(t, result.Configs, 1)
+			assert.Equal(t, tt.want, result.Configs[0].Status)
+			assert.Equal(t, tt.wantVersion, result.Configs[0].Environments["prod"].Version)
+		})
+	}
+}
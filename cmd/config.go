@@ -2,226 +2,516 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
-	"github/szpinc/nacosctl/pkg/editor"
-	"github/szpinc/nacosctl/pkg/nacos"
-	"github/szpinc/nacosctl/pkg/util"
+	"github.com/Talbot3/nacos-cli/pkg/diff"
+	"github.com/Talbot3/nacos-cli/pkg/editor"
+	"github.com/Talbot3/nacos-cli/pkg/encrypt"
+	"github.com/Talbot3/nacos-cli/pkg/nacos"
+	"github.com/Talbot3/nacos-cli/pkg/selector"
+	"github.com/Talbot3/nacos-cli/pkg/util"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/gosuri/uitable"
 	"github.com/spf13/cobra"
 )
 
-var (
-	getAllConfig bool   // 获取所有配置
-	fileType     string // 配置类型
-)
-
-var getConfig = &cobra.Command{
-	Use:   "config",
-	Short: "获取 Nacos 配置",
-	Long: `从 Nacos 服务器获取配置。
-
-可以指定 dataId 获取单个配置，或使用 --all 参数列出命名空间中的所有配置。`,
-	Example: `  # 获取指定配置
+// newGetConfigCmd 创建 get config 命令
+func newGetConfigCmd(f *factory) *cobra.Command {
+	var (
+		getAllConfig  bool   // 获取所有配置
+		watchConfig   bool   // 持续监听配置变更
+		watchDiff     bool   // 监听时输出与上一版本的差异
+		labelSelector string // 标签选择器
+	)
+
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "获取 Nacos 配置",
+		Long: `从 Nacos 服务器获取配置。
+
+可以指定 dataId 获取单个配置，或使用 --all 参数列出命名空间中的所有配置。
+使用 -l 按标签 (config_tags) 筛选，语法与 kubectl 一致：
+  key=value, key!=value, key in (a,b), key notin (a,b), key, !key
+
+键名匹配 --sensitive-keys（默认 password、secret、token、apiKey 等）的键下的值显示为 ******，
+支持 yaml、json、properties。输出重定向到文件或管道时同样脱敏，使用 --show-secrets 显示明文。`,
+		Example: `  # 获取指定配置
   nacosctl get config app.yaml -n public -g DEFAULT_GROUP
 
   # 列出所有配置
   nacosctl get config -A -n public
 
-  # 保存配置到文件
-  nacosctl get config app.yaml -n public > app.yaml`,
-	RunE: func(cmd *cobra.Command, args []string) error {
+  # 按标签筛选配置
+  nacosctl get config -A -n public -l team=payments,tier!=dev
+
+  # 保存配置原文到文件
+  nacosctl get config app.yaml -n public --show-secrets > app.yaml
+
+  # 显示密码等敏感值的明文
+  nacosctl get config app.yaml -n public --show-secrets
+
+  # 持续监听配置变更，Ctrl-C 退出
+  nacosctl get config app.yaml -n public --watch
+
+  # 监听配置变更并输出与上一版本的差异
+  nacosctl get config app.yaml -n public --watch --diff`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			if getAllConfig || labelSelector != "" {
+				dataIds, err := f.selectConfigs(cmd, labelSelector)
+
+				if err != nil {
+					return err
+				}
+
+				f.printTable(dataIds, labelSelector != "")
+				return nil
+			}
+
+			if len(args) == 0 {
+				return errors.New("请指定 dataId")
+			}
+
+			dataId := args[0]
+
+			if watchConfig {
+				return f.watch(cmd.Context(), nacos.ConfigKey{
+					Namespace: f.namespace,
+					Group:     f.group,
+					DataId:    dataId,
+				}, watchDiff)
+			}
 
-		if getAllConfig {
-			dataIds, err := nacosClient.AllConfig(nacos.ConfigGetOperation{
+			configData, err := f.client.GetContext(cmd.Context(), nacos.ConfigGetOperation{
 				NacosOperation: &nacos.NacosOperation{
-					Namespace: namespace,
+					Namespace: f.namespace,
+					Group:     f.group,
 				},
+				DataId: dataId,
 			})
 
 			if err != nil {
 				return err
 			}
 
-			printTable(dataIds)
+			fmt.Fprintln(f.Out, f.redactContent(configData.Content, configData.Type, configData.DataID))
 			return nil
-		}
-
-		if len(args) == 0 {
-			return errors.New("请指定 dataId")
-		}
-
-		dataId := args[0]
-
-		configData, err := nacosClient.Get(nacos.ConfigGetOperation{
-			NacosOperation: &nacos.NacosOperation{
-				Namespace: namespace,
-				Group:     group,
-			},
-			DataId: dataId,
-		})
-
-		if err != nil {
-			return err
-		}
-
-		fmt.Println(configData.Content)
-		return nil
-	},
-	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		dataIds, err := nacosClient.AllConfig(nacos.ConfigGetOperation{
-			NacosOperation: &nacos.NacosOperation{
-				Namespace: namespace,
-			},
-		})
-		for _, dataId := range dataIds {
-			println(dataId.DataId)
-		}
-		if err != nil {
-			return nil, cobra.ShellCompDirectiveNoFileComp
-		}
-		names := []string{}
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			client, err := f.clientFor("")
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			dataIds, err := client.AllConfigContext(cmd.Context(), nacos.ConfigGetOperation{
+				NacosOperation: &nacos.NacosOperation{
+					Namespace: f.namespace,
+				},
+			})
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			names := []string{}
 
-		for _, id := range dataIds {
-			names = append(names, id.DataId)
-		}
-		return names, cobra.ShellCompDirectiveNoFileComp
-	},
+			for _, id := range dataIds {
+				names = append(names, id.DataId)
+			}
+			return names, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+	cmd.Flags().BoolVarP(&getAllConfig, "all", "A", false, "列出命名空间中的所有配置")
+	cmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "按标签筛选配置，如 team=payments,tier!=dev")
+	cmd.Flags().BoolVarP(&watchConfig, "watch", "w", false, "持续监听配置变更并输出每个新版本")
+	cmd.Flags().BoolVar(&watchDiff, "diff", false, "监听时输出与上一版本的差异 (需配合 --watch)")
+	return cmd
 }
 
-var editConfig = &cobra.Command{
-	Use:   "config",
-	Short: "交互式编辑配置",
-	Long: `交互式编辑 Nacos 服务器上的配置。
+// newEditConfigCmd 创建 edit config 命令
+func newEditConfigCmd(f *factory) *cobra.Command {
+	var (
+		fileType string   // 配置类型
+		betaIps  []string // 灰度发布的客户端 IP
+	)
+
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "交互式编辑配置",
+		Long: `交互式编辑 Nacos 服务器上的配置。
 
 配置会被下载并在默认编辑器中打开，保存并关闭编辑器后，更改会自动上传到服务器。`,
-	Example: `  # 编辑配置
+		Example: `  # 编辑配置
   nacosctl edit config app.yaml -n public -g DEFAULT_GROUP
 
   # 指定编辑器
   export EDITOR=vim
-  nacosctl edit config app.yaml -n public`,
-	Run: func(cmd *cobra.Command, args []string) {
-
-		var dataId = args[0]
-
-		configData, err := nacosClient.Get(nacos.ConfigGetOperation{
-			NacosOperation: &nacos.NacosOperation{
-				Namespace: namespace,
-				Group:     group,
-			},
-			DataId: dataId,
-		})
-
-		if err != nil {
-			fmt.Println(err.Error())
-			return
-		}
+  nacosctl edit config app.yaml -n public
+
+  # 编辑后灰度发布到指定客户端 IP
+  nacosctl edit config app.yaml -n public --beta-ips 10.0.0.1`,
+		Run: func(cmd *cobra.Command, args []string) {
+
+			var dataId = args[0]
 
-		e := editor.NewDefaultEditor([]string{})
+			configData, err := f.client.GetContext(cmd.Context(), nacos.ConfigGetOperation{
+				NacosOperation: &nacos.NacosOperation{
+					Namespace: f.namespace,
+					Group:     f.group,
+				},
+				DataId: dataId,
+			})
 
-		buf := &bytes.Buffer{}
-		buf.Write([]byte(configData.Content))
+			if err != nil {
+				fmt.Fprintln(f.Out, err.Error())
+				return
+			}
 
-		edited, file, err := e.LaunchTempFile(fmt.Sprintf("%s-edit-", filepath.Base(os.Args[0])), configData.Type, buf)
+			if configData.EncryptedDataKey != "" {
+				fmt.Fprintln(f.Out, "配置已加密，请通过 --encryption-key-file 或 NACOS_ENCRYPTION_KEY 提供密钥后再编辑")
+				return
+			}
 
-		if err != nil {
-			fmt.Println(err.Error())
-			return
-		}
+			e := editor.NewDefaultEditor([]string{"EDITOR"})
+			e.In, e.Out, e.ErrOut = f.In, f.Out, f.ErrOut
 
-		editedMd5 := util.Md5BytesToString(edited)
+			buf := &bytes.Buffer{}
+			buf.Write([]byte(configData.Content))
 
-		if configData.Md5 == editedMd5 {
-			fmt.Println("配置未修改")
-			return
-		}
+			edited, file, err := e.LaunchTempFile(fmt.Sprintf("%s-edit-", filepath.Base(os.Args[0])), configData.Type, buf)
 
-		defer func(f string) {
-			if e := os.Remove(f); e != nil {
-				fmt.Println("删除临时文件错误:", e)
+			if err != nil {
+				fmt.Fprintln(f.Out, err.Error())
+				return
 			}
-		}(file)
 
-		if fileType == "" {
-			fileType = configData.Type
-		}
+			editedMd5 := util.Md5BytesToString(edited)
 
-		err = nacosClient.Edit(nacos.ConfigEditOperation{
-			NacosOperation: &nacos.NacosOperation{
-				Namespace: namespace,
-				Group:     group,
-			},
-			DataId:  dataId,
-			Content: string(edited),
-			Type:    fileType,
-		})
-
-		if err != nil {
-			fmt.Println(err.Error())
-			return
-		}
+			if configData.Md5 == editedMd5 {
+				fmt.Fprintln(f.Out, "配置未修改")
+				return
+			}
+
+			defer func(file string) {
+				if e := os.Remove(file); e != nil {
+					fmt.Fprintln(f.ErrOut, "删除临时文件错误:", e)
+				}
+			}(file)
+
+			if fileType == "" {
+				fileType = configData.Type
+			}
+
+			// 使用编辑前内容的 MD5 作为 casMd5，编辑期间配置被他人修改时发布失败而不是覆盖
+			casMd5 := ""
+			if !encrypt.IsCipherDataId(dataId) {
+				casMd5 = configData.Md5
+			}
+
+			err = f.client.EditContext(cmd.Context(), nacos.ConfigEditOperation{
+				NacosOperation: &nacos.NacosOperation{
+					Namespace: f.namespace,
+					Group:     f.group,
+				},
+				DataId:  dataId,
+				Content: string(edited),
+				Type:    fileType,
+				BetaIps: betaIps,
+				CasMd5:  casMd5,
+			})
+
+			if errors.Is(err, nacos.ErrConflict) {
+				fmt.Fprintln(f.Out, "配置在编辑期间已被他人修改，未发布，请重新编辑")
+				return
+			}
+			if err != nil {
+				fmt.Fprintln(f.Out, err.Error())
+				return
+			}
 
-		fmt.Println("配置已更新")
-	},
+			if len(betaIps) > 0 {
+				fmt.Fprintln(f.Out, "配置已灰度发布")
+				return
+			}
+			fmt.Fprintln(f.Out, "配置已更新")
+		},
+	}
+	cmd.Flags().StringVarP(&fileType, "type", "t", "", "配置文件类型 (如: yaml, properties, json)")
+	cmd.Flags().StringSliceVar(&betaIps, "beta-ips", nil, "灰度发布的客户端 IP，逗号分隔 (默认正式发布)")
+	return cmd
 }
 
-var deleteConfig = &cobra.Command{
-	Use:   "config",
-	Short: "删除 Nacos 配置",
-	Long: `删除 Nacos 服务器上的配置。
+// newDeleteConfigCmd 创建 delete config 命令
+func newDeleteConfigCmd(f *factory) *cobra.Command {
+	var (
+		labelSelector string // 标签选择器
+		yes           bool   // 批量删除前不确认
+	)
+
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "删除 Nacos 配置",
+		Long: `删除 Nacos 服务器上的配置。
+
+此操作会永久删除配置，无法撤销。
+使用 -l 删除命名空间（或 -g 指定分组）中标签满足选择器的所有配置，
+删除前列出匹配的配置并要求确认，使用 --yes 跳过确认。`,
+		Example: `  # 删除配置
+  nacosctl delete config app.yaml -n public -g DEFAULT_GROUP
+
+  # 删除所有带有 tier=dev 标签的配置
+  nacosctl delete config -n public -l tier=dev
+
+  # 在脚本中批量删除，不询问确认
+  nacosctl delete config -n public -l tier=dev --yes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			if labelSelector != "" {
+				if len(args) > 0 {
+					return errors.New("不能同时指定 dataId 和 -l")
+				}
+
+				items, err := f.selectConfigs(cmd, labelSelector)
+				if err != nil {
+					return err
+				}
+				if len(items) == 0 {
+					return errors.New("没有匹配的配置")
+				}
+				if !yes {
+					confirmed, err := f.confirmDelete(items)
+					if err != nil {
+						return err
+					}
+					if !confirmed {
+						return errors.New("已取消删除")
+					}
+				}
+
+				for _, item := range items {
+					err := f.client.DeleteConfigContext(cmd.Context(), nacos.ConfigDeleteOperation{
+						NacosOperation: &nacos.NacosOperation{
+							Namespace: f.namespace,
+							Group:     item.Group,
+						},
+						DataId: item.DataId,
+					})
+					if err != nil {
+						return fmt.Errorf("删除 %s (%s) 失败: %w", item.DataId, item.Group, err)
+					}
+					fmt.Fprintf(f.Out, "配置已删除: %s (%s)\n", item.DataId, item.Group)
+				}
+				return nil
+			}
 
-此操作会永久删除配置，无法撤销。`,
-	Example: `  # 删除配置
-  nacosctl delete config app.yaml -n public -g DEFAULT_GROUP`,
-	RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errors.New("请指定 dataId")
+			}
 
-		if len(args) == 0 {
-			return errors.New("请指定 dataId")
-		}
+			err := f.client.DeleteConfigContext(cmd.Context(), nacos.ConfigDeleteOperation{
+				NacosOperation: &nacos.NacosOperation{
+					Namespace: f.namespace,
+					Group:     f.group,
+				},
+				DataId: args[0],
+			})
+			if err != nil {
+				return err
+			}
 
-		err := nacosClient.DeleteConfig(nacos.ConfigDeleteOperation{
-			NacosOperation: &nacos.NacosOperation{
-				Namespace: namespace,
-				Group:     group,
-			},
-			DataId: args[0],
-		})
-		if err != nil {
-			return err
-		}
+			fmt.Fprintln(f.Out, "配置已删除")
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "删除标签满足选择器的所有配置")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "使用 -l 批量删除时不询问确认")
+	return cmd
+}
+
+// confirmDelete 列出将要删除的配置并询问确认，只有输入 y 或 yes 时返回 true
+func (f *factory) confirmDelete(items []nacos.NacosPageItem) (bool, error) {
+	for _, item := range items {
+		fmt.Fprintf(f.ErrOut, "  %s (%s)\n", item.DataId, item.Group)
+	}
+	fmt.Fprintf(f.ErrOut, "将删除以上 %d 个配置，是否继续? [y/N] ", len(items))
 
-		fmt.Println("配置已删除")
-		return nil
-	},
+	answer, err := f.lineReader().ReadLine()
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
 }
 
-func init() {
+// newCopyConfigCmd 创建 copy config 命令
+func newCopyConfigCmd(f *factory) *cobra.Command {
+	o := &copyOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "config [dataId...]",
+		Short: "复制配置到其他命名空间或集群",
+		Long: `将配置复制到其他命名空间、分组或集群。
+
+写入前输出目标与源之间的差异预览，描述、标签、应用名等元数据随内容一起复制。
+目标配置已存在且内容或元数据不同时按 --policy 处理：
+  abort     不写入任何配置并报错 (默认)，--dry-run 时列出所有冲突
+  skip      跳过该配置
+  overwrite 覆盖目标配置
 
-	editConfig.Flags().StringVarP(&fileType, "type", "t", "", "配置文件类型 (如: yaml, properties, json)")
+使用 -A 复制命名空间下的所有配置，同时指定 -g 时只复制该分组。`,
+		Example: `  # 复制单个配置到另一个集群
+  nacosctl copy config app.yaml --from-context test -n test --to-context prod --to-namespace prod
 
-	getConfig.Flags().BoolVarP(&getAllConfig, "all", "A", false, "列出命名空间中的所有配置")
+  # 复制整个命名空间，覆盖目标中已存在的配置
+  nacosctl copy config -A -n test --to-namespace prod --policy overwrite
 
-	editCmd.AddCommand(editConfig)
-	getCmd.AddCommand(getConfig)
-	deleteCmd.AddCommand(deleteConfig)
+  # 复制指定分组，只预览不写入
+  nacosctl copy config -A -n test -g PAY_GROUP --to-namespace prod --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policy, err := parsePolicy(o.policy)
+			if err != nil {
+				return err
+			}
+
+			src, err := f.clientFor(o.fromContext)
+			if err != nil {
+				return err
+			}
+			dst, err := f.clientFor(o.toContext)
+			if err != nil {
+				return err
+			}
+
+			if o.fromContext == o.toContext && (o.toNamespace == "" || o.toNamespace == f.namespace) &&
+				(o.toGroup == "" || o.toGroup == f.group) {
+				return errors.New("源和目标相同，请指定 --to-context、--to-namespace 或 --to-group")
+			}
+
+			var keys []nacos.ConfigKey
+			if o.all {
+				operation := nacos.ConfigGetOperation{
+					NacosOperation: &nacos.NacosOperation{
+						Namespace: f.namespace,
+					},
+				}
+				if cmd.Flags().Changed("group") {
+					operation.Group = f.group
+				}
+
+				items, err := src.AllConfigContext(cmd.Context(), operation)
+				if err != nil {
+					return err
+				}
+				for _, item := range items {
+					keys = append(keys, nacos.ConfigKey{Namespace: f.namespace, Group: item.Group, DataId: item.DataId})
+				}
+			} else {
+				if len(args) == 0 {
+					return errors.New("请指定 dataId 或使用 -A 复制所有配置")
+				}
+				for _, dataId := range args {
+					keys = append(keys, nacos.ConfigKey{Namespace: f.namespace, Group: f.group, DataId: dataId})
+				}
+			}
+
+			// 之后的错误（如目标冲突）与参数无关，不输出用法
+			cmd.SilenceUsage = true
+			return o.copyConfigs(cmd.Context(), src, dst, keys, policy)
+		},
+	}
+	cmd.Flags().StringVar(&o.fromContext, "from-context", "", "源上下文 (默认使用环境变量配置的服务器)")
+	cmd.Flags().StringVar(&o.toContext, "to-context", "", "目标上下文 (默认与源相同)")
+	cmd.Flags().StringVar(&o.toNamespace, "to-namespace", "", "目标命名空间 (默认与源相同)")
+	cmd.Flags().StringVar(&o.toGroup, "to-group", "", "目标分组 (默认与源相同)")
+	cmd.Flags().BoolVarP(&o.all, "all", "A", false, "复制命名空间（或 -g 指定分组）下的所有配置")
+	cmd.Flags().StringVar(&o.policy, "policy", "abort", "目标已存在且内容不同时的处理策略 (abort, skip, overwrite)")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "只预览差异，不写入目标")
+	return cmd
 }
 
-func printTable(items []nacos.NacosPageItem) {
+// selectConfigs 列出命名空间中的配置，指定 -g 时只列出该分组，指定 -l 时按标签筛选
+func (f *factory) selectConfigs(cmd *cobra.Command, labelSelector string) ([]nacos.NacosPageItem, error) {
+	operation := nacos.ConfigGetOperation{
+		NacosOperation: &nacos.NacosOperation{
+			Namespace: f.namespace,
+		},
+	}
+	if cmd.Flags().Changed("group") {
+		operation.Group = f.group
+	}
+
+	sel, err := selector.Parse(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.client.SelectConfigContext(cmd.Context(), operation, sel)
+}
+
+func (f *factory) printTable(items []nacos.NacosPageItem, showTags bool) {
 	table := uitable.New()
 	table.MaxColWidth = 50
 
-	table.AddRow("DataID", "GROUP", "NAMESPACE")
+	if showTags {
+		table.AddRow("DataID", "GROUP", "NAMESPACE", "TAGS")
+	} else {
+		table.AddRow("DataID", "GROUP", "NAMESPACE")
+	}
 
 	for _, item := range items {
 		if item.Tenant == "" {
 			item.Tenant = "public"
 		}
-		table.AddRow(item.DataId, item.Group, item.Tenant)
+		if showTags {
+			table.AddRow(item.DataId, item.Group, item.Tenant, item.Tags)
+		} else {
+			table.AddRow(item.DataId, item.Group, item.Tenant)
+		}
+	}
+
+	fmt.Fprintln(f.Out, table)
+}
+
+// watch 监听配置变更并输出每个新版本，ctx 取消（收到 SIGINT/SIGTERM）时停止
+func (f *factory) watch(ctx context.Context, key nacos.ConfigKey, showDiff bool) error {
+	events, err := f.client.Watch(ctx, key)
+	if err != nil {
+		return err
 	}
 
-	fmt.Println(table)
+	// 收到信号时根命令的 ctx 被取消，channel 关闭后正常退出
+	for event := range events {
+		if event.Err != nil {
+			fmt.Fprintln(f.ErrOut, "监听出错:", event.Err)
+			continue
+		}
+
+		now := time.Now().Format("2006-01-02 15:04:05")
+
+		if event.Config == nil {
+			if event.Previous == nil {
+				fmt.Fprintf(f.Out, "# %s %s 配置不存在，等待创建...\n", now, key.DataId)
+			} else {
+				fmt.Fprintf(f.Out, "# %s %s 配置已删除\n", now, key.DataId)
+			}
+			continue
+		}
+
+		fmt.Fprintf(f.Out, "# %s %s md5: %s\n", now, key.DataId, util.Md5ToString(event.Config.Content))
+
+		if showDiff && event.Previous != nil {
+			fmt.Fprint(f.Out, diff.Unified(key.DataId+" (previous)", key.DataId+" (current)",
+				f.redactContent(event.Previous.Content, event.Previous.Type, key.DataId),
+				f.redactContent(event.Config.Content, event.Config.Type, key.DataId)))
+			continue
+		}
+
+		fmt.Fprintln(f.Out, f.redactContent(event.Config.Content, event.Config.Type, key.DataId))
+	}
+	return nil
 }
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"github.com/Talbot3/nacos-cli/pkg/nacos/nacostest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newConfigServer(t *testing.T) *nacostest.Server {
+	server := nacostest.NewServer()
+	t.Cleanup(server.Close)
+
+	server.SetConfig(nacostest.Config{
+		Namespace: "dev",
+		Group:     "DEFAULT_GROUP",
+		DataId:    "app.yaml",
+		Type:      "yaml",
+		Content:   "server:\n  port: 8080\ndb:\n  host: db.dev\n  password: s3cret\n",
+	})
+	server.SetConfig(nacostest.Config{
+		Namespace: "dev",
+		Group:     "PAY_GROUP",
+		DataId:    "pay.properties",
+		Type:      "properties",
+		Content:   "pay.timeout=30\n",
+		Tags:      "team=payments",
+	})
+	return server
+}
+
+func TestGetConfig(t *testing.T) {
+	server := newConfigServer(t)
+
+	// 输出不是终端时同样脱敏
+	out, _, err := runCommand(t, server, "get", "config", "app.yaml", "-n", "dev")
+	require.NoError(t, err)
+	assertGolden(t, "get-config", out)
+
+	out, _, err = runCommand(t, server, "get", "config", "app.yaml", "-n", "dev", "--show-secrets")
+	require.NoError(t, err)
+	assertGolden(t, "get-config-show-secrets", out)
+}
+
+func TestGetConfigAll(t *testing.T) {
+	server := newConfigServer(t)
+
+	out, _, err := runCommand(t, server, "get", "config", "-A", "-n", "dev")
+	require.NoError(t, err)
+	assertGolden(t, "get-config-all", out)
+
+	out, _, err = runCommand(t, server, "get", "config", "-n", "dev", "-l", "team=payments")
+	require.NoError(t, err)
+	assertGolden(t, "get-config-selector", out)
+}
+
+func TestGetConfigNotExist(t *testing.T) {
+	server := newConfigServer(t)
+
+	_, _, err := runCommand(t, server, "get", "config", "missing.yaml", "-n", "dev")
+	assert.Error(t, err)
+}
+
+func TestEditConfig(t *testing.T) {
+	server := newConfigServer(t)
+	setFakeEditor(t, "server:\n  port: 9090\n")
+
+	out, errOut, err := runCommand(t, server, "edit", "config", "app.yaml", "-n", "dev")
+	require.NoError(t, err)
+	assertGolden(t, "edit-config", out)
+	assert.Contains(t, errOut, "Opening file with editor")
+
+	config, ok := server.Config("dev", "DEFAULT_GROUP", "app.yaml")
+	require.True(t, ok)
+	assert.Equal(t, "server:\n  port: 9090\n", config.Content)
+	assert.Equal(t, "yaml", config.Type)
+}
+
+func TestEditConfigUnchanged(t *testing.T) {
+	server := newConfigServer(t)
+	setFakeEditor(t, "pay.timeout=30\n")
+
+	out, _, err := runCommand(t, server, "edit", "config", "pay.properties", "-n", "dev", "-g", "PAY_GROUP")
+	require.NoError(t, err)
+	assertGolden(t, "edit-config-unchanged", out)
+	assert.Len(t, server.History("dev", "PAY_GROUP", "pay.properties"), 1)
+}
+
+func TestDeleteConfig(t *testing.T) {
+	server := newConfigServer(t)
+
+	out, _, err := runCommand(t, server, "delete", "config", "app.yaml", "-n", "dev")
+	require.NoError(t, err)
+	assertGolden(t, "delete-config", out)
+
+	_, ok := server.Config("dev", "DEFAULT_GROUP", "app.yaml")
+	assert.False(t, ok)
+	_, ok = server.Config("dev", "PAY_GROUP", "pay.properties")
+	assert.True(t, ok)
+}
+
+func TestDeleteConfigSelector(t *testing.T) {
+	server := newConfigServer(t)
+
+	out, _, err := runCommand(t, server, "delete", "config", "-n", "dev", "-l", "team=payments", "--yes")
+	require.NoError(t, err)
+	assertGolden(t, "delete-config-selector", out)
+	assert.Len(t, server.Configs(), 1)
+}
+
+func TestDeleteConfigSelectorConfirm(t *testing.T) {
+	server := newConfigServer(t)
+	total := len(server.Configs())
+
+	// 空选择器是错误而不是匹配所有配置
+	for _, sel := range []string{",", " "} {
+		_, _, err := runCommand(t, server, "delete", "config", "-n", "dev", "-l", sel, "--yes")
+		assert.Error(t, err, sel)
+	}
+	assert.Len(t, server.Configs(), total)
+
+	// 未确认时不删除
+	out, errOut, err := runCommandStdin(t, server, "n\n", "delete", "config", "-n", "dev", "-l", "team=payments")
+	require.Error(t, err)
+	assert.NotContains(t, out, "配置已删除")
+	assert.Contains(t, errOut, "pay.properties (PAY_GROUP)")
+	assert.Contains(t, errOut, "将删除以上 1 个配置")
+	assert.Len(t, server.Configs(), total)
+
+	out, _, err = runCommandStdin(t, server, "y\n", "delete", "config", "-n", "dev", "-l", "team=payments")
+	require.NoError(t, err)
+	assertGolden(t, "delete-config-selector", out)
+	assert.Len(t, server.Configs(), total-1)
+}
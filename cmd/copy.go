@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/diff"
+	"github.com/Talbot3/nacos-cli/pkg/nacos"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// copyOptions copy config 命令的参数
+type copyOptions struct {
+	*factory
+
+	fromContext string // 源上下文
+	toContext   string // 目标上下文
+	toNamespace string // 目标命名空间
+	toGroup     string // 目标分组
+	all         bool   // 复制命名空间（或分组）下的所有配置
+	policy      string // 目标已存在且内容不同时的处理策略
+	dryRun      bool   // 只预览不写入
+}
+
+// newCopyCmd 创建 copy 命令
+func newCopyCmd(f *factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "copy",
+		Short: "在命名空间或集群之间复制配置",
+		Long: `在命名空间或 Nacos 集群之间复制配置。
+
+源和目标可以分别通过 --from-context 和 --to-context 指定不同的服务器，
+上下文定义在 ~/.nacosctl/config.yaml（可通过 NACOSCTL_CONFIG 环境变量覆盖）：
+
+  contexts:
+    - name: test
+      addr: http://test-nacos:8848/nacos
+      username: nacos
+      password: nacos
+
+未指定上下文时使用 NACOS_ADDR 等环境变量配置的服务器。`,
+		Example: `  # 将 test 集群的配置提升到 prod 集群
+  nacosctl copy config app.yaml --from-context test -n test --to-context prod --to-namespace prod
+
+  # 预览整个命名空间的复制结果
+  nacosctl copy config -A -n test --to-namespace prod --dry-run`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newCopyConfigCmd(f))
+	return cmd
+}
+
+// copyResult 复制结果统计
+type copyResult struct {
+	created, updated, unchanged, skipped, conflicted int
+}
+
+// copyPlan 一个配置的复制计划
+type copyPlan struct {
+	source, target nacos.ConfigKey
+	config         *nacos.NacosConfigDetail // 源配置
+	exists         bool                     // 目标配置已存在
+	contentChanged bool                     // 内容不同
+	changes        string                   // 内容差异，敏感值已隐藏
+	metadata       []string                 // 不同的元数据字段
+}
+
+// changed 判断目标与源的内容或元数据是否不同
+func (p *copyPlan) changed() bool {
+	return p.contentChanged || len(p.metadata) > 0
+}
+
+// copyConfigs 将源命名空间中的配置复制到目标命名空间。
+// 先读取所有源和目标配置，--policy abort 时任一目标存在冲突则不写入任何配置
+func (o *copyOptions) copyConfigs(ctx context.Context, src, dst *nacos.Client, keys []nacos.ConfigKey, policy nacos.ImportPolicy) error {
+	plans := make([]*copyPlan, 0, len(keys))
+	var conflicts []string
+	for _, key := range keys {
+		target := nacos.ConfigKey{
+			Namespace: o.toNamespace,
+			Group:     o.toGroup,
+			DataId:    key.DataId,
+		}
+		if target.Namespace == "" {
+			target.Namespace = key.Namespace
+		}
+		if target.Group == "" {
+			target.Group = key.Group
+		}
+
+		plan, err := o.planCopy(ctx, src, dst, key, target)
+		if err != nil {
+			return err
+		}
+		plans = append(plans, plan)
+		if plan.exists && plan.changed() {
+			conflicts = append(conflicts, target.String())
+		}
+	}
+
+	abort := policy == nacos.ImportPolicyAbort && len(conflicts) > 0
+	if abort && !o.dryRun {
+		for _, plan := range plans {
+			if plan.exists && plan.changed() {
+				o.printChanges(plan)
+			}
+		}
+		return fmt.Errorf("目标配置 %s 已存在且内容不同，未写入任何配置，使用 --policy overwrite 覆盖或 --policy skip 跳过", strings.Join(conflicts, ", "))
+	}
+
+	result := copyResult{}
+	for _, plan := range plans {
+		if err := o.copyConfig(ctx, dst, plan, policy, &result); err != nil {
+			return err
+		}
+	}
+
+	action := "复制完成"
+	if o.dryRun {
+		action = "预览完成 (dry-run，未写入)"
+	}
+	fmt.Fprintf(o.Out, "%s: 新建 %d, 更新 %d, 未变化 %d, 跳过 %d", action, result.created, result.updated, result.unchanged, result.skipped)
+	if result.conflicted > 0 {
+		fmt.Fprintf(o.Out, ", 冲突 %d", result.conflicted)
+	}
+	fmt.Fprintln(o.Out)
+
+	if abort {
+		return fmt.Errorf("目标配置 %s 已存在且内容不同，复制时将终止，使用 --policy overwrite 覆盖或 --policy skip 跳过", strings.Join(conflicts, ", "))
+	}
+	return nil
+}
+
+// planCopy 读取源和目标配置，比较内容和元数据
+func (o *copyOptions) planCopy(ctx context.Context, src, dst *nacos.Client, source, target nacos.ConfigKey) (*copyPlan, error) {
+	sourceConfig, err := src.DetailContext(ctx, getOperation(source))
+	if err != nil {
+		return nil, fmt.Errorf("获取源配置 %s 失败: %w", source, err)
+	}
+
+	targetConfig, err := dst.DetailContext(ctx, getOperation(target))
+	if err != nil && !errors.Is(err, nacos.ErrConfigNotExist) {
+		return nil, fmt.Errorf("获取目标配置 %s 失败: %w", target, err)
+	}
+
+	plan := &copyPlan{source: source, target: target, config: sourceConfig, exists: targetConfig != nil}
+	previous := ""
+	if targetConfig != nil {
+		previous = targetConfig.Content
+		plan.metadata = metadataChanges(sourceConfig, targetConfig)
+	}
+	plan.contentChanged = targetConfig == nil || previous != sourceConfig.Content
+	plan.changes = diff.Unified(target.String(), source.String(),
+		o.redactContent(previous, sourceConfig.Type, target.DataId),
+		o.redactContent(sourceConfig.Content, sourceConfig.Type, source.DataId))
+	return plan, nil
+}
+
+// metadataChanges 返回源和目标之间不同的元数据字段
+func metadataChanges(source, target *nacos.NacosConfigDetail) []string {
+	var fields []string
+	for _, field := range []struct {
+		name           string
+		source, target string
+	}{
+		{"type", source.Type, target.Type},
+		{"desc", source.Desc, target.Desc},
+		{"tags", strings.Join(nacos.ParseTags(source.ConfigTags), ","), strings.Join(nacos.ParseTags(target.ConfigTags), ",")},
+		{"appName", source.AppName, target.AppName},
+		{"use", source.Use, target.Use},
+		{"effect", source.Effect, target.Effect},
+		{"schema", source.Schema, target.Schema},
+	} {
+		if field.source != field.target {
+			fields = append(fields, field.name)
+		}
+	}
+	return fields
+}
+
+func (o *copyOptions) printChanges(plan *copyPlan) {
+	fmt.Fprint(o.Out, plan.changes)
+	if plan.contentChanged && plan.changes == "" {
+		fmt.Fprintf(o.Out, "%s 敏感值不同 (使用 --show-secrets 查看)\n", plan.target)
+	}
+	if len(plan.metadata) > 0 {
+		fmt.Fprintf(o.Out, "%s 元数据不同: %s\n", plan.target, strings.Join(plan.metadata, ", "))
+	}
+}
+
+func (o *copyOptions) copyConfig(ctx context.Context, dst *nacos.Client, plan *copyPlan, policy nacos.ImportPolicy, result *copyResult) error {
+	target := plan.target
+	if plan.exists && !plan.changed() {
+		fmt.Fprintf(o.Out, "%s 未变化\n", target)
+		result.unchanged++
+		return nil
+	}
+
+	o.printChanges(plan)
+
+	if plan.exists {
+		switch policy {
+		case nacos.ImportPolicySkip:
+			fmt.Fprintf(o.Out, "%s 已存在，跳过\n", target)
+			result.skipped++
+			return nil
+		case nacos.ImportPolicyAbort:
+			// 只有 --dry-run 会执行到这里
+			fmt.Fprintf(o.Out, "%s 已存在且内容不同\n", target)
+			result.conflicted++
+			return nil
+		}
+	}
+
+	if !o.dryRun {
+		if err := dst.EditContext(ctx, nacos.ConfigEditOperation{
+			NacosOperation: &nacos.NacosOperation{
+				Namespace: target.Namespace,
+				Group:     target.Group,
+			},
+			ConfigMetadata: plan.config.Metadata(),
+			DataId:         target.DataId,
+			Content:        plan.config.Content,
+			Type:           plan.config.Type,
+
+			// 未配置密钥时源配置未解密，密文和数据密钥原样复制
+			EncryptedDataKey: plan.config.EncryptedDataKey,
+		}); err != nil {
+			return fmt.Errorf("写入目标配置 %s 失败: %w", target, err)
+		}
+	}
+
+	created, updated := "已新建", "已更新"
+	if o.dryRun {
+		created, updated = "将新建", "将更新"
+	}
+	if !plan.exists {
+		fmt.Fprintf(o.Out, "%s %s\n", target, created)
+		result.created++
+	} else {
+		fmt.Fprintf(o.Out, "%s %s\n", target, updated)
+		result.updated++
+	}
+	return nil
+}
+
+func getOperation(key nacos.ConfigKey) nacos.ConfigGetOperation {
+	return nacos.ConfigGetOperation{
+		NacosOperation: &nacos.NacosOperation{
+			Namespace: key.Namespace,
+			Group:     key.Group,
+		},
+		DataId: key.DataId,
+	}
+}
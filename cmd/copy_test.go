@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"github.com/Talbot3/nacos-cli/pkg/nacos/nacostest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyConfig(t *testing.T) {
+	server := newConfigServer(t)
+	server.SetConfig(nacostest.Config{
+		Namespace: "dev",
+		Group:     "PAY_GROUP",
+		DataId:    "pay.properties",
+		Type:      "properties",
+		Content:   "pay.timeout=30\n",
+		Tags:      "team=payments",
+		Desc:      "支付配置",
+		AppName:   "pay",
+	})
+
+	out, _, err := runCommand(t, server, "copy", "config", "pay.properties", "-n", "dev", "-g", "PAY_GROUP", "--to-namespace", "prod")
+	require.NoError(t, err)
+	assertGolden(t, "copy-config", out)
+
+	// 元数据随内容一起复制
+	config, ok := server.Config("prod", "PAY_GROUP", "pay.properties")
+	require.True(t, ok)
+	assert.Equal(t, "pay.timeout=30\n", config.Content)
+	assert.Equal(t, "properties", config.Type)
+	assert.Equal(t, "支付配置", config.Desc)
+	assert.Equal(t, "team=payments", config.Tags)
+	assert.Equal(t, "pay", config.AppName)
+
+	out, _, err = runCommand(t, server, "copy", "config", "pay.properties", "-n", "dev", "-g", "PAY_GROUP", "--to-namespace", "prod")
+	require.NoError(t, err)
+	assert.Contains(t, out, "prod/PAY_GROUP/pay.properties 未变化")
+}
+
+func TestCopyConfigAbortWritesNothing(t *testing.T) {
+	server := newConfigServer(t)
+	server.SetConfig(nacostest.Config{Namespace: "prod", Group: "PAY_GROUP", DataId: "pay.properties", Type: "properties", Content: "pay.timeout=60\n"})
+
+	_, _, err := runCommand(t, server, "copy", "config", "-A", "-n", "dev", "--to-namespace", "prod")
+	require.ErrorContains(t, err, "未写入任何配置")
+
+	// 其他没有冲突的配置也不写入
+	_, ok := server.Config("prod", "DEFAULT_GROUP", "app.yaml")
+	assert.False(t, ok)
+	config, _ := server.Config("prod", "PAY_GROUP", "pay.properties")
+	assert.Equal(t, "pay.timeout=60\n", config.Content)
+}
+
+func TestCopyConfigDryRunReportsAll(t *testing.T) {
+	server := newConfigServer(t)
+	server.SetConfig(nacostest.Config{Namespace: "prod", Group: "PAY_GROUP", DataId: "pay.properties", Type: "properties", Content: "pay.timeout=60\n", Tags: "team=payments"})
+
+	out, _, err := runCommand(t, server, "copy", "config", "-A", "-n", "dev", "--to-namespace", "prod", "--dry-run")
+	assert.ErrorContains(t, err, "复制时将终止")
+	assertGolden(t, "copy-config-dry-run", out)
+	assert.Len(t, server.Configs(), 3)
+
+	// dry-run 只报告将要进行的写入
+	out, _, err = runCommand(t, server, "copy", "config", "-A", "-n", "dev", "--to-namespace", "prod", "--dry-run", "--policy", "overwrite")
+	require.NoError(t, err)
+	assert.Contains(t, out, "prod/DEFAULT_GROUP/app.yaml 将新建")
+	assert.Contains(t, out, "prod/PAY_GROUP/pay.properties 将更新")
+	assert.NotContains(t, out, "已新建")
+	assert.NotContains(t, out, "已更新")
+	config, _ := server.Config("prod", "PAY_GROUP", "pay.properties")
+	assert.Equal(t, "pay.timeout=60\n", config.Content)
+	assert.Len(t, server.Configs(), 3)
+}
+
+func TestCopyConfigPolicy(t *testing.T) {
+	tests := []struct {
+		policy  string
+		content string
+		summary string
+	}{
+		{policy: "skip", content: "pay.timeout=60\n", summary: "复制完成: 新建 1, 更新 0, 未变化 0, 跳过 1"},
+		{policy: "overwrite", content: "pay.timeout=30\n", summary: "复制完成: 新建 1, 更新 1, 未变化 0, 跳过 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			server := newConfigServer(t)
+			server.SetConfig(nacostest.Config{Namespace: "prod", Group: "PAY_GROUP", DataId: "pay.properties", Type: "properties", Content: "pay.timeout=60\n"})
+
+			out, _, err := runCommand(t, server, "copy", "config", "-A", "-n", "dev", "--to-namespace", "prod", "--policy", tt.policy)
+			require.NoError(t, err)
+			assert.Contains(t, out, tt.summary)
+
+			config, _ := server.Config("prod", "PAY_GROUP", "pay.properties")
+			assert.Equal(t, tt.content, config.Content)
+			_, ok := server.Config("prod", "DEFAULT_GROUP", "app.yaml")
+			assert.True(t, ok)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/content"
+	"github.com/Talbot3/nacos-cli/pkg/encrypt"
+	"github.com/Talbot3/nacos-cli/pkg/nacos"
+	"github.com/Talbot3/nacos-cli/pkg/term"
+	"io"
+	"os"
+	"time"
+)
+
+// IOStreams 命令的标准输入、标准输出和标准错误，命令只通过它读写，测试时替换为缓冲区
+type IOStreams struct {
+	In     io.Reader
+	Out    io.Writer
+	ErrOut io.Writer
+}
+
+// StdStreams 返回进程的标准输入、标准输出和标准错误
+func StdStreams() IOStreams {
+	return IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+}
+
+// ClientFactory 创建访问 Nacos 的客户端。contextName 为空时为 NACOS_* 环境变量配置的服务器，
+// 否则为 ~/.nacosctl/config.yaml 中定义的上下文（copy、compare 使用）。
+// 命令再按 -u、-p、--transport、--retries 等参数和保存的凭据调整返回的客户端
+type ClientFactory func(contextName string) (*nacos.Client, error)
+
+// DefaultClientFactory 从环境变量或上下文配置创建客户端
+func DefaultClientFactory(contextName string) (*nacos.Client, error) {
+	if contextName == "" {
+		return nacos.NewDefaultClient(), nil
+	}
+	return nacos.NewContextClient(contextName)
+}
+
+// factory 一个命令树共享的输入输出、全局参数和客户端
+type factory struct {
+	IOStreams
+	newClient ClientFactory
+
+	namespace            string
+	group                string
+	username             string
+	password             string
+	credentialHelper     string        // 凭据 helper 名称
+	encryptionKeyFile    string        // cipher- 配置的加密主密钥文件
+	serverSideEncryption bool          // 未提供密钥时 cipher- 配置原样发布，由服务端加密插件加密
+	transport            string        // 传输方式，为空时使用环境变量或上下文中的配置
+	requestTimeout       time.Duration // 单次请求（包括重试）的超时时间
+	retries              int           // 请求失败时的最大重试次数
+	retryTimeout         time.Duration // 包括重试在内的最长时间
+	verbosity            int           // 日志详细级别，日志输出到 ErrOut
+	showSecrets          bool          // 输出中显示敏感值明文
+	sensitiveKeys        []string      // 视为敏感的键名模式
+
+	stdin       *term.Reader        // 按行读取 In，同一命令树的多次读取共享缓冲
+	client      *nacos.Client       // 默认服务器的客户端，命令执行前创建
+	clients     []*nacos.Client     // clientFor 创建的所有客户端，命令结束时关闭
+	keyProvider encrypt.KeyProvider // cipher- 配置的加密密钥
+	redactor    *content.Redactor   // 为 nil 时不脱敏
+}
+
+// lineReader 返回按行读取 In 的 Reader，管道输入的多行内容在多次读取之间不会丢失
+func (f *factory) lineReader() *term.Reader {
+	if f.stdin == nil {
+		f.stdin = term.NewReader(f.In)
+	}
+	return f.stdin
+}
+
+// init 命令执行前根据解析后的全局参数初始化密钥、客户端和脱敏器
+func (f *factory) init() error {
+	keyProvider, err := encrypt.LoadKey(f.encryptionKeyFile)
+	if err != nil {
+		return fmt.Errorf("加载加密密钥失败: %w", err)
+	}
+	f.keyProvider = keyProvider
+
+	client, err := f.clientFor("")
+	if err != nil {
+		return err
+	}
+	f.client = client
+	return f.initRedactor()
+}
+
+// clientFor 根据上下文名称获取客户端，未指定时返回默认客户端。
+// 命令行参数优先级高于环境变量和上下文配置，-u、-p 只作用于默认客户端
+func (f *factory) clientFor(contextName string) (*nacos.Client, error) {
+	if contextName == "" && f.client != nil {
+		return f.client, nil
+	}
+
+	client, err := f.newClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	f.track(client)
+
+	if contextName == "" {
+		if f.username != "" {
+			client.Config.Username = f.username
+		}
+		if f.password != "" {
+			client.Config.Password = f.password
+		}
+	}
+	if f.transport != "" {
+		client.Config.Transport = f.transport
+	}
+	switch client.Config.Transport {
+	case "", nacos.TransportHTTP, nacos.TransportGRPC:
+	default:
+		return nil, fmt.Errorf("不支持的传输方式: %s (可选: http, grpc)", client.Config.Transport)
+	}
+
+	if err := f.loadCredential(client.Config); err != nil {
+		return nil, err
+	}
+	client.KeyProvider = f.keyProvider
+	client.ServerSideEncryption = f.serverSideEncryption
+	client.RetryPolicy = f.retryPolicy()
+	client.RequestTimeout = f.requestTimeout
+	f.initLogging(client)
+	return client, nil
+}
+
+// track 记录创建的客户端，同一客户端只记录一次
+func (f *factory) track(client *nacos.Client) {
+	for _, c := range f.clients {
+		if c == client {
+			return
+		}
+	}
+	f.clients = append(f.clients, client)
+}
+
+// close 关闭 clientFor 创建的所有客户端（包括 copy、compare 按上下文创建的客户端），释放 gRPC 连接和监听
+func (f *factory) close() error {
+	var errs []error
+	for _, client := range f.clients {
+		if err := client.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	f.clients = nil
+	return errors.Join(errs...)
+}
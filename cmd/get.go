@@ -4,18 +4,19 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// getCmd represents the get command
-var getCmd = &cobra.Command{
-	Use:   "get",
-	Short: "获取 Nacos 配置",
-	Long: `从 Nacos 服务器获取配置。
+// newGetCmd 创建 get 命令
+func newGetCmd(f *factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "获取 Nacos 配置",
+		Long: `从 Nacos 服务器获取配置。
 
 可以指定 dataId 获取单个配置，或使用 --all 参数列出命名空间中的所有配置。`,
-	Example: `  # 获取指定配置
+		Example: `  # 获取指定配置
   nacosctl get config app.yaml -n public -g DEFAULT_GROUP
 
   # 获取配置并保存到文件
-  nacosctl get config app.yaml -n public > app.yaml
+  nacosctl get config app.yaml -n public --show-secrets > app.yaml
 
   # 列出命名空间中的所有配置
   nacosctl get config -A -n public
@@ -28,11 +29,10 @@ var getCmd = &cobra.Command{
   export NACOS_USERNAME="nacos"
   export NACOS_PASSWORD="nacos"
   nacosctl get config app.yaml -n public`,
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
-	},
-}
-
-func init() {
-	rootCmd.AddCommand(getCmd)
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+	cmd.AddCommand(newGetConfigCmd(f))
+	return cmd
 }
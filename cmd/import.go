@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/nacos"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// newImportCmd 创建 import 命令
+func newImportCmd(f *factory) *cobra.Command {
+	var importPolicy string // 同名配置处理策略
+
+	cmd := &cobra.Command{
+		Use:   "import <backup.zip|dir>",
+		Short: "将导出的配置导入命名空间",
+		Long: `将 nacosctl export 或 Nacos 控制台导出的配置导入到指定命名空间。
+
+支持 zip 文件或 export 展开的目录。遇到同名配置时按 --policy 处理：
+  abort     终止导入 (默认)
+  skip      跳过已存在的配置
+  overwrite 覆盖已存在的配置`,
+		Example: `  # 导入到 prod 命名空间，覆盖已存在的配置
+  nacosctl import backup.zip -n prod --policy overwrite
+
+  # 从目录导入，跳过已存在的配置
+  nacosctl import ./backup/ -n prod --policy skip`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policy, err := parsePolicy(importPolicy)
+			if err != nil {
+				return err
+			}
+
+			items, err := readArchive(args[0])
+			if err != nil {
+				return err
+			}
+			if len(items) == 0 {
+				return errors.New("没有可导入的配置")
+			}
+
+			result, err := f.client.ImportContext(cmd.Context(), nacos.ConfigImportOperation{
+				NacosOperation: &nacos.NacosOperation{
+					Namespace: f.namespace,
+				},
+				Items:  items,
+				Policy: policy,
+			})
+			if result != nil {
+				printImportResult(f.Out, result)
+			}
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&importPolicy, "policy", "abort", "同名配置处理策略 (abort, skip, overwrite)")
+	return cmd
+}
+
+// parsePolicy 解析同名配置处理策略
+func parsePolicy(s string) (nacos.ImportPolicy, error) {
+	policy := nacos.ImportPolicy(strings.ToUpper(s))
+	switch policy {
+	case nacos.ImportPolicyAbort, nacos.ImportPolicySkip, nacos.ImportPolicyOverwrite:
+		return policy, nil
+	}
+	return "", fmt.Errorf("不支持的策略: %s (可选: abort, skip, overwrite)", s)
+}
+
+// readArchive 读取 zip 文件或导出目录
+func readArchive(source string) ([]nacos.ConfigArchiveItem, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nacos.ReadConfigDir(source)
+	}
+
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, err
+	}
+	return nacos.ReadConfigArchive(data)
+}
+
+func printImportResult(out io.Writer, result *nacos.ImportResult) {
+	fmt.Fprintf(out, "导入完成: 成功 %d, 跳过 %d, 失败 %d\n", result.SuccCount, result.SkipCount, len(result.FailData))
+	for _, item := range result.SkipData {
+		fmt.Fprintf(out, "  跳过: %s (%s)\n", item.DataId, item.Group)
+	}
+	for _, item := range result.FailData {
+		fmt.Fprintf(out, "  失败: %s (%s)\n", item.DataId, item.Group)
+	}
+}
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/nacos"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// errConflictMetadata 读取配置后、发布元数据前配置内容被他人修改
+var errConflictMetadata = errors.New("配置在更新期间已被他人修改，元数据未更新，请重试")
+
+// metadataFlags 配置元数据参数
+type metadataFlags struct {
+	desc    string   // 描述
+	tags    []string // 标签
+	appName string   // 所属应用
+	use     string   // 用途
+	effect  string   // 影响
+	schema  string   // 约束
+}
+
+// newLabelCmd 创建 label 命令
+func newLabelCmd(f *factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "label",
+		Short: "更新配置标签",
+		Long: `更新配置的标签 (config_tags)，配置内容保持不变。
+
+标签以 key=value 形式保存，key- 表示删除该 key 的标签。`,
+		Example: `  # 添加或覆盖标签
+  nacosctl label config app.yaml team=payments tier=prod -n public
+
+  # 删除标签
+  nacosctl label config app.yaml tier- -n public`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newLabelConfigCmd(f))
+	return cmd
+}
+
+// newAnnotateCmd 创建 annotate 命令
+func newAnnotateCmd(f *factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "annotate",
+		Short: "更新配置描述等元数据",
+		Long: `更新配置的描述、所属应用、用途、影响、约束等元数据，配置内容保持不变。
+
+只修改显式指定的字段，未指定的字段保留服务器上的值。`,
+		Example: `  # 更新描述和所属应用
+  nacosctl annotate config app.yaml -n public --desc "订单服务主配置" --app-name orders`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newAnnotateConfigCmd(f))
+	return cmd
+}
+
+// newLabelConfigCmd 创建 label config 命令
+func newLabelConfigCmd(f *factory) *cobra.Command {
+	return &cobra.Command{
+		Use:     "config <dataId> <key=value|key->...",
+		Short:   "添加、覆盖或删除配置标签",
+		Example: `  nacosctl label config app.yaml team=payments tier- -n public`,
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			operation := nacos.ConfigGetOperation{
+				NacosOperation: &nacos.NacosOperation{
+					Namespace: f.namespace,
+					Group:     f.group,
+				},
+				DataId: args[0],
+			}
+
+			current, err := f.client.DetailContext(cmd.Context(), operation)
+			if err != nil {
+				return err
+			}
+
+			tags, err := applyLabels(nacos.ParseTags(current.ConfigTags), args[1:])
+			if err != nil {
+				return err
+			}
+
+			err = f.client.UpdateMetadataContext(cmd.Context(), nacos.ConfigMetadataOperation{
+				NacosOperation: operation.NacosOperation,
+				ConfigMetadata: nacos.ConfigMetadata{Tags: tags},
+				DataId:         operation.DataId,
+			})
+			if errors.Is(err, nacos.ErrConflict) {
+				return errConflictMetadata
+			}
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(f.Out, "标签已更新:", strings.Join(tags, ","))
+			return nil
+		},
+	}
+}
+
+// newAnnotateConfigCmd 创建 annotate config 命令
+func newAnnotateConfigCmd(f *factory) *cobra.Command {
+	var metadata metadataFlags
+
+	cmd := &cobra.Command{
+		Use:     "config <dataId>",
+		Short:   "更新配置描述等元数据",
+		Example: `  nacosctl annotate config app.yaml -n public --desc "订单服务主配置"`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			changed := false
+			for _, name := range []string{"desc", "app-name", "use", "effect", "schema"} {
+				changed = changed || cmd.Flags().Changed(name)
+			}
+			if !changed {
+				return errors.New("请至少指定一个元数据字段")
+			}
+
+			err := f.client.UpdateMetadataContext(cmd.Context(), nacos.ConfigMetadataOperation{
+				NacosOperation: &nacos.NacosOperation{
+					Namespace: f.namespace,
+					Group:     f.group,
+				},
+				ConfigMetadata: metadata.metadata(cmd),
+				DataId:         args[0],
+			})
+			if errors.Is(err, nacos.ErrConflict) {
+				return errConflictMetadata
+			}
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(f.Out, "元数据已更新")
+			return nil
+		},
+	}
+	metadata.add(cmd, false)
+	return cmd
+}
+
+// add 注册元数据参数，withTags 为 true 时同时注册 --tags
+func (m *metadataFlags) add(cmd *cobra.Command, withTags bool) {
+	cmd.Flags().StringVar(&m.desc, "desc", "", "配置描述 (未指定时保留服务器上的值)")
+	cmd.Flags().StringVar(&m.appName, "app-name", "", "所属应用 (未指定时保留服务器上的值)")
+	cmd.Flags().StringVar(&m.use, "use", "", "用途 (未指定时保留服务器上的值)")
+	cmd.Flags().StringVar(&m.effect, "effect", "", "影响 (未指定时保留服务器上的值)")
+	cmd.Flags().StringVar(&m.schema, "schema", "", "约束 (未指定时保留服务器上的值)")
+	if withTags {
+		cmd.Flags().StringSliceVar(&m.tags, "tags", nil, "配置标签，逗号分隔 (未指定时保留服务器上的值)")
+	}
+}
+
+// metadata 只收集显式指定的元数据参数，其余字段为 nil 以保留服务器上的值
+func (m *metadataFlags) metadata(cmd *cobra.Command) nacos.ConfigMetadata {
+	metadata := nacos.ConfigMetadata{}
+	flags := cmd.Flags()
+
+	if flags.Changed("desc") {
+		metadata.Desc = &m.desc
+	}
+	if flags.Changed("app-name") {
+		metadata.AppName = &m.appName
+	}
+	if flags.Changed("use") {
+		metadata.Use = &m.use
+	}
+	if flags.Changed("effect") {
+		metadata.Effect = &m.effect
+	}
+	if flags.Changed("schema") {
+		metadata.Schema = &m.schema
+	}
+	if flags.Lookup("tags") != nil && flags.Changed("tags") {
+		metadata.Tags = append([]string{}, m.tags...)
+	}
+	return metadata
+}
+
+// applyLabels 按 key=value / key- 更新标签列表
+func applyLabels(tags []string, changes []string) ([]string, error) {
+	for _, change := range changes {
+		var key, tag string
+		switch {
+		case strings.HasSuffix(change, "-") && !strings.Contains(change, "="):
+			key = strings.TrimSuffix(change, "-")
+		case strings.Contains(change, "="):
+			key, _, _ = strings.Cut(change, "=")
+			tag = change
+		default:
+			return nil, fmt.Errorf("无效的标签 %q，格式应为 key=value 或 key-", change)
+		}
+		if key == "" {
+			return nil, fmt.Errorf("无效的标签 %q，key 不能为空", change)
+		}
+
+		kept := make([]string, 0, len(tags)+1)
+		for _, existing := range tags {
+			existingKey, _, _ := strings.Cut(existing, "=")
+			if existingKey != key {
+				kept = append(kept, existing)
+			}
+		}
+		if tag != "" {
+			kept = append(kept, tag)
+		}
+		tags = kept
+	}
+	return tags, nil
+}
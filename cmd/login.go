@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/credential"
+	"github.com/Talbot3/nacos-cli/pkg/nacos"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+)
+
+// newLoginCmd 创建 login 命令
+func newLoginCmd(f *factory) *cobra.Command {
+	var (
+		passwordStdin bool // 从标准输入读取密码
+		tokenOnly     bool // 只缓存 token，不保存密码
+	)
+
+	cmd := &cobra.Command{
+		Use:   "login [server]",
+		Short: "登录 Nacos 服务器并保存凭据",
+		Long: `登录 Nacos 服务器，并按服务器地址保存凭据，之后的命令无需再提供密码。
+
+server 默认为 NACOS_ADDR。未指定 -p 和 --password-stdin 时在终端中输入密码（不回显），
+避免密码出现在 shell 历史和进程列表中。
+
+凭据默认保存在加密文件 ~/.nacosctl/credentials.enc 中，密钥为自动生成的
+~/.nacosctl/credentials.key，也可以通过 NACOS_CREDENTIAL_KEY 指定 base64 编码的密钥。
+密钥文件与加密文件在同一目录，这只能避免密码以明文出现在磁盘上，能读取 ~/.nacosctl 的人
+同样能解密；需要真正的保护时使用系统钥匙串（--credential-helper）。
+指定 --credential-helper（或 NACOS_CREDENTIAL_HELPER）时交由 docker-credential-<name> 程序保存，
+可以直接使用 osxkeychain、wincred、secretservice、pass 等 docker credential helper。
+
+使用 --token-only 时只保存用户名和 token，token 过期后需要重新登录。`,
+		Example: `  # 交互式输入密码
+  nacosctl login http://nacos:8848/nacos -u nacos
+
+  # 在 CI 中从标准输入读取密码
+  echo "$NACOS_PASSWORD" | nacosctl login -u nacos --password-stdin
+
+  # 使用系统钥匙串保存凭据
+  nacosctl login -u nacos --credential-helper osxkeychain
+
+  # 只缓存 token，不保存密码
+  nacosctl login -u nacos --token-only`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := f.newClient("")
+			if err != nil {
+				return err
+			}
+			f.initLogging(client)
+			config := client.Config
+			if len(args) > 0 {
+				config.Addr = args[0]
+			}
+			if f.username != "" {
+				config.Username = f.username
+			}
+
+			if config.Username == "" {
+				fmt.Fprint(f.ErrOut, "Username: ")
+				name, err := f.lineReader().ReadLine()
+				if err != nil {
+					return err
+				}
+				config.Username = strings.TrimSpace(name)
+			}
+
+			secret, err := f.readLoginPassword(passwordStdin)
+			if err != nil {
+				return err
+			}
+			config.Password = secret
+			if config.Password == "" {
+				return errors.New("密码不能为空")
+			}
+
+			auth := &nacos.PasswordAuthenticator{Config: config, HTTPClient: client.HTTPClient, Logger: client.Logger}
+			if _, err := auth.Refresh(cmd.Context()); err != nil {
+				return err
+			}
+
+			store, err := credential.NewStore(f.credentialHelper)
+			if err != nil {
+				return err
+			}
+
+			stored := &credential.Credential{
+				ServerURL: config.Addr,
+				Username:  config.Username,
+				Secret:    config.Password,
+			}
+			if tokenOnly {
+				stored.Secret = ""
+			}
+			if err := store.Store(stored); err != nil {
+				return fmt.Errorf("保存凭据失败: %w", err)
+			}
+
+			fmt.Fprintf(f.Out, "登录成功: %s@%s\n", config.Username, config.Addr)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&passwordStdin, "password-stdin", false, "从标准输入读取密码")
+	cmd.Flags().BoolVar(&tokenOnly, "token-only", false, "只缓存 token，不保存密码")
+	return cmd
+}
+
+// newLogoutCmd 创建 logout 命令
+func newLogoutCmd(f *factory) *cobra.Command {
+	var logoutAll bool // 退出所有服务器
+
+	cmd := &cobra.Command{
+		Use:   "logout [server]",
+		Short: "退出登录，清除保存的凭据和 token",
+		Long: `清除指定服务器上当前用户缓存的 token 和保存的凭据，其他用户和服务器不受影响。
+
+server 默认为 NACOS_ADDR，用户默认为 -u、NACOS_USERNAME 或保存凭据中的用户。
+使用 --all 清除所有服务器、所有用户的 token 和凭据。`,
+		Example: `  # 退出当前服务器
+  nacosctl logout
+
+  # 退出指定服务器
+  nacosctl logout http://nacos:8848/nacos -u nacos
+
+  # 清除所有凭据和 token
+  nacosctl logout --all`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := credential.NewStore(f.credentialHelper)
+			if err != nil {
+				return err
+			}
+
+			if logoutAll {
+				if err := nacos.ClearAllAccessTokens(); err != nil {
+					return err
+				}
+				servers, err := store.List()
+				if err != nil {
+					return err
+				}
+				for server := range servers {
+					if err := store.Erase(server); err != nil {
+						return err
+					}
+				}
+				fmt.Fprintln(f.Out, "已清除所有凭据和 token")
+				return nil
+			}
+
+			config := f.client.Config
+			if len(args) > 0 {
+				client, err := f.newClient("")
+				if err != nil {
+					return err
+				}
+				config = client.Config
+				config.Addr = args[0]
+				config.Username = f.username
+				if err := f.loadCredential(config); err != nil {
+					return err
+				}
+			}
+			if config.Username == "" {
+				return errors.New("未登录")
+			}
+
+			if err := nacos.ClearAccessToken(config.Addr, config.Username); err != nil {
+				return err
+			}
+			stored, err := store.Get(config.Addr)
+			if err == nil && stored.Username == config.Username {
+				if err := store.Erase(config.Addr); err != nil {
+					return err
+				}
+			} else if err != nil && !errors.Is(err, credential.ErrNotFound) {
+				return err
+			}
+
+			fmt.Fprintf(f.Out, "已退出: %s@%s\n", config.Username, config.Addr)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&logoutAll, "all", false, "清除所有服务器、所有用户的 token 和凭据")
+	return cmd
+}
+
+// newWhoamiCmd 创建 whoami 命令
+func newWhoamiCmd(f *factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "whoami",
+		Short: "显示当前登录的用户、服务器和 token 有效期",
+		Example: `  nacosctl whoami
+  nacosctl whoami -u admin`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := f.client
+			config := client.Config
+			switch {
+			case config.Token != "":
+				fmt.Fprintf(f.Out, "使用 NACOS_TOKEN 访问 %s\n", config.Addr)
+				return nil
+			case config.AccessKey != "" && config.SecretKey != "":
+				fmt.Fprintf(f.Out, "使用 AccessKey %s 签名访问 %s\n", config.AccessKey, config.Addr)
+				return nil
+			case config.IdentityKey != "":
+				fmt.Fprintf(f.Out, "使用身份标识请求头 %s 访问 %s\n", config.IdentityKey, config.Addr)
+				return nil
+			case config.Username == "":
+				fmt.Fprintf(f.Out, "未登录，匿名访问 %s\n", config.Addr)
+				return nil
+			}
+
+			// 确保 token 有效，过期时使用保存的密码重新登录
+			auth := &nacos.PasswordAuthenticator{Config: config, HTTPClient: client.HTTPClient, Logger: client.Logger}
+			if _, err := auth.AccessToken(cmd.Context()); err != nil {
+				return err
+			}
+			token, err := nacos.CachedToken(config.Addr, config.Username)
+			if err != nil {
+				return err
+			}
+
+			table := uitable.New()
+			table.AddRow("用户:", config.Username)
+			table.AddRow("服务器:", config.Addr)
+			if token != nil {
+				expire := time.Unix(token.ExpireTime, 0)
+				table.AddRow("过期时间:", fmt.Sprintf("%s (剩余 %s)", expire.Format("2006-01-02 15:04:05"), time.Until(expire).Round(time.Second)))
+				admin := "否"
+				if token.GlobalAdmin {
+					admin = "是"
+				}
+				table.AddRow("管理员:", admin)
+			}
+			fmt.Fprintln(f.Out, table)
+			return nil
+		},
+	}
+}
+
+// readLoginPassword 按 --password-stdin、-p、终端输入的顺序获取密码
+func (f *factory) readLoginPassword(passwordStdin bool) (string, error) {
+	switch {
+	case passwordStdin:
+		data, err := io.ReadAll(f.lineReader())
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	case f.password != "":
+		fmt.Fprintln(f.ErrOut, "Warning: 通过 -p 传入的密码会留在 shell 历史中，建议使用 --password-stdin")
+		return f.password, nil
+	case os.Getenv("NACOS_PASSWORD") != "":
+		return os.Getenv("NACOS_PASSWORD"), nil
+	}
+	return f.lineReader().ReadPassword(f.ErrOut, "Password: ")
+}
+
+// loadCredential 未提供密码时从凭据存储中读取该服务器的凭据
+func (f *factory) loadCredential(config *nacos.NacosConfig) error {
+	if config.Password != "" {
+		return nil
+	}
+
+	store, err := credential.NewStore(f.credentialHelper)
+	if err != nil {
+		return err
+	}
+
+	stored, err := store.Get(config.Addr)
+	if errors.Is(err, credential.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取凭据失败: %w", err)
+	}
+
+	// 显式指定了其他用户时不使用保存的凭据
+	if config.Username != "" && config.Username != stored.Username {
+		return nil
+	}
+	config.Username = stored.Username
+	config.Password = stored.Secret
+	return nil
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"bytes"
+	"github.com/Talbot3/nacos-cli/pkg/credential"
+	"github.com/Talbot3/nacos-cli/pkg/nacos"
+	"github.com/Talbot3/nacos-cli/pkg/nacos/nacostest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLoginServer(t *testing.T) *nacostest.Server {
+	// 不使用开发者本机环境变量中的凭据
+	t.Setenv("NACOS_PASSWORD", "")
+	t.Setenv("NACOS_CREDENTIAL_HELPER", "")
+	t.Setenv(credential.KeyEnv, "")
+
+	server := nacostest.NewServer(nacostest.WithAuth("nacos", "s3cret"))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// storedCredential 读取当前 HOME 下保存的凭据
+func storedCredential(t *testing.T, addr string) *credential.Credential {
+	store, err := credential.NewStore("")
+	require.NoError(t, err)
+	stored, err := store.Get(addr)
+	require.NoError(t, err)
+	return stored
+}
+
+func TestLogin(t *testing.T) {
+	tests := []struct {
+		name   string
+		stdin  string
+		args   []string
+		secret string // 保存的密码
+	}{
+		{name: "piped username and password", stdin: "nacos\ns3cret\n", secret: "s3cret"},
+		{name: "password stdin", stdin: "s3cret\n", args: []string{"-u", "nacos", "--password-stdin"}, secret: "s3cret"},
+		{name: "token only", stdin: "s3cret", args: []string{"-u", "nacos", "--token-only"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newLoginServer(t)
+
+			out, _, err := runCommandStdin(t, server, tt.stdin, append([]string{"login"}, tt.args...)...)
+			require.NoError(t, err)
+			assert.Equal(t, "登录成功: nacos@"+server.Addr+"\n", out)
+			assert.Equal(t, 1, server.Logins())
+
+			stored := storedCredential(t, server.Addr)
+			assert.Equal(t, "nacos", stored.Username)
+			assert.Equal(t, tt.secret, stored.Secret)
+
+			token, err := nacos.CachedToken(server.Addr, "nacos")
+			require.NoError(t, err)
+			assert.NotEmpty(t, token.AccessToken)
+		})
+	}
+}
+
+func TestLoginWrongPassword(t *testing.T) {
+	server := newLoginServer(t)
+
+	_, _, err := runCommandStdin(t, server, "nacos\nwrong\n", "login")
+	assert.ErrorIs(t, err, nacos.ErrAuthFailed)
+
+	store, err := credential.NewStore("")
+	require.NoError(t, err)
+	_, err = store.Get(server.Addr)
+	assert.ErrorIs(t, err, credential.ErrNotFound, "登录失败时不保存凭据")
+}
+
+func TestLoadCredential(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(credential.KeyEnv, "")
+	store, err := credential.NewStore("")
+	require.NoError(t, err)
+	require.NoError(t, store.Store(&credential.Credential{ServerURL: "http://nacos:8848/nacos", Username: "nacos", Secret: "s3cret"}))
+
+	tests := []struct {
+		name   string
+		config nacos.NacosConfig
+		want   nacos.NacosConfig
+	}{
+		{
+			name:   "stored credential",
+			config: nacos.NacosConfig{Addr: "http://nacos:8848/nacos/"},
+			want:   nacos.NacosConfig{Addr: "http://nacos:8848/nacos/", Username: "nacos", Password: "s3cret"},
+		},
+		{
+			name:   "same user",
+			config: nacos.NacosConfig{Addr: "http://nacos:8848/nacos", Username: "nacos"},
+			want:   nacos.NacosConfig{Addr: "http://nacos:8848/nacos", Username: "nacos", Password: "s3cret"},
+		},
+		{
+			name:   "other user",
+			config: nacos.NacosConfig{Addr: "http://nacos:8848/nacos", Username: "admin"},
+			want:   nacos.NacosConfig{Addr: "http://nacos:8848/nacos", Username: "admin"},
+		},
+		{
+			name:   "password given",
+			config: nacos.NacosConfig{Addr: "http://nacos:8848/nacos", Username: "admin", Password: "p"},
+			want:   nacos.NacosConfig{Addr: "http://nacos:8848/nacos", Username: "admin", Password: "p"},
+		},
+		{
+			name:   "other server",
+			config: nacos.NacosConfig{Addr: "http://other:8848/nacos"},
+			want:   nacos.NacosConfig{Addr: "http://other:8848/nacos"},
+		},
+	}
+
+	f := &factory{IOStreams: IOStreams{In: &bytes.Buffer{}, Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			require.NoError(t, f.loadCredential(&config))
+			assert.Equal(t, tt.want, config)
+		})
+	}
+}
+
+// fakeCredentialHelper 使用文件模拟 docker credential helper，每个服务器一个文件
+const fakeCredentialHelper = `#!/bin/sh
+dir="$(dirname "$0")/store"
+mkdir -p "$dir"
+case "$1" in
+store)
+	input=$(cat)
+	server=$(printf '%s' "$input" | sed 's/.*"ServerURL":"\([^"]*\)".*/\1/')
+	printf '%s' "$input" > "$dir/$(printf '%s' "$server" | tr '/:' '__')"
+	;;
+get)
+	f="$dir/$(cat | tr '/:' '__')"
+	[ -f "$f" ] || { echo "credentials not found in native keychain"; exit 1; }
+	cat "$f"
+	;;
+erase)
+	f="$dir/$(cat | tr '/:' '__')"
+	[ -f "$f" ] || { echo "credentials not found in native keychain"; exit 1; }
+	rm "$f"
+	;;
+list)
+	printf '{'
+	sep=""
+	for f in "$dir"/*; do
+		[ -f "$f" ] || continue
+		sed 's/.*"ServerURL":"\([^"]*\)","Username":"\([^"]*\)".*/'"$sep"'"\1":"\2"/' "$f"
+		sep=","
+	done
+	printf '}'
+	;;
+esac
+`
+
+func TestLogoutAllKeepsForeignHelperCredentials(t *testing.T) {
+	server := newLoginServer(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	program := filepath.Join(home, "fake-helper")
+	require.NoError(t, os.WriteFile(program, []byte(fakeCredentialHelper), 0755))
+	store, err := credential.NewHelperStore(program, "")
+	require.NoError(t, err)
+
+	// helper 中 docker 保存的凭据不属于 nacosctl
+	docker := &credential.Credential{ServerURL: "https://index.docker.io/v1", Username: "docker", Secret: "d"}
+	require.NoError(t, store.Store(docker))
+	require.NoError(t, os.Remove(filepath.Join(home, ".nacosctl", "credential-helpers.json")))
+	require.NoError(t, store.Store(&credential.Credential{ServerURL: server.Addr, Username: "nacos", Secret: "s3cret"}))
+
+	out := &bytes.Buffer{}
+	cmd := NewRootCommand(IOStreams{In: &bytes.Buffer{}, Out: out, ErrOut: &bytes.Buffer{}}, func(string) (*nacos.Client, error) {
+		return nacos.New(server.Addr), nil
+	})
+	cmd.SetArgs([]string{"logout", "--all", "--credential-helper", program})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "已清除所有凭据和 token\n", out.String())
+
+	_, err = store.Get(server.Addr)
+	assert.ErrorIs(t, err, credential.ErrNotFound)
+	stored, err := store.Get(docker.ServerURL)
+	require.NoError(t, err)
+	assert.Equal(t, "docker", stored.Username)
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"github.com/Talbot3/nacos-cli/pkg/content"
+	"github.com/Talbot3/nacos-cli/pkg/secret"
+)
+
+// initRedactor 根据 --show-secrets 和 --sensitive-keys 初始化脱敏器
+func (f *factory) initRedactor() error {
+	if f.showSecrets {
+		f.redactor = nil
+		return nil
+	}
+
+	r, err := content.NewRedactor(f.sensitiveKeys)
+	if err != nil {
+		return err
+	}
+	f.redactor = r
+	return nil
+}
+
+// redactContent 隐藏配置内容中敏感键的值，类型为空时从 dataId 推断
+func (f *factory) redactContent(text, configType, dataId string) string {
+	if f.redactor == nil {
+		return text
+	}
+	return f.redactor.Redact(text, content.Type(configType, dataId))
+}
+
+// redactChanges 隐藏键值差异中敏感键的值
+func (f *factory) redactChanges(changes []content.Change) []content.Change {
+	if f.redactor == nil {
+		return changes
+	}
+
+	redacted := make([]content.Change, len(changes))
+	for i, change := range changes {
+		if f.redactor.Sensitive(change.Key) {
+			if change.Old != "" {
+				change.Old = content.Masked
+			}
+			if change.New != "" {
+				change.New = content.Masked
+			}
+		}
+		redacted[i] = change
+	}
+	return redacted
+}
+
+// maskSecrets 隐藏由密钥引用解析出的值
+func (f *factory) maskSecrets(text string, values []string) string {
+	if f.showSecrets {
+		return text
+	}
+	return secret.Mask(text, values)
+}
@@ -1,28 +1,32 @@
 package cmd
 
 import (
-	"github/szpinc/nacosctl/pkg/nacos"
+	"context"
+	"github.com/Talbot3/nacos-cli/pkg/content"
+	"github.com/Talbot3/nacos-cli/pkg/interrupt"
 	"os"
+	"os/signal"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
-var namespace string
-var group string
-var username string
-var password string
+// interruptGrace 收到中断信号后等待命令自行退出的时间，超时后强制退出（如阻塞在读取标准输入）
+const interruptGrace = 2 * time.Second
 
-var nacosClient *nacos.Client
+// NewRootCommand 创建 nacosctl 命令。命令通过 streams 输入输出，通过 newClient 创建客户端，
+// 每次调用返回独立的命令树，参数互不影响
+func NewRootCommand(streams IOStreams, newClient ClientFactory) *cobra.Command {
+	f := &factory{IOStreams: streams, newClient: newClient}
 
-// rootCmd represents the base command when called without any subcommands
-var rootCmd = &cobra.Command{
-	Use:   "nacosctl",
-	Short: "Nacos 配置管理命令行工具",
-	Long: `nacosctl 是一个用于管理 Nacos 配置的命令行工具。
+	cmd := &cobra.Command{
+		Use:   "nacosctl",
+		Short: "Nacos 配置管理命令行工具",
+		Long: `nacosctl 是一个用于管理 Nacos 配置的命令行工具。
 
 支持读取、创建、更新和删除 Nacos 服务器上的配置。
 可以通过用户名密码或环境变量进行身份验证。`,
-	Example: `  # 通过环境变量设置 Nacos 服务器地址
+		Example: `  # 通过环境变量设置 Nacos 服务器地址
   export NACOS_ADDR="http://localhost:8848/nacos"
 
   # 通过环境变量设置认证信息
@@ -46,49 +50,76 @@ var rootCmd = &cobra.Command{
 
   # 删除配置
   nacosctl delete config app.yaml -n public`,
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
-	},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// 命令行参数解析后创建客户端
+			return f.init()
+		},
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			return f.close()
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+	cmd.SetIn(streams.In)
+	cmd.SetOut(streams.Out)
+	cmd.SetErr(streams.ErrOut)
+
+	flags := cmd.PersistentFlags()
+	flags.StringVarP(&f.namespace, "namespace", "n", "", "Nacos 命名空间 ID (必填)")
+	flags.StringVarP(&f.group, "group", "g", "DEFAULT_GROUP", "Nacos 分组名称")
+	flags.StringVarP(&f.username, "username", "u", "", "Nacos 用户名 (覆盖 NACOS_USERNAME 环境变量)")
+	flags.StringVarP(&f.password, "password", "p", "", "Nacos 密码 (覆盖 NACOS_PASSWORD 环境变量)")
+	flags.StringVar(&f.credentialHelper, "credential-helper", os.Getenv("NACOS_CREDENTIAL_HELPER"), "保存登录凭据的 docker credential helper 名称 (如 osxkeychain)，默认使用加密的本地文件")
+	flags.StringVar(&f.transport, "transport", "", "配置读写和监听使用的传输方式: http 或 grpc (覆盖 NACOS_TRANSPORT 环境变量，grpc 使用 HTTP 端口 + 1000)")
+	flags.StringVar(&f.encryptionKeyFile, "encryption-key-file", "", "cipher- 配置的加密主密钥文件 (覆盖 NACOS_ENCRYPTION_KEY_FILE、NACOS_ENCRYPTION_KEY 环境变量)")
+	flags.BoolVar(&f.serverSideEncryption, "server-side-encryption", false, "未提供加密密钥时 cipher- 配置原样发布，由服务端加密插件加密 (默认拒绝发布)")
+
+	flags.DurationVar(&f.requestTimeout, "request-timeout", 0, "单次请求 (包括重试) 的超时时间，如 10s、1m，0 不限制；监听的长轮询在 30 秒挂起时间之外另计")
+	flags.IntVar(&f.retries, "retries", 2, "请求失败 (5xx、连接错误) 时的最大重试次数，只重试查询、删除等可安全重试的请求，0 不重试")
+	flags.DurationVar(&f.retryTimeout, "retry-timeout", 30*time.Second, "从首次请求开始计算的重试时间上限")
+	flags.CountVarP(&f.verbosity, "verbose", "v", "输出详细日志到 stderr: -v 输出请求、耗时和重试，-vv 增加请求头和响应头，-vvv 增加请求体和响应体")
+
+	flags.BoolVar(&f.showSecrets, "show-secrets", false, "输出中显示密码、token 等敏感值的明文")
+	flags.StringSliceVar(&f.sensitiveKeys, "sensitive-keys", content.DefaultSensitivePatterns, "视为敏感的键名模式 (正则表达式，不区分大小写，需匹配完整的键名)，逗号分隔；敏感键下的所有值都会隐藏")
+
+	_ = cmd.MarkFlagRequired("namespace")
+
+	cmd.AddCommand(
+		newGetCmd(f),
+		newApplyCmd(f),
+		newEditCmd(f),
+		newDeleteCmd(f),
+		newCopyCmd(f),
+		newCompareCmd(f),
+		newSyncCmd(f),
+		newExportCmd(f),
+		newImportCmd(f),
+		newBetaCmd(f),
+		newLabelCmd(f),
+		newAnnotateCmd(f),
+		newLoginCmd(f),
+		newLogoutCmd(f),
+		newWhoamiCmd(f),
+	)
+	return cmd
 }
 
 func Execute() {
-	err := rootCmd.Execute()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 收到 SIGINT/SIGTERM 时取消进行中的请求，命令返回后正常退出；
+	// 命令未响应取消或再次收到信号时立即退出
+	handler := interrupt.New(func(os.Signal) {
+		signal.Reset()
+		time.AfterFunc(interruptGrace, func() { os.Exit(1) })
+	}, cancel)
+
+	err := handler.Run(func() error {
+		return NewRootCommand(StdStreams(), DefaultClientFactory).ExecuteContext(ctx)
+	})
 	if err != nil {
 		os.Exit(1)
 	}
 }
-
-func init() {
-	rootCmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", "", "Nacos 命名空间 ID (必填)")
-	rootCmd.PersistentFlags().StringVarP(&group, "group", "g", "DEFAULT_GROUP", "Nacos 分组名称")
-	rootCmd.PersistentFlags().StringVarP(&username, "username", "u", "", "Nacos 用户名 (覆盖 NACOS_USERNAME 环境变量)")
-	rootCmd.PersistentFlags().StringVarP(&password, "password", "p", "", "Nacos 密码 (覆盖 NACOS_PASSWORD 环境变量)")
-
-	_ = rootCmd.MarkFlagRequired("namespace")
-
-	// 初始化客户端
-	nacosClient = initNacosClient()
-}
-
-// initNacosClient 初始化Nacos客户端
-func initNacosClient() *nacos.Client {
-	addr := os.Getenv("NACOS_ADDR")
-	apiVersion := os.Getenv("NACOS_API_VERSION")
-	envUsername := os.Getenv("NACOS_USERNAME")
-	envPassword := os.Getenv("NACOS_PASSWORD")
-
-	// 命令行参数优先级高于环境变量
-	if username != "" {
-		envUsername = username
-	}
-	if password != "" {
-		envPassword = password
-	}
-
-	// 如果命令行或环境变量指定了用户名密码，使用NewClient
-	if envUsername != "" && envPassword != "" {
-		return nacos.NewClient(addr, apiVersion, envUsername, envPassword)
-	}
-
-	return nacos.NewDefaultClient()
-}
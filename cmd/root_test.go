@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"github.com/Talbot3/nacos-cli/pkg/nacos"
+	"github.com/Talbot3/nacos-cli/pkg/nacos/nacostest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "更新 testdata 中的 golden 文件")
+
+// fakeEditorEnv 设置后测试程序作为编辑器运行，将该变量的值写入要编辑的文件
+const fakeEditorEnv = "NACOSCTL_TEST_EDITOR_CONTENT"
+
+func TestMain(m *testing.M) {
+	if content, ok := os.LookupEnv(fakeEditorEnv); ok {
+		if err := os.WriteFile(os.Args[len(os.Args)-1], []byte(content), 0644); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// setFakeEditor 将测试程序设为 EDITOR，编辑结果为 content
+func setFakeEditor(t *testing.T, content string) {
+	executable, err := os.Executable()
+	require.NoError(t, err)
+	t.Setenv("EDITOR", executable)
+	t.Setenv(fakeEditorEnv, content)
+}
+
+// runCommand 对 server 执行 nacosctl 命令，返回标准输出、标准错误和命令的错误
+func runCommand(t *testing.T, server *nacostest.Server, args ...string) (string, string, error) {
+	return executeCommand(context.Background(), t, server, "", args...)
+}
+
+// runCommandContext 与 runCommand 相同，ctx 取消时停止 sync 等持续运行的命令
+func runCommandContext(ctx context.Context, t *testing.T, server *nacostest.Server, args ...string) (string, string, error) {
+	return executeCommand(ctx, t, server, "", args...)
+}
+
+// runCommandStdin 与 runCommand 相同，stdin 作为命令的标准输入
+func runCommandStdin(t *testing.T, server *nacostest.Server, stdin string, args ...string) (string, string, error) {
+	return executeCommand(context.Background(), t, server, stdin, args...)
+}
+
+func executeCommand(ctx context.Context, t *testing.T, server *nacostest.Server, stdin string, args ...string) (string, string, error) {
+	// token 缓存和凭据保存在 HOME 下，避免读写开发者本机的文件
+	t.Setenv("HOME", t.TempDir())
+
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	streams := IOStreams{In: bytes.NewBufferString(stdin), Out: out, ErrOut: errOut}
+	cmd := NewRootCommand(streams, func(contextName string) (*nacos.Client, error) {
+		return nacos.New(server.Addr), nil
+	})
+	cmd.SetArgs(args)
+
+	err := cmd.ExecuteContext(ctx)
+	return out.String(), errOut.String(), err
+}
+
+// assertGolden 比较输出与 testdata/<name>.golden，指定 -update 时更新 golden 文件
+func assertGolden(t *testing.T, name, actual string) {
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		require.NoError(t, os.MkdirAll("testdata", 0755))
+		require.NoError(t, os.WriteFile(path, []byte(actual), 0644))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err, "使用 go test ./cmd -update 生成 golden 文件")
+	assert.Equal(t, string(expected), actual)
+}
+
+func TestNewRootCommandIsolated(t *testing.T) {
+	server := nacostest.NewServer()
+	defer server.Close()
+	server.SetConfig(nacostest.Config{Namespace: "dev", Group: "DEFAULT_GROUP", DataId: "app.yaml", Content: "port: 8080"})
+
+	out, _, err := runCommand(t, server, "get", "config", "app.yaml", "-n", "dev")
+	require.NoError(t, err)
+	assert.Equal(t, "port: 8080\n", out)
+
+	// 每次创建的命令树参数互不影响，上一次的 -n 不会带到下一次，查询的是 public 命名空间
+	_, _, err = runCommand(t, server, "get", "config", "app.yaml")
+	assert.ErrorIs(t, err, nacos.ErrConfigNotExist)
+}
+
+func TestFactoryClosesAllClients(t *testing.T) {
+	created := map[string]int{}
+	f := &factory{newClient: func(contextName string) (*nacos.Client, error) {
+		created[contextName]++
+		return nacos.New("http://127.0.0.1:8848/nacos"), nil
+	}}
+
+	client, err := f.clientFor("")
+	require.NoError(t, err)
+	f.client = client
+
+	// copy --to-context、compare 按上下文创建的客户端同样在命令结束时关闭
+	for _, name := range []string{"", "test", "prod"} {
+		_, err := f.clientFor(name)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, map[string]int{"": 1, "test": 1, "prod": 1}, created)
+	assert.Len(t, f.clients, 3)
+
+	require.NoError(t, f.close())
+	assert.Empty(t, f.clients)
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/nacos"
+	"github.com/Talbot3/nacos-cli/pkg/util"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// syncOptions sync 命令的参数
+type syncOptions struct {
+	*factory
+
+	dir  string // 本地同步目录
+	exec string // 每次更新后执行的命令
+}
+
+// newSyncCmd 创建 sync 命令
+func newSyncCmd(f *factory) *cobra.Command {
+	o := &syncOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "sync [dataId...]",
+		Short: "将远程配置持续同步到本地目录",
+		Long: `将 Nacos 上的配置同步到本地目录，并通过长轮询持续监听变更。
+
+适用于从磁盘读取配置、未接入 Nacos SDK 的服务。
+每个配置写入 <目录>/<dataId>，先写临时文件再重命名，读取方不会看到写了一半的文件。
+dataId 为绝对路径或包含 .. 等会写到目录之外时拒绝同步该配置。
+本地文件内容与远程一致时不会重写，也不会触发 --exec。
+
+未指定 dataId 时同步分组下的所有配置（仅包含启动时已存在的配置）。
+远程配置被删除时保留本地文件。收到 SIGINT/SIGTERM 后停止监听并退出。
+
+--exec 命令通过 shell 执行，可使用以下环境变量：
+  NACOS_NAMESPACE, NACOS_GROUP, NACOS_DATA_ID, NACOS_FILE`,
+		Example: `  # 同步指定配置到 ./conf
+  nacosctl sync --to ./conf -n public -g DEFAULT_GROUP app.yaml db.yaml
+
+  # 同步分组下的所有配置
+  nacosctl sync --to ./conf -n public -g PROD_GROUP
+
+  # 配置更新后通知服务重新加载
+  nacosctl sync --to /etc/app -n public app.yaml --exec 'kill -HUP $(cat /run/app.pid)'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := os.MkdirAll(o.dir, 0755); err != nil {
+				return err
+			}
+
+			keys, err := o.syncKeys(cmd.Context(), args)
+			if err != nil {
+				return err
+			}
+			if len(keys) == 0 {
+				return errors.New("没有需要同步的配置")
+			}
+
+			events, err := f.client.Watch(cmd.Context(), keys...)
+			if err != nil {
+				return err
+			}
+
+			// 收到信号时根命令的 ctx 被取消，处理完当前事件后退出
+			for event := range events {
+				if event.Err != nil {
+					o.syncLog("同步出错 %s: %v", event.Key, event.Err)
+					continue
+				}
+
+				if event.Config == nil {
+					if event.Previous != nil {
+						o.syncLog("远程配置已删除，保留本地文件: %s", event.Key)
+					} else {
+						o.syncLog("远程配置不存在，等待创建: %s", event.Key)
+					}
+					continue
+				}
+
+				path, err := syncPath(o.dir, event.Key.DataId)
+				if err != nil {
+					o.syncLog("跳过 %s: %v", event.Key, err)
+					continue
+				}
+				written, err := syncFile(path, []byte(event.Config.Content))
+				if err != nil {
+					o.syncLog("写入 %s 失败: %v", path, err)
+					continue
+				}
+				if !written {
+					continue
+				}
+				o.syncLog("已同步 %s -> %s", event.Key, path)
+
+				if o.exec != "" {
+					if err := o.runSyncHook(event.Key, path); err != nil {
+						o.syncLog("执行 --exec 失败: %v", err)
+					}
+				}
+			}
+			o.syncLog("同步已停止")
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&o.dir, "to", "", "本地同步目录 (必填)")
+	cmd.Flags().StringVar(&o.exec, "exec", "", "每次配置文件更新后执行的命令")
+
+	_ = cmd.MarkFlagRequired("to")
+	return cmd
+}
+
+// syncKeys 根据参数确定要同步的配置，未指定时列出分组下的所有配置。
+// 指定的 dataId 会写到目录之外时返回错误，列出的配置中这样的 dataId 跳过
+func (o *syncOptions) syncKeys(ctx context.Context, dataIds []string) ([]nacos.ConfigKey, error) {
+	listed := len(dataIds) == 0
+	if listed {
+		items, err := o.client.AllConfigContext(ctx, nacos.ConfigGetOperation{
+			NacosOperation: &nacos.NacosOperation{
+				Namespace: o.namespace,
+				Group:     o.group,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			dataIds = append(dataIds, item.DataId)
+		}
+	}
+
+	keys := make([]nacos.ConfigKey, 0, len(dataIds))
+	for _, dataId := range dataIds {
+		if _, err := syncPath(o.dir, dataId); err != nil {
+			if !listed {
+				return nil, err
+			}
+			o.syncLog("跳过 %s: %v", dataId, err)
+			continue
+		}
+		keys = append(keys, nacos.ConfigKey{
+			Namespace: o.namespace,
+			Group:     o.group,
+			DataId:    dataId,
+		})
+	}
+	return keys, nil
+}
+
+// syncPath 返回 dataId 在同步目录中的文件路径，dataId 会写到目录之外时返回错误
+func syncPath(dir, dataId string) (string, error) {
+	if !filepath.IsLocal(dataId) {
+		return "", fmt.Errorf("dataId %q 不是目录 %s 下的文件", dataId, dir)
+	}
+	return filepath.Join(dir, dataId), nil
+}
+
+// syncFile 内容变化时原子写入文件，返回是否发生了写入
+func syncFile(path string, content []byte) (bool, error) {
+	if current, err := os.ReadFile(path); err == nil {
+		if util.Md5BytesToString(current) == util.Md5BytesToString(content) {
+			return false, nil
+		}
+	}
+
+	if err := util.WriteFileAtomic(path, content, 0644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// runSyncHook 通过 shell 执行 --exec 命令
+func (o *syncOptions) runSyncHook(key nacos.ConfigKey, path string) error {
+	shell, flag := "/bin/sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+
+	hook := exec.Command(shell, flag, o.exec)
+	hook.Stdout = o.Out
+	hook.Stderr = o.ErrOut
+	hook.Env = append(os.Environ(),
+		"NACOS_NAMESPACE="+key.Namespace,
+		"NACOS_GROUP="+key.Group,
+		"NACOS_DATA_ID="+key.DataId,
+		"NACOS_FILE="+path,
+	)
+	return hook.Run()
+}
+
+func (o *syncOptions) syncLog(format string, args ...interface{}) {
+	fmt.Fprintf(o.Out, "%s %s\n", time.Now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+}
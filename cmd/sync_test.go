@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"github.com/Talbot3/nacos-cli/pkg/nacos/nacostest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startSync 在后台运行 sync 命令，返回停止命令并获取其输出的函数
+func startSync(t *testing.T, server *nacostest.Server, args ...string) func() (string, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	type result struct {
+		out string
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		out, _, err := runCommandContext(ctx, t, server, append([]string{"sync"}, args...)...)
+		done <- result{out, err}
+	}()
+
+	return func() (string, error) {
+		cancel()
+		r := <-done
+		return r.out, r.err
+	}
+}
+
+// assertFileEventually 等待文件内容变为 content
+func assertFileEventually(t *testing.T, path, content string) {
+	assert.Eventually(t, func() bool {
+		data, err := os.ReadFile(path)
+		return err == nil && string(data) == content
+	}, 5*time.Second, 10*time.Millisecond, "%s 未同步为 %q", path, content)
+}
+
+func TestSync(t *testing.T) {
+	server := newConfigServer(t)
+	dir := t.TempDir()
+
+	stop := startSync(t, server, "--to", dir, "-n", "dev", "app.yaml")
+	path := filepath.Join(dir, "app.yaml")
+	assertFileEventually(t, path, "server:\n  port: 8080\ndb:\n  host: db.dev\n  password: s3cret\n")
+
+	server.SetConfig(nacostest.Config{Namespace: "dev", Group: "DEFAULT_GROUP", DataId: "app.yaml", Content: "server:\n  port: 9090\n"})
+	assertFileEventually(t, path, "server:\n  port: 9090\n")
+
+	out, err := stop()
+	require.NoError(t, err)
+	assert.Contains(t, out, "已同步 dev/DEFAULT_GROUP/app.yaml -> "+path)
+	assert.Contains(t, out, "同步已停止")
+}
+
+func TestSyncRejectsPathTraversal(t *testing.T) {
+	server := newConfigServer(t)
+	root := t.TempDir()
+	dir := filepath.Join(root, "conf")
+
+	for _, dataId := range []string{"../escape.yaml", "/tmp/escape.yaml"} {
+		_, _, err := runCommand(t, server, "sync", "--to", dir, "-n", "dev", dataId)
+		assert.ErrorContains(t, err, "不是目录", dataId)
+	}
+
+	// 列出分组下的配置时跳过会写到目录之外的 dataId，其余配置照常同步
+	server.SetConfig(nacostest.Config{Namespace: "dev", Group: "SYNC_GROUP", DataId: "../escape.yaml", Content: "escaped: true\n"})
+	server.SetConfig(nacostest.Config{Namespace: "dev", Group: "SYNC_GROUP", DataId: "ok.yaml", Content: "ok: true\n"})
+
+	stop := startSync(t, server, "--to", dir, "-n", "dev", "-g", "SYNC_GROUP")
+	assertFileEventually(t, filepath.Join(dir, "ok.yaml"), "ok: true\n")
+	out, err := stop()
+	require.NoError(t, err)
+	assert.Contains(t, out, `跳过 ../escape.yaml`)
+	assert.NoFileExists(t, filepath.Join(root, "escape.yaml"))
+}
+
+func TestSyncPath(t *testing.T) {
+	tests := []struct {
+		dataId string
+		ok     bool
+	}{
+		{"app.yaml", true},
+		{"sub/app.yaml", true},
+		{"../app.yaml", false},
+		{"sub/../../app.yaml", false},
+		{"/etc/app.yaml", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		path, err := syncPath("conf", tt.dataId)
+		if tt.ok {
+			require.NoError(t, err, tt.dataId)
+			assert.Equal(t, filepath.Join("conf", tt.dataId), path)
+		} else {
+			assert.Error(t, err, tt.dataId)
+		}
+	}
+}
+
+func TestSyncFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+
+	written, err := syncFile(path, []byte("a: 1\n"))
+	require.NoError(t, err)
+	assert.True(t, written)
+
+	// 内容相同时不重写
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(path, past, past))
+	written, err = syncFile(path, []byte("a: 1\n"))
+	require.NoError(t, err)
+	assert.False(t, written)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, past, info.ModTime())
+
+	written, err = syncFile(path, []byte("a: 2\n"))
+	require.NoError(t, err)
+	assert.True(t, written)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "a: 2\n", string(data))
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/nacos"
+	"net/http"
+	"time"
+)
+
+// initLogging 将客户端的警告输出到 stderr，-v 时将客户端的 HTTP 请求输出到 stderr，级别越高输出越详细
+func (f *factory) initLogging(client *nacos.Client) {
+	client.Logger = func(format string, args ...any) {
+		fmt.Fprintf(f.ErrOut, "Warning: "+format+"\n", args...)
+	}
+	if f.verbosity < 1 {
+		return
+	}
+
+	httpClient := http.Client{}
+	if client.HTTPClient != nil {
+		httpClient = *client.HTTPClient
+	}
+	httpClient.Transport = &nacos.TraceTransport{Base: httpClient.Transport, Out: f.ErrOut, Level: f.verbosity}
+	client.HTTPClient = &httpClient
+}
+
+// retryPolicy 根据 --retries、--retry-timeout 构造重试策略，-v 时输出每次重试
+func (f *factory) retryPolicy() *nacos.RetryPolicy {
+	policy := nacos.DefaultRetryPolicy()
+	policy.MaxRetries = f.retries
+	policy.Timeout = f.retryTimeout
+	policy.OnRetry = func(event nacos.RetryEvent) {
+		if f.verbosity < 1 {
+			return
+		}
+		reason := fmt.Sprintf("status code %d", event.StatusCode)
+		if event.Err != nil {
+			reason = event.Err.Error()
+		}
+		fmt.Fprintf(f.ErrOut, "重试 %s %s (第 %d 次，等待 %s): %s\n",
+			event.Method, event.URL, event.Attempt, event.Wait.Round(time.Millisecond), reason)
+	}
+	return policy
+}
@@ -3,7 +3,7 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 */
 package main
 
-import "github/szpinc/nacosctl/cmd"
+import "github.com/Talbot3/nacos-cli/cmd"
 
 func main() {
 	cmd.Execute()
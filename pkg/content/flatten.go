@@ -0,0 +1,168 @@
+// Package content 解析 YAML、JSON、Properties 等配置内容
+package content
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnsupportedType 不支持结构化解析的配置类型
+var ErrUnsupportedType = errors.New("unsupported config type")
+
+// Type 规范化配置类型，类型为空时从 dataId 扩展名推断
+func Type(configType, dataId string) string {
+	if configType == "" {
+		configType = strings.TrimPrefix(path.Ext(dataId), ".")
+	}
+	configType = strings.ToLower(configType)
+	if configType == "yml" {
+		return "yaml"
+	}
+	return configType
+}
+
+// Flatten 将配置内容展开为 "a.b[0].c" 形式的键值对，支持 yaml、json、properties
+func Flatten(content, configType string) (map[string]string, error) {
+	switch Type(configType, "") {
+	case "yaml":
+		var value interface{}
+		if err := yaml.Unmarshal([]byte(content), &value); err != nil {
+			return nil, err
+		}
+		return flattenValue(value), nil
+	case "json":
+		var value interface{}
+		if err := json.Unmarshal([]byte(content), &value); err != nil {
+			return nil, err
+		}
+		return flattenValue(value), nil
+	case "properties":
+		return parseProperties(content), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, configType)
+}
+
+func flattenValue(value interface{}) map[string]string {
+	result := make(map[string]string)
+	flatten("", value, result)
+	return result
+}
+
+func flatten(prefix string, value interface{}, result map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			flatten(join(prefix, key), child, result)
+		}
+	case map[interface{}]interface{}:
+		for key, child := range v {
+			flatten(join(prefix, fmt.Sprint(key)), child, result)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flatten(fmt.Sprintf("%s[%d]", prefix, i), child, result)
+		}
+	case nil:
+		if prefix != "" {
+			result[prefix] = ""
+		}
+	default:
+		result[prefix] = fmt.Sprint(v)
+	}
+}
+
+func join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// parseProperties 解析 Java properties 格式，支持 = 和 : 分隔符及行尾 \ 续行
+func parseProperties(content string) map[string]string {
+	result := make(map[string]string)
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	logical := ""
+	for scanner.Scan() {
+		line := strings.TrimLeft(scanner.Text(), " \t")
+		if logical == "" && (line == "" || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+		if strings.HasSuffix(line, "\\") {
+			logical += strings.TrimSuffix(line, "\\")
+			continue
+		}
+		logical += line
+
+		key, value := splitProperty(logical)
+		result[key] = value
+		logical = ""
+	}
+	if logical != "" {
+		key, value := splitProperty(logical)
+		result[key] = value
+	}
+	return result
+}
+
+func splitProperty(line string) (string, string) {
+	i := strings.IndexAny(line, "=:")
+	if i < 0 {
+		return strings.TrimSpace(line), ""
+	}
+	return strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+}
+
+// ChangeKind 键值变更类型
+type ChangeKind string
+
+const (
+	Added    ChangeKind = "+"
+	Removed  ChangeKind = "-"
+	Modified ChangeKind = "~"
+)
+
+// Change 单个键的变更
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	Key  string     `json:"key"`
+	Old  string     `json:"old,omitempty"`
+	New  string     `json:"new,omitempty"`
+}
+
+// Compare 比较两组展开后的键值对，按键排序返回差异
+func Compare(a, b map[string]string) []Change {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []Change
+	for _, key := range keys {
+		old, inA := a[key]
+		value, inB := b[key]
+		switch {
+		case !inA:
+			changes = append(changes, Change{Kind: Added, Key: key, New: value})
+		case !inB:
+			changes = append(changes, Change{Kind: Removed, Key: key, Old: old})
+		case old != value:
+			changes = append(changes, Change{Kind: Modified, Key: key, Old: old, New: value})
+		}
+	}
+	return changes
+}
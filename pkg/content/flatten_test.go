@@ -0,0 +1,56 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlatten(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		configType string
+		want       map[string]string
+	}{
+		{
+			"yaml",
+			"server:\n  port: 8080\nhosts:\n  - a\n  - b\n",
+			"yml",
+			map[string]string{"server.port": "8080", "hosts[0]": "a", "hosts[1]": "b"},
+		},
+		{
+			"json",
+			`{"db": {"host": "x", "pool": [1, 2]}}`,
+			"json",
+			map[string]string{"db.host": "x", "db.pool[0]": "1", "db.pool[1]": "2"},
+		},
+		{
+			"properties",
+			"# comment\na.b = 1\nc: two\nlong=x\\\n  y\n",
+			"properties",
+			map[string]string{"a.b": "1", "c": "two", "long": "xy"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Flatten(tt.content, tt.configType)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := Flatten("x", "text")
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+}
+
+func TestCompare(t *testing.T) {
+	a := map[string]string{"a": "1", "b": "2", "c": "3"}
+	b := map[string]string{"a": "1", "b": "20", "d": "4"}
+	assert.Equal(t, []Change{
+		{Kind: Modified, Key: "b", Old: "2", New: "20"},
+		{Kind: Removed, Key: "c", Old: "3"},
+		{Kind: Added, Key: "d", New: "4"},
+	}, Compare(a, b))
+}
@@ -0,0 +1,422 @@
+package content
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Masked 脱敏后的占位文本
+const Masked = "******"
+
+// DefaultSensitivePatterns 默认视为敏感的键名模式（不区分大小写），需匹配完整的键名，
+// 如 dbPassword、api-key、clientSecret，而 maxKeys、tokenTtl、cacheKey 不是敏感键
+var DefaultSensitivePatterns = []string{`.*passw(or)?ds?`, `.*pwd`, `.*secrets?`, `.*tokens?`, `.*credentials?`, `.*(secret|private|access|api)[-_]?keys?`}
+
+// indexSuffix 展开后键名中的数组下标，如 servers[0]
+var indexSuffix = regexp.MustCompile(`(\[\d+\])+$`)
+
+// Redactor 按键名隐藏配置中的敏感值
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor 创建脱敏器，patterns 为不区分大小写的正则表达式，需匹配完整的键名
+func NewRedactor(patterns []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("(?i)^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, err
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Sensitive 判断键是否敏感。a.b[0].c 形式的键（Properties 或展开后的键）中任意一级敏感即为敏感
+func (r *Redactor) Sensitive(key string) bool {
+	for _, name := range strings.Split(key, ".") {
+		name = indexSuffix.ReplaceAllString(name, "")
+		for _, re := range r.patterns {
+			if re.MatchString(name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Redact 隐藏配置内容中敏感键下的所有值，支持 yaml、json、properties，其他类型原样返回。
+// 只替换敏感值所在的文本，其余内容（缩进、引号、注释）保持原样，没有敏感值时返回原内容。
+// 敏感键下的布尔值和 null 保持不变；YAML 无法解析时按行隐藏，JSON 无法解析时整体隐藏
+func (r *Redactor) Redact(content, configType string) string {
+	switch Type(configType, "") {
+	case "yaml":
+		return r.redactYaml(content)
+	case "json":
+		return r.redactJson(content)
+	case "properties":
+		return r.redactProperties(content)
+	}
+	return content
+}
+
+// yamlMasked YAML 中替换敏感值的文本，* 开头的普通标量会被解析为别名，需加引号
+const yamlMasked = "'" + Masked + "'"
+
+// redactYaml 遍历每个文档的节点树，按节点位置原地替换敏感键下的标量
+func (r *Redactor) redactYaml(content string) string {
+	w := &yamlWalker{
+		r:       r,
+		content: content,
+		lines:   lineOffsets(content),
+		anchors: map[*yaml.Node]yamlContext{},
+		masked:  map[*yaml.Node]bool{},
+	}
+
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	for {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return r.redactYamlLines(content)
+		}
+		w.walk(&node, false, yamlContext{indent: -1})
+	}
+
+	sort.Slice(w.spans, func(i, j int) bool { return w.spans[i][0] < w.spans[j][0] })
+	return replaceSpans(content, w.spans, yamlMasked)
+}
+
+// yamlContext 节点所在的上下文，用于确定标量文本的结束位置
+type yamlContext struct {
+	indent int  // 所属键或序列项 "-" 的缩进，多行标量的后续行缩进大于该值
+	flow   bool // 位于 {} 或 [] 中
+}
+
+// yamlWalker 记录 YAML 文档中需要隐藏的标量位置
+type yamlWalker struct {
+	r       *Redactor
+	content string
+	lines   []int // 每行起始的字节位置
+
+	anchors map[*yaml.Node]yamlContext // 锚点节点的上下文，别名被隐藏时锚点定义处一并隐藏
+	masked  map[*yaml.Node]bool
+	spans   [][2]int
+}
+
+func (w *yamlWalker) walk(node *yaml.Node, masking bool, ctx yamlContext) {
+	if node.Anchor != "" {
+		w.anchors[node] = ctx
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if masking && !w.masked[node] && node.Tag != "!!null" && node.Tag != "!!bool" {
+			w.masked[node] = true
+			w.spans = append(w.spans, w.scalarSpan(node, ctx))
+		}
+	case yaml.AliasNode:
+		// 别名指向的锚点也被隐藏，避免在锚点定义处泄露
+		if masking && node.Alias != nil {
+			w.walk(node.Alias, true, w.anchors[node.Alias])
+		}
+	case yaml.MappingNode:
+		flow := node.Style&yaml.FlowStyle != 0
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			sensitive := key.Kind == yaml.ScalarNode && w.r.Sensitive(key.Value)
+			w.walk(value, masking || sensitive, yamlContext{indent: key.Column - 1, flow: flow})
+		}
+	case yaml.SequenceNode:
+		child := yamlContext{indent: node.Column - 1, flow: node.Style&yaml.FlowStyle != 0}
+		for _, item := range node.Content {
+			w.walk(item, masking, child)
+		}
+	default:
+		for _, child := range node.Content {
+			w.walk(child, masking, ctx)
+		}
+	}
+}
+
+// scalarSpan 返回标量值在内容中的位置，不包括锚点、标签和行尾注释
+func (w *yamlWalker) scalarSpan(node *yaml.Node, ctx yamlContext) [2]int {
+	c := w.content
+	start := w.offset(node.Line, node.Column)
+
+	// 节点位置从锚点或标签开始
+	for start < len(c) && (c[start] == '&' || c[start] == '!') {
+		for start < len(c) && !strings.ContainsRune(" \t\r\n", rune(c[start])) {
+			start++
+		}
+		for start < len(c) && strings.ContainsRune(" \t\r\n", rune(c[start])) {
+			start++
+		}
+	}
+
+	switch {
+	case node.Style&yaml.DoubleQuotedStyle != 0:
+		for i := start + 1; i < len(c); i++ {
+			switch c[i] {
+			case '\\':
+				i++
+			case '"':
+				return [2]int{start, i + 1}
+			}
+		}
+	case node.Style&yaml.SingleQuotedStyle != 0:
+		for i := start + 1; i < len(c); i++ {
+			if c[i] == '\'' {
+				if i+1 < len(c) && c[i+1] == '\'' {
+					i++
+					continue
+				}
+				return [2]int{start, i + 1}
+			}
+		}
+	case node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0:
+		return [2]int{start, w.continuation(start, ctx.indent, true)}
+	case ctx.flow:
+		end := start
+		for end < len(c) && !strings.ContainsRune(",]}\r\n", rune(c[end])) && !isComment(c, end) {
+			end++
+		}
+		return [2]int{start, start + len(strings.TrimRight(c[start:end], " \t"))}
+	default:
+		return [2]int{start, w.continuation(start, ctx.indent, false)}
+	}
+	return [2]int{start, len(c)}
+}
+
+// continuation 返回从 start 开始的多行标量的结束位置：
+// 缩进大于 indent 的后续行属于该标量，普通标量在注释处结束，块标量（| 或 >）的注释也是内容
+func (w *yamlWalker) continuation(start, indent int, block bool) int {
+	c := w.content
+	end := lineValueEnd(c, start, !block)
+
+	for next := end; next < len(c); {
+		lineStart := strings.IndexByte(c[next:], '\n')
+		if lineStart < 0 {
+			break
+		}
+		lineStart += next + 1
+		line := c[lineStart:]
+		if i := strings.IndexByte(line, '\n'); i >= 0 {
+			line = line[:i]
+		}
+		next = lineStart + len(line)
+
+		trimmed := strings.TrimLeft(line, " \t")
+		if strings.TrimSpace(trimmed) == "" {
+			continue
+		}
+		if len(line)-len(trimmed) <= indent || (!block && trimmed[0] == '#') {
+			break
+		}
+		end = lineValueEnd(c, lineStart+len(line)-len(trimmed), !block)
+	}
+	return end
+}
+
+// offset 将节点的行列（从 1 开始，列按字符计数）转换为字节位置
+func (w *yamlWalker) offset(line, column int) int {
+	if line < 1 || line > len(w.lines) {
+		return len(w.content)
+	}
+	offset := w.lines[line-1]
+	for i := 1; i < column && offset < len(w.content); i++ {
+		_, size := utf8.DecodeRuneInString(w.content[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// lineOffsets 返回每行起始的字节位置
+func lineOffsets(content string) []int {
+	lines := []int{0}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	return lines
+}
+
+// lineValueEnd 返回从 start 开始到行尾（comments 为 true 时到注释前）的值的结束位置，不包括尾部空白
+func lineValueEnd(c string, start int, comments bool) int {
+	end := start
+	for end < len(c) && c[end] != '\n' && !(comments && isComment(c, end)) {
+		end++
+	}
+	return start + len(strings.TrimRight(c[start:end], " \t\r"))
+}
+
+// isComment 判断 i 处是否为注释开始：# 前面是空白
+func isComment(c string, i int) bool {
+	return c[i] == '#' && i > 0 && (c[i-1] == ' ' || c[i-1] == '\t')
+}
+
+// redactYamlLines YAML 无法解析时按行隐藏：敏感键的值及其下缩进更深的行，
+// 以及无法确定键名的 {} 和 [] 值
+func (r *Redactor) redactYamlLines(content string) string {
+	lines := strings.Split(content, "\n")
+	maskIndent := -1
+
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		indent := len(line) - len(trimmed)
+		if strings.TrimSpace(trimmed) == "" || trimmed[0] == '#' {
+			continue
+		}
+		if maskIndent >= 0 && (indent > maskIndent || (indent == maskIndent && strings.HasPrefix(trimmed, "- "))) {
+			lines[i] = line[:indent] + Masked
+			continue
+		}
+		maskIndent = -1
+
+		entry := trimmed
+		for strings.HasPrefix(entry, "- ") {
+			entry = strings.TrimLeft(entry[2:], " \t")
+		}
+		key, value, ok := strings.Cut(entry, ":")
+		if !ok || (value != "" && value[0] != ' ' && value[0] != '\t' && value[0] != '\r') {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		if r.Sensitive(strings.Trim(strings.TrimSpace(key), `"'`)) {
+			maskIndent = len(line) - len(entry)
+		} else if !strings.HasPrefix(value, "{") && !strings.HasPrefix(value, "[") {
+			continue
+		}
+		if value != "" {
+			lines[i] = line[:len(line)-len(entry)] + key + ": " + Masked
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// replaceSpans 将 content 中按位置排序的 spans 替换为 masked
+func replaceSpans(content string, spans [][2]int, masked string) string {
+	if len(spans) == 0 {
+		return content
+	}
+
+	out := strings.Builder{}
+	last := 0
+	for _, span := range spans {
+		out.WriteString(content[last:span[0]])
+		out.WriteString(masked)
+		last = span[1]
+	}
+	out.WriteString(content[last:])
+	return out.String()
+}
+
+// redactJson 按 token 遍历 JSON，将敏感键下的字符串和数字原地替换为 "******"
+func (r *Redactor) redactJson(content string) string {
+	if strings.TrimSpace(content) == "" {
+		return content
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.UseNumber()
+
+	// spans 需要替换的值在 content 中的位置
+	var spans [][2]int
+	if err := r.walkJson(decoder, content, false, &spans); err != nil {
+		return Masked
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return Masked
+	}
+
+	return replaceSpans(content, spans, `"`+Masked+`"`)
+}
+
+// walkJson 读取一个 JSON 值，masking 为 true 时记录其中所有字符串和数字的位置
+func (r *Redactor) walkJson(decoder *json.Decoder, content string, masking bool, spans *[][2]int) error {
+	start := int(decoder.InputOffset())
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			name, _ := key.(string)
+			if err := r.walkJson(decoder, content, masking || r.Sensitive(name), spans); err != nil {
+				return err
+			}
+		}
+		_, err = decoder.Token()
+		return err
+	case json.Delim('['):
+		for decoder.More() {
+			if err := r.walkJson(decoder, content, masking, spans); err != nil {
+				return err
+			}
+		}
+		_, err = decoder.Token()
+		return err
+	}
+
+	switch token.(type) {
+	case string, json.Number:
+		if masking {
+			// Token 跳过值前的空白、冒号和逗号，值从第一个有效字符开始
+			end := int(decoder.InputOffset())
+			start += strings.IndexFunc(content[start:end], func(c rune) bool {
+				return !strings.ContainsRune(" \t\r\n:,", c)
+			})
+			*spans = append(*spans, [2]int{start, end})
+		}
+	}
+	return nil
+}
+
+// redactProperties 隐藏敏感键的值，续行一并隐藏
+func (r *Redactor) redactProperties(content string) string {
+	lines := strings.Split(content, "\n")
+	masking := false
+
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		continued := strings.HasSuffix(strings.TrimRight(line, "\r"), "\\")
+
+		if masking {
+			lines[i] = line[:len(line)-len(trimmed)] + Masked
+			masking = continued
+			continue
+		}
+		if trimmed == "" || trimmed[0] == '#' || trimmed[0] == '!' {
+			continue
+		}
+
+		sep := strings.IndexAny(line, "=:")
+		if sep < 0 || !r.Sensitive(strings.TrimSpace(line[:sep])) {
+			continue
+		}
+		lines[i] = line[:sep+1] + Masked
+		masking = continued
+	}
+
+	return strings.Join(lines, "\n")
+}
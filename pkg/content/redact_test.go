@@ -0,0 +1,149 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	r, err := NewRedactor(DefaultSensitivePatterns)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		configType string
+		content    string
+		expected   string
+	}{
+		{
+			name:       "yaml",
+			configType: "yml",
+			content: `spring:
+  datasource:
+    url: jdbc:mysql://db/app
+    password: s3cr3t # prod
+    "apiToken": 'abc'
+  secrets:
+    - one
+    - name: two
+  private-key: |
+    -----BEGIN KEY-----
+    abc
+  port: 8080
+  maxKeys: 10
+`,
+			expected: `spring:
+  datasource:
+    url: jdbc:mysql://db/app
+    password: '******' # prod
+    "apiToken": '******'
+  secrets:
+    - '******'
+    - name: '******'
+  private-key: '******'
+  port: 8080
+  maxKeys: 10
+`,
+		},
+		{
+			name:       "yaml sequence at key indent",
+			configType: "yaml",
+			content:    "passwords:\n- hunter2\n- hunter3\nuser: app\n",
+			expected:   "passwords:\n- '******'\n- '******'\nuser: app\n",
+		},
+		{
+			name:       "yaml flow mapping",
+			configType: "yaml",
+			content:    "db: {password: hunter2, user: app}\nkeys: [a, b]\n",
+			expected:   "db: {password: '******', user: app}\nkeys: [a, b]\n",
+		},
+		{
+			name:       "yaml anchor and multiple documents",
+			configType: "yaml",
+			content:    "base: &pw hunter2\ndb:\n  password: *pw\n  enabled: true\n---\ntoken: abc\n",
+			expected:   "base: &pw '******'\ndb:\n  password: *pw\n  enabled: true\n---\ntoken: '******'\n",
+		},
+		{
+			name:       "yaml without sensitive keys unchanged",
+			configType: "yaml",
+			content:    "server:\n    port:   8080   # http\n    name: \"app\"\n\n\nlist: [ a,b ]\ncacheKey: users\n",
+			expected:   "server:\n    port:   8080   # http\n    name: \"app\"\n\n\nlist: [ a,b ]\ncacheKey: users\n",
+		},
+		{
+			name:       "yaml multi-line and quoted values",
+			configType: "yaml",
+			content:    "token: first\n  second # c\n\nname: \"中文\"\npassword: \"a\\\"b\n  c\"  # q\ntags: !!str 'it''s'\napiKey: >-\n  one\n\n  two\n",
+			expected:   "token: '******' # c\n\nname: \"中文\"\npassword: '******'  # q\ntags: !!str 'it''s'\napiKey: '******'\n",
+		},
+		{
+			name:       "yaml invalid",
+			configType: "yaml",
+			content:    "password: [hunter2\ndb:\n  user: app\n  secrets:\n    - a\n  pool: {size: 1\n",
+			expected:   "password: ******\ndb:\n  user: app\n  secrets:\n    ******\n  pool: ******\n",
+		},
+		{
+			name:       "json",
+			configType: "json",
+			content:    `{"db": {"user": "app", "password": "p\"w", "maxKeys": 10}, "token": null}`,
+			expected:   `{"db": {"user": "app", "password": "******", "maxKeys": 10}, "token": null}`,
+		},
+		{
+			name:       "json nested under sensitive key",
+			configType: "json",
+			content: `{
+  "secrets": {"db": "hunter2", "ttl": 30, "rotate": true},
+  "apiKey": ["a", "b"],
+  "name": "app"
+}`,
+			expected: `{
+  "secrets": {"db": "******", "ttl": "******", "rotate": true},
+  "apiKey": ["******", "******"],
+  "name": "app"
+}`,
+		},
+		{
+			name:       "json invalid",
+			configType: "json",
+			content:    `{"password": "hunter2"`,
+			expected:   Masked,
+		},
+		{
+			name:       "properties",
+			configType: "properties",
+			content:    "# password=comment\ndb.user=app\ndb.password = s3cr3t\napi.token:a\\\n  b\nport=80\ncache.maxKeys=10",
+			expected:   "# password=comment\ndb.user=app\ndb.password =******\napi.token:******\n  ******\nport=80\ncache.maxKeys=10",
+		},
+		{
+			name:       "text",
+			configType: "text",
+			content:    "password=s3cr3t",
+			expected:   "password=s3cr3t",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, r.Redact(tt.content, tt.configType))
+		})
+	}
+}
+
+func TestSensitive(t *testing.T) {
+	r, err := NewRedactor(DefaultSensitivePatterns)
+	require.NoError(t, err)
+
+	for _, key := range []string{"password", "dbPassword", "passwd", "secrets", "client_secret", "accessToken", "api-key", "api_key", "secretKey", "accessKey", "privateKey", "db.password", "secrets[0].name"} {
+		assert.True(t, r.Sensitive(key), key)
+	}
+	for _, key := range []string{"maxKeys", "keys", "tokenTtl", "passwordEncoder", "user", "spring.datasource.url", "monkey", "hotkey", "cacheKey", "primaryKey"} {
+		assert.False(t, r.Sensitive(key), key)
+	}
+
+	// 自定义模式同样需匹配完整的键名
+	r, err = NewRedactor([]string{"jwt.*"})
+	require.NoError(t, err)
+	assert.True(t, r.Sensitive("auth.jwtSigningKey"))
+	assert.False(t, r.Sensitive("myjwt"))
+}
@@ -0,0 +1,45 @@
+// Package credential 按服务器地址保存 Nacos 登录凭据。
+//
+// 默认保存在加密的本地文件中；也可以通过 credential helper 交给系统钥匙串等外部程序管理，
+// helper 协议与 docker credential helper 相同，可以直接使用 docker-credential-osxkeychain 等现有实现。
+package credential
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrNotFound 没有保存该服务器的凭据
+var ErrNotFound = errors.New("credentials not found")
+
+// Credential 一台服务器的登录凭据，Secret 为空表示只缓存了 token
+type Credential struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// Store 凭据存储
+type Store interface {
+	// Get 获取服务器的凭据，不存在时返回 ErrNotFound
+	Get(serverURL string) (*Credential, error)
+	// Store 保存凭据，覆盖该服务器已有的凭据
+	Store(credential *Credential) error
+	// Erase 删除服务器的凭据，不存在时不报错
+	Erase(serverURL string) error
+	// List 列出 nacosctl 保存的所有服务器及其用户名
+	List() (map[string]string, error)
+}
+
+// NewStore 创建凭据存储，helper 为空时使用加密的本地文件
+func NewStore(helper string) (Store, error) {
+	if helper != "" {
+		return NewHelperStore(helper, "")
+	}
+	return NewFileStore("")
+}
+
+// normalize 规范化服务器地址作为存储的键
+func normalize(serverURL string) string {
+	return strings.TrimRight(serverURL, "/")
+}
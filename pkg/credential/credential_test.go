@@ -0,0 +1,118 @@
+package credential
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStore(t *testing.T, store Store) {
+	_, err := store.Get("http://nacos:8848/nacos")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, store.Store(&Credential{ServerURL: "http://nacos:8848/nacos/", Username: "nacos", Secret: "s3cr3t"}))
+
+	credential, err := store.Get("http://nacos:8848/nacos")
+	require.NoError(t, err)
+	assert.Equal(t, &Credential{ServerURL: "http://nacos:8848/nacos", Username: "nacos", Secret: "s3cr3t"}, credential)
+
+	servers, err := store.List()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"http://nacos:8848/nacos": "nacos"}, servers)
+
+	require.NoError(t, store.Erase("http://nacos:8848/nacos"))
+	require.NoError(t, store.Erase("http://nacos:8848/nacos"))
+	_, err = store.Get("http://nacos:8848/nacos")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestFileStore(t *testing.T) {
+	t.Setenv(KeyEnv, "")
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Store(&Credential{ServerURL: "http://other", Username: "u", Secret: "plain-password"}))
+	data, err := os.ReadFile(filepath.Join(dir, credentialsFile))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "plain-password")
+	require.NoError(t, store.Erase("http://other"))
+
+	testStore(t, store)
+}
+
+// fakeHelper 使用文件模拟 docker credential helper，每个服务器一个文件
+const fakeHelper = `#!/bin/sh
+dir="$(dirname "$0")/store"
+mkdir -p "$dir"
+case "$1" in
+store)
+	input=$(cat)
+	server=$(printf '%s' "$input" | sed 's/.*"ServerURL":"\([^"]*\)".*/\1/')
+	printf '%s' "$input" > "$dir/$(printf '%s' "$server" | tr '/:' '__')"
+	;;
+get)
+	f="$dir/$(cat | tr '/:' '__')"
+	[ -f "$f" ] || { echo "credentials not found in native keychain"; exit 1; }
+	cat "$f"
+	;;
+erase)
+	f="$dir/$(cat | tr '/:' '__')"
+	[ -f "$f" ] || { echo "credentials not found in native keychain"; exit 1; }
+	rm "$f"
+	;;
+list)
+	printf '{'
+	sep=""
+	for f in "$dir"/*; do
+		[ -f "$f" ] || continue
+		sed 's/.*"ServerURL":"\([^"]*\)","Username":"\([^"]*\)".*/'"$sep"'"\1":"\2"/' "$f"
+		sep=","
+	done
+	printf '}'
+	;;
+esac
+`
+
+func TestHelperStore(t *testing.T) {
+	program := filepath.Join(t.TempDir(), "fake-helper")
+	require.NoError(t, os.WriteFile(program, []byte(fakeHelper), 0755))
+
+	store, err := NewHelperStore(program, t.TempDir())
+	require.NoError(t, err)
+	testStore(t, store)
+}
+
+func TestHelperStoreListsOwnServers(t *testing.T) {
+	dir := t.TempDir()
+	program := filepath.Join(dir, "fake-helper")
+	require.NoError(t, os.WriteFile(program, []byte(fakeHelper), 0755))
+
+	// helper 中已有 docker 保存的凭据
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "store"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "store", "https___index.docker.io_v1_"),
+		[]byte(`{"ServerURL":"https://index.docker.io/v1/","Username":"docker","Secret":"d"}`), 0600))
+
+	store, err := NewHelperStore(program, filepath.Join(dir, "home"))
+	require.NoError(t, err)
+	require.NoError(t, store.Store(&Credential{ServerURL: "http://nacos:8848/nacos", Username: "nacos", Secret: "s3cr3t"}))
+
+	servers, err := store.List()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"http://nacos:8848/nacos": "nacos"}, servers)
+}
+
+func TestNewHelperStore(t *testing.T) {
+	for name, program := range map[string]string{
+		"osxkeychain":            "docker-credential-osxkeychain",
+		"docker-credential-pass": "docker-credential-pass",
+		"/opt/helper":            "/opt/helper",
+	} {
+		store, err := NewHelperStore(name, t.TempDir())
+		require.NoError(t, err)
+		assert.Equal(t, program, store.program)
+	}
+}
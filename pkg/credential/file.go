@@ -0,0 +1,164 @@
+package credential
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/Talbot3/nacos-cli/pkg/encrypt"
+	"github.com/Talbot3/nacos-cli/pkg/util"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	credentialsFile = "credentials.enc"
+	keyFile         = "credentials.key"
+
+	// KeyEnv 加密凭据文件的 base64 主密钥，未设置时使用自动生成的 ~/.nacosctl/credentials.key
+	KeyEnv = "NACOS_CREDENTIAL_KEY"
+)
+
+// FileStore 加密的本地凭据文件，使用 AES-GCM 加密
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore 创建位于 dir 的凭据文件存储，dir 为空时使用 ~/.nacosctl
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(home, ".nacosctl")
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) Get(serverURL string) (*Credential, error) {
+	credentials, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	credential, ok := credentials[normalize(serverURL)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return credential, nil
+}
+
+func (s *FileStore) Store(credential *Credential) error {
+	credentials, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	stored := *credential
+	stored.ServerURL = normalize(credential.ServerURL)
+	credentials[stored.ServerURL] = &stored
+	return s.save(credentials)
+}
+
+func (s *FileStore) Erase(serverURL string) error {
+	credentials, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := credentials[normalize(serverURL)]; !ok {
+		return nil
+	}
+	delete(credentials, normalize(serverURL))
+	return s.save(credentials)
+}
+
+func (s *FileStore) List() (map[string]string, error) {
+	credentials, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(credentials))
+	for serverURL, credential := range credentials {
+		result[serverURL] = credential.Username
+	}
+	return result, nil
+}
+
+func (s *FileStore) load() (map[string]*Credential, error) {
+	credentials := map[string]*Credential{}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, credentialsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return credentials, nil
+		}
+		return nil, err
+	}
+
+	key, err := s.key(false)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := encrypt.Decrypt(key, string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(plaintext), &credentials); err != nil {
+		return nil, err
+	}
+	return credentials, nil
+}
+
+func (s *FileStore) save(credentials map[string]*Credential) error {
+	data, err := json.Marshal(credentials)
+	if err != nil {
+		return err
+	}
+
+	key, err := s.key(true)
+	if err != nil {
+		return err
+	}
+	encrypted, err := encrypt.Encrypt(key, string(data))
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+	return util.WriteFileAtomic(filepath.Join(s.dir, credentialsFile), []byte(encrypted), 0600)
+}
+
+// key 获取加密凭据文件的主密钥，create 为 true 且密钥文件不存在时自动生成
+func (s *FileStore) key(create bool) (encrypt.KeyProvider, error) {
+	if key := os.Getenv(KeyEnv); key != "" {
+		return encrypt.ParseKey([]byte(key))
+	}
+
+	path := filepath.Join(s.dir, keyFile)
+	provider, err := encrypt.KeyFromFile(path)
+	switch {
+	case err == nil:
+		return provider, nil
+	case !errors.Is(err, os.ErrNotExist) || !create:
+		return nil, err
+	}
+
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return nil, err
+	}
+	if err := util.WriteFileAtomic(path, []byte(base64.StdEncoding.EncodeToString(key)), 0600); err != nil {
+		return nil, err
+	}
+	return encrypt.NewLocalKeyProvider(key)
+}
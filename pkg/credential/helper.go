@@ -0,0 +1,178 @@
+package credential
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/util"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// helperPrefix credential helper 可执行文件的前缀，与 docker 一致
+const helperPrefix = "docker-credential-"
+
+// notFoundMessage helper 未找到凭据时输出的错误信息
+const notFoundMessage = "credentials not found in native keychain"
+
+// helperIndexFile 记录 nacosctl 通过 helper 保存的服务器，helper 中还有 docker 等其他程序的凭据
+const helperIndexFile = "credential-helpers.json"
+
+// HelperStore 通过外部 credential helper 程序管理凭据。
+// 协议与 docker credential helper 相同：程序以 store/get/erase/list 为参数，
+// 通过标准输入传入 JSON 凭据或服务器地址，通过标准输出返回结果。
+// helper 是多个程序共享的（如系统钥匙串中的 docker 登录），List 只返回 nacosctl 保存的服务器
+type HelperStore struct {
+	program string
+	dir     string // 保存服务器索引的目录
+}
+
+// NewHelperStore 创建 helper 存储，name 为 osxkeychain 等名称时执行 docker-credential-<name>，
+// 包含路径分隔符时视为可执行文件路径。dir 为保存服务器索引的目录，为空时使用 ~/.nacosctl
+func NewHelperStore(name, dir string) (*HelperStore, error) {
+	program := name
+	if !strings.Contains(name, "/") && !strings.HasPrefix(name, helperPrefix) {
+		program = helperPrefix + name
+	}
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(home, ".nacosctl")
+	}
+	return &HelperStore{program: program, dir: dir}, nil
+}
+
+func (s *HelperStore) Get(serverURL string) (*Credential, error) {
+	out, err := s.exec("get", []byte(normalize(serverURL)))
+	if err != nil {
+		return nil, err
+	}
+
+	credential := &Credential{}
+	if err := json.Unmarshal(out, credential); err != nil {
+		return nil, fmt.Errorf("%s get: invalid response: %w", s.program, err)
+	}
+	credential.ServerURL = normalize(serverURL)
+	return credential, nil
+}
+
+func (s *HelperStore) Store(credential *Credential) error {
+	stored := *credential
+	stored.ServerURL = normalize(credential.ServerURL)
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	if _, err := s.exec("store", data); err != nil {
+		return err
+	}
+	return s.updateIndex(stored.ServerURL, true)
+}
+
+func (s *HelperStore) Erase(serverURL string) error {
+	_, err := s.exec("erase", []byte(normalize(serverURL)))
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	return s.updateIndex(normalize(serverURL), false)
+}
+
+// List 列出 nacosctl 通过该 helper 保存的服务器，不包括其他程序保存的凭据
+func (s *HelperStore) List() (map[string]string, error) {
+	out, err := s.exec("list", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	all := map[string]string{}
+	if err := json.Unmarshal(out, &all); err != nil {
+		return nil, fmt.Errorf("%s list: invalid response: %w", s.program, err)
+	}
+
+	index, err := s.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]string{}
+	for _, serverURL := range index[s.program] {
+		if username, ok := all[serverURL]; ok {
+			result[serverURL] = username
+		}
+	}
+	return result, nil
+}
+
+// loadIndex 读取每个 helper 程序中由 nacosctl 保存的服务器
+func (s *HelperStore) loadIndex() (map[string][]string, error) {
+	index := map[string][]string{}
+	data, err := os.ReadFile(filepath.Join(s.dir, helperIndexFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return index, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", helperIndexFile, err)
+	}
+	return index, nil
+}
+
+// updateIndex 在索引中添加或删除服务器
+func (s *HelperStore) updateIndex(serverURL string, add bool) error {
+	index, err := s.loadIndex()
+	if err != nil {
+		return err
+	}
+
+	var servers []string
+	for _, server := range index[s.program] {
+		if server != serverURL {
+			servers = append(servers, server)
+		}
+	}
+	if add {
+		servers = append(servers, serverURL)
+	}
+	if len(servers) == len(index[s.program]) && !add {
+		return nil
+	}
+	if len(servers) == 0 {
+		delete(index, s.program)
+	} else {
+		index[s.program] = servers
+	}
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+	return util.WriteFileAtomic(filepath.Join(s.dir, helperIndexFile), data, 0600)
+}
+
+func (s *HelperStore) exec(action string, input []byte) ([]byte, error) {
+	cmd := exec.Command(s.program, action)
+	cmd.Stdin = bytes.NewReader(input)
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stdout.String() + stderr.String())
+		if strings.Contains(message, notFoundMessage) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("%s %s: %w: %s", s.program, action, err, message)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,204 @@
+// Package diff 提供按行比较文本并生成 unified diff 的功能
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines unified diff 中变更前后保留的上下文行数
+const contextLines = 3
+
+// Kind 行编辑类型
+type Kind int
+
+const (
+	Equal  Kind = iota // 两侧相同
+	Delete             // 仅存在于旧文本
+	Insert             // 仅存在于新文本
+)
+
+// Line 编辑序列中的一行
+type Line struct {
+	Kind Kind
+	Text string
+}
+
+// Lines 逐行比较两段文本，返回把 a 变为 b 的编辑序列
+func Lines(a, b string) []Line {
+	al, bl := splitLines(a), splitLines(b)
+
+	// 先去掉公共前后缀，配置变更通常只涉及少量行，可以显著缩小 LCS 的规模
+	prefix := 0
+	for prefix < len(al) && prefix < len(bl) && al[prefix] == bl[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(al)-prefix && suffix < len(bl)-prefix &&
+		al[len(al)-1-suffix] == bl[len(bl)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]Line, 0, len(al)+len(bl))
+	for _, text := range al[:prefix] {
+		lines = append(lines, Line{Kind: Equal, Text: text})
+	}
+	lines = append(lines, lcs(al[prefix:len(al)-suffix], bl[prefix:len(bl)-suffix])...)
+	for _, text := range al[len(al)-suffix:] {
+		lines = append(lines, Line{Kind: Equal, Text: text})
+	}
+	return lines
+}
+
+// Unified 生成 unified 格式的差异，内容相同时返回空字符串
+func Unified(fromName, toName, a, b string) string {
+	lines := Lines(a, b)
+
+	changed := false
+	for _, l := range lines {
+		if l.Kind != Equal {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	// aLine/bLine 记录每一行在旧/新文本中的行号（从 1 开始）
+	aLine := make([]int, len(lines))
+	bLine := make([]int, len(lines))
+	ai, bi := 1, 1
+	for i, l := range lines {
+		aLine[i], bLine[i] = ai, bi
+		if l.Kind != Insert {
+			ai++
+		}
+		if l.Kind != Delete {
+			bi++
+		}
+	}
+
+	for start := 0; start < len(lines); {
+		// 找到下一处变更
+		first := start
+		for first < len(lines) && lines[first].Kind == Equal {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+
+		// 向后扩展，直到连续相同的行超过两倍上下文
+		end := first
+		for i := first; i < len(lines); i++ {
+			if lines[i].Kind != Equal {
+				end = i + 1
+				continue
+			}
+			if i-end >= 2*contextLines {
+				break
+			}
+		}
+
+		from := first - contextLines
+		if from < start {
+			from = start
+		}
+		to := end + contextLines
+		if to > len(lines) {
+			to = len(lines)
+		}
+
+		var aCount, bCount int
+		for _, l := range lines[from:to] {
+			if l.Kind != Insert {
+				aCount++
+			}
+			if l.Kind != Delete {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aLine[from], aCount), hunkRange(bLine[from], bCount))
+		for _, l := range lines[from:to] {
+			switch l.Kind {
+			case Equal:
+				sb.WriteString(" ")
+			case Delete:
+				sb.WriteString("-")
+			case Insert:
+				sb.WriteString("+")
+			}
+			sb.WriteString(l.Text)
+			sb.WriteString("\n")
+		}
+		start = to
+	}
+
+	return sb.String()
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		// 空范围按惯例指向前一行
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// lcs 基于最长公共子序列计算编辑序列
+func lcs(a, b []string) []Line {
+	n, m := len(a), len(b)
+	table := make([][]int, n+1)
+	for i := range table {
+		table[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i][j] = table[i+1][j+1] + 1
+			} else if table[i+1][j] >= table[i][j+1] {
+				table[i][j] = table[i+1][j]
+			} else {
+				table[i][j] = table[i][j+1]
+			}
+		}
+	}
+
+	lines := make([]Line, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, Line{Kind: Equal, Text: a[i]})
+			i++
+			j++
+		case table[i+1][j] >= table[i][j+1]:
+			lines = append(lines, Line{Kind: Delete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, Line{Kind: Insert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		lines = append(lines, Line{Kind: Delete, Text: a[i]})
+	}
+	for ; j < m; j++ {
+		lines = append(lines, Line{Kind: Insert, Text: b[j]})
+	}
+	return lines
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
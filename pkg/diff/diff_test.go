@@ -0,0 +1,40 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"identical", "a\nb\n", "a\nb\n", ""},
+		{
+			"modify line",
+			"a\nb\nc\n",
+			"a\nB\nc\n",
+			"--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			"create",
+			"",
+			"a\n",
+			"--- old\n+++ new\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			"separate hunks",
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			"0\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n13\n",
+			"--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+0\n 2\n 3\n 4\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+13\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Unified("old", "new", tt.a, tt.b))
+		})
+	}
+}
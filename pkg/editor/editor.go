@@ -2,7 +2,7 @@ package editor
 
 import (
 	"fmt"
-	"github/szpinc/nacosctl/pkg/term"
+	"github.com/Talbot3/nacos-cli/pkg/term"
 	"io"
 	"os"
 	"os/exec"
@@ -26,6 +26,12 @@ const (
 type Editor struct {
 	Args  []string
 	Shell bool
+
+	// In, Out and ErrOut are the standard streams of the editor process,
+	// os.Stdin, os.Stdout and os.Stderr are used when nil.
+	In     io.Reader
+	Out    io.Writer
+	ErrOut io.Writer
 }
 
 // NewDefaultEditor creates a struct Editor that uses the OS environment to
@@ -100,12 +106,13 @@ func (e Editor) Launch(path string) error {
 		return err
 	}
 	args := e.args(abs)
+	in, out, errOut := e.streams()
 	cmd := exec.Command(args[0], args[1:]...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-	fmt.Printf("Opening file with editor %v\n", args)
-	if err := (term.TTY{In: os.Stdin, TryDev: true}).Safe(cmd.Run); err != nil {
+	cmd.Stdout = out
+	cmd.Stderr = errOut
+	cmd.Stdin = in
+	fmt.Fprintf(errOut, "Opening file with editor %v\n", args)
+	if err := (term.TTY{In: in, TryDev: true}).Safe(cmd.Run); err != nil {
 		if err, ok := err.(*exec.Error); ok {
 			if err.Err == exec.ErrNotFound {
 				return fmt.Errorf("unable to launch the editor %q", strings.Join(e.Args, " "))
@@ -116,6 +123,23 @@ func (e Editor) Launch(path string) error {
 	return nil
 }
 
+// streams returns the standard streams of the editor process, defaulting to
+// those of the current process.
+func (e Editor) streams() (io.Reader, io.Writer, io.Writer) {
+	var in io.Reader = os.Stdin
+	var out, errOut io.Writer = os.Stdout, os.Stderr
+	if e.In != nil {
+		in = e.In
+	}
+	if e.Out != nil {
+		out = e.Out
+	}
+	if e.ErrOut != nil {
+		errOut = e.ErrOut
+	}
+	return in, out, errOut
+}
+
 // LaunchTempFile reads the provided stream into a temporary file in the given directory
 // and file prefix, and then invokes Launch with the path of that file. It will return
 // the contents of the file after launch, any errors that occur, and the path of the
@@ -0,0 +1,222 @@
+// Package encrypt 在客户端对敏感配置进行信封加密。
+//
+// 每次加密生成随机的数据密钥，用 AES-GCM 加密配置内容，
+// 数据密钥再由 KeyProvider（本地主密钥或 KMS）加密。
+// 与 Nacos 加密插件的约定一致，配置内容只保存密文（base64 nonce+密文），
+// 加密后的数据密钥保存在配置的 encryptedDataKey 字段中，见 EncryptContent。
+//
+// 本地文件（如登录凭据）没有单独保存数据密钥的位置，使用 Encrypt 生成自包含的密文：
+//
+//	nacosctl:enc:v1:<base64 加密后的数据密钥>:<base64 nonce+密文>
+package encrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// CipherPrefix 需要加密的 dataId 前缀，与 Nacos 加密插件的约定一致
+	CipherPrefix = "cipher-"
+
+	// KeyEnv 保存 base64 编码主密钥的环境变量
+	KeyEnv = "NACOS_ENCRYPTION_KEY"
+	// KeyFileEnv 保存主密钥文件路径的环境变量
+	KeyFileEnv = "NACOS_ENCRYPTION_KEY_FILE"
+
+	envelopePrefix = "nacosctl:enc:v1:"
+	dataKeySize    = 32
+)
+
+var (
+	ErrNotEncrypted = errors.New("content is not encrypted")
+	ErrInvalidKey   = errors.New("encryption key must be 16, 24 or 32 bytes")
+)
+
+// KeyProvider 加解密数据密钥，可由 KMS 等外部密钥服务实现
+type KeyProvider interface {
+	EncryptDataKey(dataKey []byte) ([]byte, error)
+	DecryptDataKey(encrypted []byte) ([]byte, error)
+}
+
+// IsCipherDataId 判断 dataId 是否需要加密
+func IsCipherDataId(dataId string) bool {
+	return strings.HasPrefix(dataId, CipherPrefix)
+}
+
+// IsEncrypted 判断内容是否为 Encrypt 生成的自包含密文
+func IsEncrypted(content string) bool {
+	return strings.HasPrefix(content, envelopePrefix)
+}
+
+// EncryptContent 生成数据密钥加密配置内容，返回 base64 编码的密文和加密后的数据密钥，
+// 数据密钥发布到配置的 encryptedDataKey 字段
+func EncryptContent(provider KeyProvider, plaintext string) (content, encryptedDataKey string, err error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", "", err
+	}
+
+	sealed, err := seal(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", "", err
+	}
+
+	encryptedKey, err := provider.EncryptDataKey(dataKey)
+	if err != nil {
+		return "", "", fmt.Errorf("encrypt data key: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(sealed), base64.StdEncoding.EncodeToString(encryptedKey), nil
+}
+
+// DecryptContent 使用 encryptedDataKey 解密 EncryptContent 生成的密文
+func DecryptContent(provider KeyProvider, content, encryptedDataKey string) (string, error) {
+	if encryptedDataKey == "" {
+		return "", ErrNotEncrypted
+	}
+
+	encryptedKey, err := base64.StdEncoding.DecodeString(encryptedDataKey)
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted data key: %w", err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(content))
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted content: %w", err)
+	}
+
+	dataKey, err := provider.DecryptDataKey(encryptedKey)
+	if err != nil {
+		return "", fmt.Errorf("decrypt data key: %w", err)
+	}
+
+	plaintext, err := open(dataKey, sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Encrypt 加密内容，返回包含加密后数据密钥的自包含密文，用于本地文件
+func Encrypt(provider KeyProvider, plaintext string) (string, error) {
+	content, encryptedDataKey, err := EncryptContent(provider, plaintext)
+	if err != nil {
+		return "", err
+	}
+	return envelopePrefix + encryptedDataKey + ":" + content, nil
+}
+
+// Decrypt 解密 Encrypt 生成的密文
+func Decrypt(provider KeyProvider, content string) (string, error) {
+	if !IsEncrypted(content) {
+		return "", ErrNotEncrypted
+	}
+
+	encryptedDataKey, data, ok := strings.Cut(strings.TrimSpace(strings.TrimPrefix(content, envelopePrefix)), ":")
+	if !ok {
+		return "", errors.New("malformed encrypted content")
+	}
+	return DecryptContent(provider, data, encryptedDataKey)
+}
+
+// LocalKeyProvider 使用本地 AES 主密钥加密数据密钥
+type LocalKeyProvider struct {
+	key []byte
+}
+
+// NewLocalKeyProvider 创建本地主密钥，长度必须为 16、24 或 32 字节
+func NewLocalKeyProvider(key []byte) (*LocalKeyProvider, error) {
+	switch len(key) {
+	case 16, 24, 32:
+		return &LocalKeyProvider{key: key}, nil
+	}
+	return nil, ErrInvalidKey
+}
+
+// ParseKey 解析 base64 编码的主密钥，忽略首尾空白（如密钥文件末尾的换行）。
+// 不接受原始字节，避免恰好是合法 base64 的原始密钥被解码成另一个密钥
+func ParseKey(data []byte) (*LocalKeyProvider, error) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("encryption key must be base64 encoded: %w", err)
+	}
+	return NewLocalKeyProvider(decoded)
+}
+
+// KeyFromFile 从文件读取 base64 编码的主密钥
+func KeyFromFile(path string) (*LocalKeyProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKey(data)
+}
+
+// LoadKey 依次从 file 参数、NACOS_ENCRYPTION_KEY_FILE、NACOS_ENCRYPTION_KEY 加载主密钥，
+// 均未设置时返回 nil
+func LoadKey(file string) (KeyProvider, error) {
+	if file == "" {
+		file = os.Getenv(KeyFileEnv)
+	}
+	if file != "" {
+		return KeyFromFile(file)
+	}
+
+	if key := os.Getenv(KeyEnv); key != "" {
+		return ParseKey([]byte(key))
+	}
+	return nil, nil
+}
+
+func (p *LocalKeyProvider) EncryptDataKey(dataKey []byte) ([]byte, error) {
+	return seal(p.key, dataKey)
+}
+
+func (p *LocalKeyProvider) DecryptDataKey(encrypted []byte) ([]byte, error) {
+	return open(p.key, encrypted)
+}
+
+// seal 使用 AES-GCM 加密，结果为 nonce+密文
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("malformed ciphertext")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt failed, wrong key or corrupted content: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
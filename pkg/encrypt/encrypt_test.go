@@ -0,0 +1,93 @@
+package encrypt
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	provider, err := NewLocalKeyProvider([]byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+
+	encrypted, err := Encrypt(provider, "password: s3cr3t\n")
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.NotContains(t, encrypted, "s3cr3t")
+
+	plaintext, err := Decrypt(provider, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "password: s3cr3t\n", plaintext)
+
+	other, err := NewLocalKeyProvider([]byte("fedcba9876543210"))
+	require.NoError(t, err)
+	_, err = Decrypt(other, encrypted)
+	assert.Error(t, err)
+
+	_, err = Decrypt(provider, "plain")
+	assert.ErrorIs(t, err, ErrNotEncrypted)
+}
+
+func TestEncryptContent(t *testing.T) {
+	provider, err := NewLocalKeyProvider([]byte("0123456789abcdef"))
+	require.NoError(t, err)
+
+	content, dataKey, err := EncryptContent(provider, "password: s3cr3t\n")
+	require.NoError(t, err)
+	assert.NotEmpty(t, dataKey)
+	assert.False(t, IsEncrypted(content), "内容只包含密文")
+	_, err = base64.StdEncoding.DecodeString(content)
+	assert.NoError(t, err)
+
+	plaintext, err := DecryptContent(provider, content, dataKey)
+	require.NoError(t, err)
+	assert.Equal(t, "password: s3cr3t\n", plaintext)
+
+	_, err = DecryptContent(provider, content, "")
+	assert.ErrorIs(t, err, ErrNotEncrypted)
+}
+
+func TestKeyFromFile(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	path := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600))
+
+	provider, err := KeyFromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, key, provider.key)
+
+	_, err = ParseKey([]byte(base64.StdEncoding.EncodeToString([]byte("short"))))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	// 只接受 base64，不猜测是否为原始字节
+	provider, err = ParseKey([]byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+	assert.Len(t, provider.key, 24)
+	_, err = ParseKey([]byte("0123456789abcde!"))
+	assert.Error(t, err)
+}
+
+func TestIsCipherDataId(t *testing.T) {
+	assert.True(t, IsCipherDataId("cipher-aes-db.yaml"))
+	assert.False(t, IsCipherDataId("db.yaml"))
+}
+
+func TestLoadKey(t *testing.T) {
+	t.Setenv(KeyFileEnv, "")
+	t.Setenv(KeyEnv, "")
+	provider, err := LoadKey("")
+	require.NoError(t, err)
+	assert.Nil(t, provider)
+
+	t.Setenv(KeyEnv, base64.StdEncoding.EncodeToString([]byte("0123456789abcdef")))
+	provider, err = LoadKey("")
+	require.NoError(t, err)
+	assert.NotNil(t, provider)
+
+	_, err = LoadKey(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
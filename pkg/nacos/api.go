@@ -0,0 +1,202 @@
+package nacos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// 支持的 Open API 版本，对应 NACOS_API_VERSION / 上下文中的 apiVersion
+const (
+	ApiVersionAuto = "auto" // 根据服务端版本自动选择
+	ApiVersionV1   = "v1"   // Nacos 1.x 及以上
+	ApiVersionV2   = "v2"   // Nacos 2.2 及以上的 /v2/cs/config
+	ApiVersionV3   = "v3"   // Nacos 3.x 的 /v3/admin/cs
+)
+
+// codeResourceNotFound v2/v3 接口中资源不存在的错误码
+const codeResourceNotFound = 20004
+
+// casConflictMessage Nacos 各版本接口（包括 gRPC）在 casMd5 冲突时返回的错误消息前缀
+const casConflictMessage = "Cas publish fail"
+
+// ErrUnsupportedOnV3 功能只有 v1 接口，服务端使用 v3 Open API（Nacos 3.x）时不可用
+var ErrUnsupportedOnV3 = errors.New("not supported by the v3 Open API")
+
+// configAPI 配置接口在不同 Open API 版本下的实现。
+// 命令只调用 Client 的方法，由 Client 根据服务端版本选择实现
+type configAPI interface {
+	// get 获取配置内容
+	get(ctx context.Context, operation ConfigGetOperation) (*NacosConfigDetail, error)
+	// detail 获取配置内容及全部元数据
+	detail(ctx context.Context, operation ConfigGetOperation) (*NacosConfigDetail, error)
+	// page 获取配置列表的一页
+	page(ctx context.Context, operation ConfigGetOperation, pageNo int) (*NacosPageResult, error)
+	// publish 发布配置
+	publish(ctx context.Context, request publishRequest) error
+	// delete 删除配置
+	delete(ctx context.Context, operation ConfigDeleteOperation) error
+}
+
+// configListener 自带配置监听方式的接口实现，未实现时使用 HTTP 长轮询
+type configListener interface {
+	// listen 监听配置变更，返回 MD5 与 states 不一致的配置
+	listen(ctx context.Context, keys []ConfigKey, states map[ConfigKey]*NacosConfigDetail) ([]ConfigKey, error)
+}
+
+// publishRequest 发布配置的请求，Metadata 已与服务器上的值合并，所有字段非 nil
+type publishRequest struct {
+	*NacosOperation
+	DataId   string
+	Content  string
+	Type     string
+	Metadata ConfigMetadata
+	BetaIps  []string
+	CasMd5   string
+
+	EncryptedDataKey string // 客户端加密时的数据密钥，为空时不发送
+}
+
+// api 获取配置接口实现，apiVersion 为 auto 时首次调用探测服务端版本。
+// ctx 在探测期间被取消时探测结果不可信，不缓存，下次调用重新探测
+func (c *Client) api(ctx context.Context) configAPI {
+	c.apiMu.Lock()
+	defer c.apiMu.Unlock()
+
+	if c.configAPI != nil {
+		return c.configAPI
+	}
+
+	version := c.Config.ApiVersion
+	if version == "" || version == ApiVersionAuto {
+		version = c.detectVersion(ctx)
+		if ctx.Err() != nil {
+			return v1API{c}
+		}
+	}
+
+	switch version {
+	case ApiVersionV3:
+		c.configAPI = v3API{c}
+	case ApiVersionV2:
+		c.configAPI = v2API{v1API{c}}
+	default:
+		c.configAPI = v1API{c}
+	}
+
+	if c.Config.Transport == TransportGRPC {
+		c.configAPI = grpcAPI{configAPI: c.configAPI, conn: newGrpcConn(c)}
+	}
+	return c.configAPI
+}
+
+// Close 释放客户端持有的连接（gRPC 传输），HTTP 传输无需关闭
+func (c *Client) Close() error {
+	c.apiMu.Lock()
+	defer c.apiMu.Unlock()
+
+	if closer, ok := c.configAPI.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// detectVersion 查询服务端版本并选择 Open API 版本，查询失败时使用 v1
+func (c *Client) detectVersion(ctx context.Context) string {
+	// 2.x 的 v1 控制台接口直接返回状态，3.x 的 v3 接口返回 {code,message,data}
+	for _, path := range []string{"/v1/console/server/state", "/v3/admin/core/state"} {
+		if version := c.serverVersion(ctx, path); version != "" {
+			return apiVersionOf(version)
+		}
+	}
+	return ApiVersionV1
+}
+
+// serverVersion 从服务端状态接口读取版本号，失败时返回空字符串
+func (c *Client) serverVersion(ctx context.Context, path string) string {
+	stateUrl, err := serverPath(path)
+	if err != nil {
+		return ""
+	}
+
+	resp, body, err := c.send(ctx, http.MethodGet, stateUrl, nil, nil)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return ""
+	}
+
+	state := struct {
+		Version string `json:"version"`
+		Data    struct {
+			Version string `json:"version"`
+		} `json:"data"`
+	}{}
+	if err := json.Unmarshal(body, &state); err != nil {
+		return ""
+	}
+	if state.Version != "" {
+		return state.Version
+	}
+	return state.Data.Version
+}
+
+// apiVersionOf 根据服务端版本号选择 Open API 版本：
+// 3.x 使用 v3，2.2 及以上使用 v2，其余使用 v1
+func apiVersionOf(version string) string {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return ApiVersionV1
+	}
+	minor := 0
+	if len(parts) > 1 {
+		minor, _ = strconv.Atoi(parts[1])
+	}
+
+	switch {
+	case major >= 3:
+		return ApiVersionV3
+	case major == 2 && minor >= 2:
+		return ApiVersionV2
+	}
+	return ApiVersionV1
+}
+
+// decodeResult 解析 v2/v3 接口的 {code,message,data} 响应，
+// HTTP 404 或错误码 20004 视为配置不存在
+func decodeResult[T any](resp *http.Response, body []byte) (T, error) {
+	result := restResult[T]{}
+	if resp.StatusCode == http.StatusNotFound {
+		return result.Data, fmt.Errorf("%w: %s", ErrConfigNotExist, body)
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		if casConflict(string(body)) {
+			return result.Data, fmt.Errorf("%w: %s", ErrConflict, body)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return result.Data, fmt.Errorf("response error,status code:%d\n%s", resp.StatusCode, body)
+		}
+		return result.Data, err
+	}
+
+	switch {
+	case result.Code == codeResourceNotFound:
+		return result.Data, fmt.Errorf("%w: %s", ErrConfigNotExist, result.Message)
+	case casConflict(result.Message):
+		return result.Data, fmt.Errorf("%w: %s", ErrConflict, result.Message)
+	case result.Code != 0 || resp.StatusCode != http.StatusOK:
+		return result.Data, fmt.Errorf("response error,status code:%d,code:%d\n%s", resp.StatusCode, result.Code, result.Message)
+	}
+
+	return result.Data, nil
+}
+
+// casConflict 判断错误消息是否表示 casMd5 与服务器上的 MD5 不一致导致的发布失败
+func casConflict(message string) bool {
+	return strings.Contains(message, casConflictMessage)
+}
@@ -0,0 +1,181 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// grpc 配置请求的错误码
+const (
+	grpcConfigNotFound = 300
+)
+
+// grpcAPI 通过 Nacos 2.x gRPC 端口（默认 9848）查询、发布、删除和监听配置。
+// gRPC 协议没有列表和元数据查询请求，这两项使用 HTTP 接口
+type grpcAPI struct {
+	configAPI
+	conn *grpcConn
+}
+
+type grpcConfigRequest struct {
+	grpcRequest
+	DataId string `json:"dataId"`
+	Group  string `json:"group"`
+	Tenant string `json:"tenant"`
+	Tag    string `json:"tag"`
+}
+
+type grpcConfigContext struct {
+	DataId string `json:"dataId"`
+	Group  string `json:"group"`
+	Tenant string `json:"tenant"`
+	Md5    string `json:"md5,omitempty"`
+}
+
+func newConfigRequest(namespace, group, dataId string) grpcConfigRequest {
+	return grpcConfigRequest{
+		grpcRequest: grpcRequest{Module: "config"},
+		DataId:      dataId,
+		Group:       group,
+		Tenant:      tenantOf(namespace),
+	}
+}
+
+func (a grpcAPI) get(ctx context.Context, operation ConfigGetOperation) (*NacosConfigDetail, error) {
+	request := newConfigRequest(operation.Namespace, operation.Group, operation.DataId)
+	resp := struct {
+		Content          string `json:"content"`
+		EncryptedDataKey string `json:"encryptedDataKey"`
+		ContentType      string `json:"contentType"`
+		Md5              string `json:"md5"`
+		LastModified     int64  `json:"lastModified"`
+	}{}
+
+	err := a.conn.request(ctx, "ConfigQueryRequest", request.Tenant, request.Group, request, &resp)
+	var respErr *grpcResponseError
+	if errors.As(err, &respErr) && respErr.ErrorCode == grpcConfigNotFound {
+		return nil, ErrConfigNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &NacosConfigDetail{
+		DataID:           operation.DataId,
+		Group:            operation.Group,
+		Tenant:           operation.Namespace,
+		Content:          resp.Content,
+		Md5:              resp.Md5,
+		EncryptedDataKey: resp.EncryptedDataKey,
+		Type:             resp.ContentType,
+		ModifyTime:       resp.LastModified,
+	}, nil
+}
+
+func (a grpcAPI) publish(ctx context.Context, request publishRequest) error {
+	metadata := request.Metadata
+	additions := map[string]string{
+		"type":        request.Type,
+		"desc":        *metadata.Desc,
+		"config_tags": strings.Join(metadata.Tags, ","),
+		"appName":     *metadata.AppName,
+		"use":         *metadata.Use,
+		"effect":      *metadata.Effect,
+		"schema":      *metadata.Schema,
+	}
+	if len(request.BetaIps) > 0 {
+		additions["betaIps"] = strings.Join(request.BetaIps, ",")
+	}
+	if request.EncryptedDataKey != "" {
+		additions["encryptedDataKey"] = request.EncryptedDataKey
+	}
+
+	body := struct {
+		grpcConfigRequest
+		Content     string            `json:"content"`
+		CasMd5      string            `json:"casMd5"`
+		AdditionMap map[string]string `json:"additionMap"`
+	}{
+		grpcConfigRequest: newConfigRequest(request.Namespace, request.Group, request.DataId),
+		Content:           request.Content,
+		CasMd5:            request.CasMd5,
+		AdditionMap:       additions,
+	}
+
+	err := a.conn.request(ctx, "ConfigPublishRequest", body.Tenant, body.Group, body, &grpcResponse{})
+	var respErr *grpcResponseError
+	if errors.As(err, &respErr) && casConflict(respErr.Message) {
+		return fmt.Errorf("%w: %s", ErrConflict, respErr.Message)
+	}
+	return err
+}
+
+func (a grpcAPI) delete(ctx context.Context, operation ConfigDeleteOperation) error {
+	request := newConfigRequest(operation.Namespace, operation.Group, operation.DataId)
+	return a.conn.request(ctx, "ConfigRemoveRequest", request.Tenant, request.Group, request, &grpcResponse{})
+}
+
+// listen 通过 ConfigBatchListen 注册监听并比较 MD5，内容一致时等待服务端推送的变更通知。
+// 连接断开时返回，下次调用在新连接上重新注册
+func (a grpcAPI) listen(ctx context.Context, keys []ConfigKey, states map[ConfigKey]*NacosConfigDetail) ([]ConfigKey, error) {
+	body := struct {
+		grpcRequest
+		Listen               bool                `json:"listen"`
+		ConfigListenContexts []grpcConfigContext `json:"configListenContexts"`
+	}{
+		grpcRequest: grpcRequest{Module: "config"},
+		Listen:      true,
+	}
+	for _, key := range keys {
+		body.ConfigListenContexts = append(body.ConfigListenContexts, grpcConfigContext{
+			DataId: key.DataId,
+			Group:  key.Group,
+			Tenant: tenantOf(key.Namespace),
+			Md5:    configMd5(states[key]),
+		})
+	}
+
+	// 在注册监听前订阅，避免漏掉注册后立即推送的变更
+	notify, unsubscribe := a.conn.subscribe(keys)
+	defer unsubscribe()
+
+	resp := struct {
+		ChangedConfigs []grpcConfigContext `json:"changedConfigs"`
+	}{}
+	if err := a.conn.request(ctx, "ConfigBatchListenRequest", "", "", body, &resp); err != nil {
+		return nil, err
+	}
+	// 监听注册在当前连接上，连接断开后需要重新注册
+	lost := a.conn.lostCh()
+
+	var changed []ConfigKey
+	for _, config := range resp.ChangedConfigs {
+		changed = append(changed, matchKeys(keys, config.DataId, config.Group, config.Tenant)...)
+	}
+	if len(changed) > 0 {
+		return changed, nil
+	}
+
+	timeout := time.NewTimer(longPollingTimeout)
+	defer timeout.Stop()
+	for {
+		select {
+		case key := <-notify:
+			return matchKeys(keys, key.DataId, key.Group, key.Namespace), nil
+		case <-timeout.C:
+			return nil, nil
+		case <-lost:
+			return nil, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Close 关闭 gRPC 连接
+func (a grpcAPI) Close() error {
+	return a.conn.Close()
+}
@@ -0,0 +1,184 @@
+package nacos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApiVersionOf(t *testing.T) {
+	assert.Equal(t, ApiVersionV1, apiVersionOf("1.4.6"))
+	assert.Equal(t, ApiVersionV1, apiVersionOf("2.1.2"))
+	assert.Equal(t, ApiVersionV2, apiVersionOf("2.4.0"))
+	assert.Equal(t, ApiVersionV3, apiVersionOf("3.0.1"))
+	assert.Equal(t, ApiVersionV1, apiVersionOf(""))
+}
+
+func TestDetectVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		expected configAPI
+	}{
+		{
+			name: "v2",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/v1/console/server/state" {
+					_, _ = w.Write([]byte(`{"version":"2.4.0","standalone_mode":"standalone"}`))
+					return
+				}
+				http.NotFound(w, r)
+			},
+			expected: v2API{},
+		},
+		{
+			name: "v3",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/v3/admin/core/state" {
+					_, _ = w.Write([]byte(`{"code":0,"message":"success","data":{"version":"3.0.1"}}`))
+					return
+				}
+				http.NotFound(w, r)
+			},
+			expected: v3API{},
+		},
+		{
+			name:     "unknown",
+			handler:  http.NotFound,
+			expected: v1API{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			client := NewClient(server.URL, "", "", "")
+			assert.IsType(t, tt.expected, client.api(context.Background()))
+		})
+	}
+}
+
+func TestV2API(t *testing.T) {
+	var published url.Values
+	deleted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/cs/configs":
+			// v2 没有元数据查询接口，发布前通过 v1 读取
+			_, _ = w.Write([]byte(`{"dataId":"app.yaml","group":"G","content":"a: 1","type":"yaml","desc":"orders"}`))
+		case r.URL.Path != "/v2/cs/config":
+			http.NotFound(w, r)
+		case r.Method == http.MethodGet && r.URL.Query().Get("dataId") == "missing.yaml":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":20004,"message":"resource not found","data":"config data not exist"}`))
+		case r.Method == http.MethodGet:
+			assert.Equal(t, "dev", r.URL.Query().Get("namespaceId"))
+			_, _ = w.Write([]byte(`{"code":0,"message":"success","data":"a: 1"}`))
+		case r.Method == http.MethodPost:
+			_ = r.ParseForm()
+			published = r.PostForm
+			_, _ = w.Write([]byte(`{"code":0,"message":"success","data":true}`))
+		case r.Method == http.MethodDelete:
+			deleted = true
+			_, _ = w.Write([]byte(`{"code":0,"message":"success","data":true}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, ApiVersionV2, "", "")
+	op := &NacosOperation{Namespace: "dev", Group: "G"}
+
+	detail, err := client.Get(ConfigGetOperation{NacosOperation: op, DataId: "app.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, "a: 1", detail.Content)
+	assert.NotEmpty(t, detail.Md5)
+
+	_, err = client.Get(ConfigGetOperation{NacosOperation: op, DataId: "missing.yaml"})
+	assert.ErrorIs(t, err, ErrConfigNotExist)
+
+	require.NoError(t, client.Edit(ConfigEditOperation{NacosOperation: op, DataId: "app.yaml", Content: "a: 2"}))
+	assert.Equal(t, "a: 2", published.Get("content"))
+	assert.Equal(t, "dev", published.Get("namespaceId"))
+	assert.Equal(t, "yaml", published.Get("type"))
+	assert.Equal(t, "orders", published.Get("desc"))
+
+	require.NoError(t, client.DeleteConfig(ConfigDeleteOperation{NacosOperation: op, DataId: "app.yaml"}))
+	assert.True(t, deleted)
+}
+
+func TestV3API(t *testing.T) {
+	var published url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case r.URL.Path == "/v3/admin/cs/config/list":
+			assert.Equal(t, "public", query.Get("namespaceId"))
+			assert.Equal(t, "blur", query.Get("search"))
+			_, _ = w.Write([]byte(`{"code":0,"data":{"totalCount":1,"pageNumber":1,"pagesAvailable":1,` +
+				`"pageItems":[{"dataId":"app.yaml","groupName":"G","namespaceId":"public","type":"yaml"}]}}`))
+		case r.URL.Path != "/v3/admin/cs/config":
+			http.NotFound(w, r)
+		case r.Method == http.MethodGet && query.Get("dataId") == "missing.yaml":
+			_, _ = w.Write([]byte(`{"code":20004,"message":"config data not exist","data":null}`))
+		case r.Method == http.MethodGet:
+			assert.Equal(t, "G", query.Get("groupName"))
+			_, _ = w.Write([]byte(`{"code":0,"data":{"dataId":"app.yaml","groupName":"G","namespaceId":"public",` +
+				`"content":"a: 1","type":"yaml","desc":"orders","configTags":"tier=prod"}}`))
+		case r.Method == http.MethodPost:
+			_ = r.ParseForm()
+			published = r.PostForm
+			_, _ = w.Write([]byte(`{"code":0,"data":true}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, ApiVersionV3, "", "")
+	op := &NacosOperation{Namespace: "public", Group: "G"}
+
+	detail, err := client.Detail(ConfigGetOperation{NacosOperation: op, DataId: "app.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, "G", detail.Group)
+	assert.Equal(t, "", detail.Tenant)
+	assert.Equal(t, "tier=prod", detail.ConfigTags)
+
+	_, err = client.Get(ConfigGetOperation{NacosOperation: op, DataId: "missing.yaml"})
+	assert.ErrorIs(t, err, ErrConfigNotExist)
+
+	items, err := client.AllConfig(ConfigGetOperation{NacosOperation: &NacosOperation{Namespace: "public"}})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "G", items[0].Group)
+
+	require.NoError(t, client.Edit(ConfigEditOperation{NacosOperation: op, DataId: "app.yaml", Content: "a: 2"}))
+	assert.Equal(t, "G", published.Get("groupName"))
+	assert.Equal(t, "public", published.Get("namespaceId"))
+	assert.Equal(t, "tier=prod", published.Get("configTags"))
+}
+
+func TestV1OnlyFeaturesOnV3(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, ApiVersionV3, "", "")
+	operation := &NacosOperation{Namespace: "public", Group: "DEFAULT_GROUP"}
+
+	_, err := client.GetBeta(ConfigGetOperation{NacosOperation: operation, DataId: "app.yaml"})
+	assert.ErrorIs(t, err, ErrUnsupportedOnV3)
+	err = client.StopBeta(ConfigDeleteOperation{NacosOperation: operation, DataId: "app.yaml"})
+	assert.ErrorIs(t, err, ErrUnsupportedOnV3)
+	_, err = client.Export(ConfigExportOperation{NacosOperation: operation})
+	assert.ErrorIs(t, err, ErrUnsupportedOnV3)
+	_, err = client.Import(ConfigImportOperation{NacosOperation: operation, Items: []ConfigArchiveItem{{DataId: "app.yaml", Group: "DEFAULT_GROUP"}}})
+	assert.ErrorIs(t, err, ErrUnsupportedOnV3)
+	_, err = client.Watch(context.Background(), ConfigKey{Group: "DEFAULT_GROUP", DataId: "app.yaml"})
+	assert.ErrorIs(t, err, ErrUnsupportedOnV3)
+}
@@ -0,0 +1,220 @@
+package nacos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// v1API Nacos 1.x 及以上的 /v1/cs/configs 接口
+type v1API struct {
+	c *Client
+}
+
+func (a v1API) get(ctx context.Context, operation ConfigGetOperation) (*NacosConfigDetail, error) {
+	configUrl, err := getUrl()
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("dataId", operation.DataId)
+	query.Set("group", operation.Group)
+	// Nacos API 中 public 命名空间用空字符串表示
+	query.Set("tenant", tenantOf(operation.Namespace))
+
+	resp, body, err := a.c.send(ctx, http.MethodGet, configUrl+"?"+query.Encode(), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusForbidden && len(body) == 0:
+		// 配置不存在时返回 403
+		return nil, ErrConfigNotExist
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrConfigNotExist, body)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("response error,status code:%d\n%s", resp.StatusCode, body)
+	}
+
+	return parseConfigDetail(operation, resp, body)
+}
+
+// parseConfigDetail 解析配置查询的响应
+func parseConfigDetail(operation ConfigGetOperation, resp *http.Response, body []byte) (*NacosConfigDetail, error) {
+	// 检查是否为空响应（配置不存在）
+	if len(body) == 0 {
+		return nil, ErrConfigNotExist
+	}
+
+	// 检查 Content-Type，如果是 text/plain，直接返回内容
+	contentType := resp.Header.Get("Content-Type")
+	if strings.Contains(contentType, "text/plain") {
+		return &NacosConfigDetail{
+			DataID:  operation.DataId,
+			Group:   operation.Group,
+			Tenant:  operation.Namespace,
+			Content: string(body),
+			Md5:     resp.Header.Get("Content-MD5"),
+			Type:    resp.Header.Get("Config-Type"),
+
+			EncryptedDataKey: resp.Header.Get(encryptedDataKeyHeader),
+		}, nil
+	}
+
+	detail := NacosConfigDetail{}
+
+	if err := json.Unmarshal(body, &detail); err != nil {
+		return nil, err
+	}
+
+	return &detail, nil
+}
+
+func (a v1API) detail(ctx context.Context, operation ConfigGetOperation) (*NacosConfigDetail, error) {
+	configUrl, err := getUrl()
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("show", "all")
+	query.Set("dataId", operation.DataId)
+	query.Set("group", operation.Group)
+	query.Set("tenant", tenantOf(operation.Namespace))
+
+	resp, body, err := a.c.send(ctx, http.MethodGet, configUrl+"?"+query.Encode(), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusForbidden && len(body) == 0:
+		return nil, ErrConfigNotExist
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("response error,status code:%d\n%s", resp.StatusCode, body)
+	case len(body) == 0:
+		return nil, ErrConfigNotExist
+	}
+
+	detail := NacosConfigDetail{}
+	if err := json.Unmarshal(body, &detail); err != nil {
+		return nil, err
+	}
+
+	return &detail, nil
+}
+
+func (a v1API) page(ctx context.Context, operation ConfigGetOperation, pageNo int) (*NacosPageResult, error) {
+	configUrl, err := getUrl()
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("dataId", "")
+	query.Set("group", operation.Group)
+	query.Set("tenant", tenantOf(operation.Namespace))
+	query.Set("pageNo", fmt.Sprint(pageNo))
+	query.Set("pageSize", fmt.Sprint(listPageSize))
+	query.Set("search", "accurate")
+
+	resp, body, err := a.c.send(ctx, http.MethodGet, configUrl+"?"+query.Encode(), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("response error,status code:%d", resp.StatusCode)
+	}
+
+	result := NacosPageResult{}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (a v1API) publish(ctx context.Context, request publishRequest) error {
+	configUrl, err := getUrl()
+	if err != nil {
+		return err
+	}
+
+	metadata := request.Metadata
+	formData := url.Values{
+		"dataId":      []string{request.DataId},
+		"group":       []string{request.Group},
+		"content":     []string{request.Content},
+		"tenant":      []string{tenantOf(request.Namespace)},
+		"type":        []string{request.Type},
+		"desc":        []string{*metadata.Desc},
+		"config_tags": []string{strings.Join(metadata.Tags, ",")},
+		"appName":     []string{*metadata.AppName},
+		"use":         []string{*metadata.Use},
+		"effect":      []string{*metadata.Effect},
+		"schema":      []string{*metadata.Schema},
+	}
+	if request.CasMd5 != "" {
+		formData.Set("casMd5", request.CasMd5)
+	}
+	if request.EncryptedDataKey != "" {
+		formData.Set("encryptedDataKey", request.EncryptedDataKey)
+	}
+
+	resp, body, err := a.c.sendForm(ctx, http.MethodPost, configUrl, betaHeader(request.BetaIps), formData)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK && casConflict(string(body)) {
+		return fmt.Errorf("%w: %s", ErrConflict, body)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("response error,status code:%d\n%s", resp.StatusCode, body)
+	}
+
+	return nil
+}
+
+func (a v1API) delete(ctx context.Context, operation ConfigDeleteOperation) error {
+	configUrl, err := getUrl()
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("dataId", operation.DataId)
+	query.Set("group", operation.Group)
+	// Nacos API 中 public 命名空间用空字符串表示
+	// 但删除时不应该包含 tenant 参数（而不是传空字符串）
+	if tenant := tenantOf(operation.Namespace); tenant != "" {
+		query.Set("tenant", tenant)
+	}
+
+	resp, _, err := a.c.send(ctx, http.MethodDelete, configUrl+"?"+query.Encode(), nil, nil)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("response error,status code:%d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// betaHeader 构造灰度发布的 betaIps 请求头，没有灰度 IP 时返回空请求头
+func betaHeader(betaIps []string) http.Header {
+	header := http.Header{}
+	if len(betaIps) > 0 {
+		header.Set(betaIpsHeader, strings.Join(betaIps, ","))
+	}
+	return header
+}
@@ -0,0 +1,113 @@
+package nacos
+
+import (
+	"context"
+	"github.com/Talbot3/nacos-cli/pkg/util"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const v2ConfigUrl = "/v2/cs/config"
+
+// v2API Nacos 2.2 及以上的 /v2/cs/config 接口。
+// v2 没有列表和元数据查询接口，这两项沿用 v1
+type v2API struct {
+	v1API
+}
+
+func (a v2API) url(query url.Values) (string, error) {
+	configUrl, err := serverPath(v2ConfigUrl)
+	if err != nil {
+		return "", err
+	}
+	if query != nil {
+		configUrl += "?" + query.Encode()
+	}
+	return configUrl, nil
+}
+
+func (a v2API) get(ctx context.Context, operation ConfigGetOperation) (*NacosConfigDetail, error) {
+	configUrl, err := a.url(url.Values{
+		"dataId":      []string{operation.DataId},
+		"group":       []string{operation.Group},
+		"namespaceId": []string{tenantOf(operation.Namespace)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, body, err := a.c.send(ctx, http.MethodGet, configUrl, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// v2 只返回配置内容，MD5 在本地计算
+	content, err := decodeResult[string](resp, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NacosConfigDetail{
+		DataID:  operation.DataId,
+		Group:   operation.Group,
+		Tenant:  operation.Namespace,
+		Content: content,
+		Md5:     util.Md5ToString(content),
+	}, nil
+}
+
+func (a v2API) publish(ctx context.Context, request publishRequest) error {
+	configUrl, err := a.url(nil)
+	if err != nil {
+		return err
+	}
+
+	metadata := request.Metadata
+	formData := url.Values{
+		"dataId":      []string{request.DataId},
+		"group":       []string{request.Group},
+		"namespaceId": []string{tenantOf(request.Namespace)},
+		"content":     []string{request.Content},
+		"type":        []string{request.Type},
+		"desc":        []string{*metadata.Desc},
+		"configTags":  []string{strings.Join(metadata.Tags, ",")},
+		"appName":     []string{*metadata.AppName},
+		"use":         []string{*metadata.Use},
+		"effect":      []string{*metadata.Effect},
+		"schema":      []string{*metadata.Schema},
+	}
+	if request.CasMd5 != "" {
+		formData.Set("casMd5", request.CasMd5)
+	}
+	if request.EncryptedDataKey != "" {
+		formData.Set("encryptedDataKey", request.EncryptedDataKey)
+	}
+
+	resp, body, err := a.c.sendForm(ctx, http.MethodPost, configUrl, betaHeader(request.BetaIps), formData)
+	if err != nil {
+		return err
+	}
+
+	_, err = decodeResult[bool](resp, body)
+	return err
+}
+
+func (a v2API) delete(ctx context.Context, operation ConfigDeleteOperation) error {
+	configUrl, err := a.url(url.Values{
+		"dataId":      []string{operation.DataId},
+		"group":       []string{operation.Group},
+		"namespaceId": []string{tenantOf(operation.Namespace)},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, body, err := a.c.send(ctx, http.MethodDelete, configUrl, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = decodeResult[bool](resp, body)
+	return err
+}
@@ -0,0 +1,228 @@
+package nacos
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const v3ConfigUrl = "/v3/admin/cs/config"
+
+// v3API Nacos 3.x 的 /v3/admin/cs 接口。
+// v3 中分组参数为 groupName，public 命名空间显式使用 "public"
+type v3API struct {
+	c *Client
+}
+
+// v3ConfigDetail v3 配置详情
+type v3ConfigDetail struct {
+	ID               string `json:"id"`
+	DataID           string `json:"dataId"`
+	GroupName        string `json:"groupName"`
+	NamespaceID      string `json:"namespaceId"`
+	Content          string `json:"content"`
+	Md5              string `json:"md5"`
+	EncryptedDataKey string `json:"encryptedDataKey"`
+	AppName          string `json:"appName"`
+	Type             string `json:"type"`
+	CreateTime       int64  `json:"createTime"`
+	ModifyTime       int64  `json:"modifyTime"`
+	CreateUser       string `json:"createUser"`
+	CreateIP         string `json:"createIp"`
+	Desc             string `json:"desc"`
+	Use              string `json:"use"`
+	Effect           string `json:"effect"`
+	Schema           string `json:"schema"`
+	ConfigTags       string `json:"configTags"`
+}
+
+// v3ConfigPage v3 配置列表
+type v3ConfigPage struct {
+	TotalCount     int `json:"totalCount"`
+	PageNumber     int `json:"pageNumber"`
+	PagesAvailable int `json:"pagesAvailable"`
+	PageItems      []struct {
+		ID          string `json:"id"`
+		DataID      string `json:"dataId"`
+		GroupName   string `json:"groupName"`
+		NamespaceID string `json:"namespaceId"`
+		Md5         string `json:"md5"`
+		Type        string `json:"type"`
+		ConfigTags  string `json:"configTags"`
+	} `json:"pageItems"`
+}
+
+// namespaceOf 将命名空间转换为 v3 和服务发现接口的 namespaceId 参数，空字符串表示 public
+func namespaceOf(namespace string) string {
+	if namespace == "" {
+		return "public"
+	}
+	return namespace
+}
+
+func (a v3API) url(path string, query url.Values) (string, error) {
+	configUrl, err := serverPath(v3ConfigUrl, path)
+	if err != nil {
+		return "", err
+	}
+	if query != nil {
+		configUrl += "?" + query.Encode()
+	}
+	return configUrl, nil
+}
+
+func (a v3API) get(ctx context.Context, operation ConfigGetOperation) (*NacosConfigDetail, error) {
+	return a.detail(ctx, operation)
+}
+
+// detail v3 的查询接口同时返回内容和元数据
+func (a v3API) detail(ctx context.Context, operation ConfigGetOperation) (*NacosConfigDetail, error) {
+	configUrl, err := a.url("", url.Values{
+		"dataId":      []string{operation.DataId},
+		"groupName":   []string{operation.Group},
+		"namespaceId": []string{namespaceOf(operation.Namespace)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, body, err := a.c.send(ctx, http.MethodGet, configUrl, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := decodeResult[*v3ConfigDetail](resp, body)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, ErrConfigNotExist
+	}
+
+	return &NacosConfigDetail{
+		ID:               data.ID,
+		DataID:           data.DataID,
+		Group:            data.GroupName,
+		Content:          data.Content,
+		Md5:              data.Md5,
+		EncryptedDataKey: data.EncryptedDataKey,
+		Tenant:           tenantOf(data.NamespaceID),
+		AppName:          data.AppName,
+		Type:             data.Type,
+		CreateTime:       data.CreateTime,
+		ModifyTime:       data.ModifyTime,
+		CreateUser:       data.CreateUser,
+		CreateIP:         data.CreateIP,
+		Desc:             data.Desc,
+		Use:              data.Use,
+		Effect:           data.Effect,
+		Schema:           data.Schema,
+		ConfigTags:       data.ConfigTags,
+	}, nil
+}
+
+func (a v3API) page(ctx context.Context, operation ConfigGetOperation, pageNo int) (*NacosPageResult, error) {
+	// 指定分组时精确匹配，未指定时模糊查询所有分组
+	search := "blur"
+	if operation.Group != "" {
+		search = "accurate"
+	}
+
+	configUrl, err := a.url("list", url.Values{
+		"dataId":      []string{""},
+		"groupName":   []string{operation.Group},
+		"namespaceId": []string{namespaceOf(operation.Namespace)},
+		"pageNo":      []string{fmt.Sprint(pageNo)},
+		"pageSize":    []string{fmt.Sprint(listPageSize)},
+		"search":      []string{search},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, body, err := a.c.send(ctx, http.MethodGet, configUrl, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := decodeResult[v3ConfigPage](resp, body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &NacosPageResult{
+		TotalCount:     data.TotalCount,
+		PageNumber:     data.PageNumber,
+		PagesAvailable: data.PagesAvailable,
+		PageItems:      make([]NacosPageItem, 0, len(data.PageItems)),
+	}
+	for _, item := range data.PageItems {
+		result.PageItems = append(result.PageItems, NacosPageItem{
+			Id:     item.ID,
+			DataId: item.DataID,
+			Group:  item.GroupName,
+			Type:   item.Type,
+			Tenant: tenantOf(item.NamespaceID),
+			Md5:    item.Md5,
+			Tags:   item.ConfigTags,
+		})
+	}
+	return result, nil
+}
+
+func (a v3API) publish(ctx context.Context, request publishRequest) error {
+	configUrl, err := a.url("", nil)
+	if err != nil {
+		return err
+	}
+
+	metadata := request.Metadata
+	formData := url.Values{
+		"dataId":      []string{request.DataId},
+		"groupName":   []string{request.Group},
+		"namespaceId": []string{namespaceOf(request.Namespace)},
+		"content":     []string{request.Content},
+		"type":        []string{request.Type},
+		"desc":        []string{*metadata.Desc},
+		"configTags":  []string{strings.Join(metadata.Tags, ",")},
+		"appName":     []string{*metadata.AppName},
+		"use":         []string{*metadata.Use},
+		"effect":      []string{*metadata.Effect},
+		"schema":      []string{*metadata.Schema},
+	}
+	if request.CasMd5 != "" {
+		formData.Set("casMd5", request.CasMd5)
+	}
+	if request.EncryptedDataKey != "" {
+		formData.Set("encryptedDataKey", request.EncryptedDataKey)
+	}
+
+	resp, body, err := a.c.sendForm(ctx, http.MethodPost, configUrl, betaHeader(request.BetaIps), formData)
+	if err != nil {
+		return err
+	}
+
+	_, err = decodeResult[bool](resp, body)
+	return err
+}
+
+func (a v3API) delete(ctx context.Context, operation ConfigDeleteOperation) error {
+	configUrl, err := a.url("", url.Values{
+		"dataId":      []string{operation.DataId},
+		"groupName":   []string{operation.Group},
+		"namespaceId": []string{namespaceOf(operation.Namespace)},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, body, err := a.c.send(ctx, http.MethodDelete, configUrl, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = decodeResult[bool](resp, body)
+	return err
+}
@@ -0,0 +1,223 @@
+package nacos
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Talbot3/nacos-cli/pkg/util"
+	"gopkg.in/yaml.v3"
+)
+
+// archiveMetadataFile 导出包中的元数据文件，与 Nacos 控制台 exportV2 格式一致
+const archiveMetadataFile = ".metadata.yml"
+
+// ConfigArchiveItem 导出包中的单个配置。
+// 导出包内配置内容存放在 <group>/<dataId>，其余字段记录在 .metadata.yml 中
+type ConfigArchiveItem struct {
+	DataId  string `yaml:"dataId"`
+	Group   string `yaml:"group"`
+	Type    string `yaml:"type"`
+	AppName string `yaml:"appName"`
+	Desc    string `yaml:"desc"`
+	Content string `yaml:"-"`
+
+	// EncryptedDataKey cipher- 配置的数据密钥，Content 为对应的密文。
+	// Nacos 控制台的导出包不包含该字段，由 nacosctl 导出时补充
+	EncryptedDataKey string `yaml:"encryptedDataKey,omitempty"`
+}
+
+type archiveMetadata struct {
+	Metadata []ConfigArchiveItem `yaml:"metadata"`
+}
+
+// ReadConfigArchive 解析 zip 格式的导出包
+func ReadConfigArchive(data []byte) ([]ConfigArchiveItem, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte)
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		files[f.Name] = content
+	}
+
+	return parseArchiveFiles(files)
+}
+
+// ReadConfigDir 读取按导出包格式展开的目录
+func ReadConfigDir(dir string) ([]ConfigArchiveItem, error) {
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = content
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseArchiveFiles(files)
+}
+
+// parseArchiveFiles 将 <group>/<dataId> 文件与元数据合并为配置列表
+func parseArchiveFiles(files map[string][]byte) ([]ConfigArchiveItem, error) {
+	metadata := archiveMetadata{}
+	if data, ok := files[archiveMetadataFile]; ok {
+		if err := yaml.Unmarshal(data, &metadata); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", archiveMetadataFile, err)
+		}
+	}
+
+	index := make(map[string]ConfigArchiveItem, len(metadata.Metadata))
+	for _, item := range metadata.Metadata {
+		index[path.Join(item.Group, item.DataId)] = item
+	}
+
+	var items []ConfigArchiveItem
+	for name, content := range files {
+		if name == archiveMetadataFile {
+			continue
+		}
+		group, dataId, ok := strings.Cut(name, "/")
+		if !ok {
+			return nil, fmt.Errorf("unexpected file in archive: %s", name)
+		}
+		if _, err := archiveName(group, dataId); err != nil {
+			return nil, err
+		}
+
+		item, ok := index[name]
+		if !ok {
+			item = ConfigArchiveItem{
+				Type: strings.TrimPrefix(path.Ext(dataId), "."),
+			}
+		}
+		item.DataId, item.Group = dataId, group
+		item.Content = string(content)
+		items = append(items, item)
+	}
+
+	sortArchiveItems(items)
+	return items, nil
+}
+
+// WriteConfigArchive 将配置写为 zip 格式的导出包
+func WriteConfigArchive(w io.Writer, items []ConfigArchiveItem) error {
+	metadata, err := marshalArchiveMetadata(items)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	for _, item := range items {
+		f, err := zw.Create(path.Join(item.Group, item.DataId))
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write([]byte(item.Content)); err != nil {
+			return err
+		}
+	}
+
+	f, err := zw.Create(archiveMetadataFile)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(metadata); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// WriteConfigDir 将配置按导出包格式写入目录
+func WriteConfigDir(dir string, items []ConfigArchiveItem) error {
+	metadata, err := marshalArchiveMetadata(items)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		groupDir := filepath.Join(dir, item.Group)
+		if err := os.MkdirAll(groupDir, 0755); err != nil {
+			return err
+		}
+		if err := util.WriteFileAtomic(filepath.Join(groupDir, item.DataId), []byte(item.Content), 0644); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return util.WriteFileAtomic(filepath.Join(dir, archiveMetadataFile), metadata, 0644)
+}
+
+// archiveName 返回配置在导出包中的路径 <group>/<dataId>。
+// group 和 dataId 必须是单个文件名，避免导出包中的 ../ 等路径写到目标目录之外
+func archiveName(group, dataId string) (string, error) {
+	for _, name := range []string{group, dataId} {
+		if name == "." || !filepath.IsLocal(name) || strings.ContainsAny(name, `/\`) {
+			return "", fmt.Errorf("invalid archive entry: %s/%s", group, dataId)
+		}
+	}
+	return path.Join(group, dataId), nil
+}
+
+// marshalArchiveMetadata 校验每个配置的 group 和 dataId 并生成 .metadata.yml
+func marshalArchiveMetadata(items []ConfigArchiveItem) ([]byte, error) {
+	for _, item := range items {
+		if item.DataId == "" || item.Group == "" {
+			return nil, errors.New("dataId and group are required for every archive item")
+		}
+		if _, err := archiveName(item.Group, item.DataId); err != nil {
+			return nil, err
+		}
+	}
+
+	sorted := make([]ConfigArchiveItem, len(items))
+	copy(sorted, items)
+	sortArchiveItems(sorted)
+
+	return yaml.Marshal(archiveMetadata{Metadata: sorted})
+}
+
+func sortArchiveItems(items []ConfigArchiveItem) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Group != items[j].Group {
+			return items[i].Group < items[j].Group
+		}
+		return items[i].DataId < items[j].DataId
+	})
+}
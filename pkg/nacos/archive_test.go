@@ -0,0 +1,54 @@
+package nacos
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigArchiveRoundTrip(t *testing.T) {
+	items := []ConfigArchiveItem{
+		{DataId: "db.properties", Group: "PROD_GROUP", Type: "properties", AppName: "orders", Desc: "数据库", Content: "db.host=x\n"},
+		{DataId: "app.yaml", Group: "DEFAULT_GROUP", Type: "yaml", Content: "a: 1\n"},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, WriteConfigArchive(buf, items))
+
+	fromZip, err := ReadConfigArchive(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, []ConfigArchiveItem{items[1], items[0]}, fromZip)
+
+	dir := t.TempDir()
+	require.NoError(t, WriteConfigDir(dir, items))
+
+	fromDir, err := ReadConfigDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, fromZip, fromDir)
+}
+
+func TestConfigArchiveRejectsUnsafePaths(t *testing.T) {
+	for _, name := range []string{"../app.yaml", "DEFAULT_GROUP/../../app.yaml", "DEFAULT_GROUP/..", "./app.yaml", `DEFAULT_GROUP/..\app.yaml`} {
+		buf := &bytes.Buffer{}
+		zw := zip.NewWriter(buf)
+		f, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte("a: 1\n"))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+
+		_, err = ReadConfigArchive(buf.Bytes())
+		assert.Error(t, err, name)
+	}
+
+	dir := filepath.Join(t.TempDir(), "export")
+	err := WriteConfigDir(dir, []ConfigArchiveItem{{DataId: "../../app.yaml", Group: "DEFAULT_GROUP", Content: "a: 1\n"}})
+	assert.Error(t, err)
+	_, err = os.Stat(filepath.Join(dir, "..", "app.yaml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
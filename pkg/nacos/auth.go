@@ -1,11 +1,13 @@
 package nacos
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/util"
 	"io"
 	"net/http"
 	"net/url"
@@ -16,8 +18,9 @@ import (
 )
 
 const (
-	authUrl = "/v1/auth/login"
-	tokenExpireBuffer = 300 // token过期前5分钟自动刷新
+	authUrl           = "/v1/auth/login"
+	authUrlV3         = "/v3/auth/user/login" // Nacos 3.x 未开启 v1 兼容时的登录接口
+	tokenExpireBuffer = 300                   // token过期前5分钟自动刷新
 )
 
 var (
@@ -56,6 +59,15 @@ func getCacheFilePath(addr, username string) (string, error) {
 	return filepath.Join(cacheDir, "token_"+hash+".json"), nil
 }
 
+// lockToken 获取 token 缓存的文件锁，保证同一用户同时只有一个进程登录刷新
+func lockToken(addr, username string) (func(), error) {
+	cacheFile, err := getCacheFilePath(addr, username)
+	if err != nil {
+		return nil, err
+	}
+	return util.LockFile(strings.TrimSuffix(cacheFile, ".json") + ".lock")
+}
+
 // loadToken 从缓存加载token
 func loadToken(addr, username string) (*TokenCache, error) {
 	cacheFile, err := getCacheFilePath(addr, username)
@@ -91,7 +103,8 @@ func saveToken(addr string, token *TokenCache) error {
 		return err
 	}
 
-	return os.WriteFile(cacheFile, data, 0600)
+	// 先写临时文件再重命名，并发读取的进程不会读到写了一半的缓存
+	return util.WriteFileAtomic(cacheFile, data, 0600)
 }
 
 // clearToken 清除指定用户的token缓存
@@ -108,8 +121,8 @@ func clearToken(addr, username string) error {
 	return nil
 }
 
-// clearAllTokens 清除指定地址的所有用户的token缓存
-func clearAllTokens(addr string) error {
+// clearAllTokens 清除所有服务器、所有用户的token缓存
+func clearAllTokens() error {
 	cacheDir, err := getCacheDir()
 	if err != nil {
 		return err
@@ -120,14 +133,11 @@ func clearAllTokens(addr string) error {
 		return err
 	}
 
-	// 清除所有与该地址相关的旧缓存文件（不包含用户名的旧格式）
-	// 以及匹配该地址的新格式缓存
 	for _, entry := range entries {
 		if strings.HasPrefix(entry.Name(), "token_") && strings.HasSuffix(entry.Name(), ".json") {
-			// 删除所有token缓存，因为我们无法确定它属于哪个用户
-			// 这样可以确保在认证失败时清除所有可能的旧缓存
-			filePath := filepath.Join(cacheDir, entry.Name())
-			_ = os.Remove(filePath)
+			if err := os.Remove(filepath.Join(cacheDir, entry.Name())); err != nil && !os.IsNotExist(err) {
+				return err
+			}
 		}
 	}
 
@@ -145,8 +155,18 @@ func isTokenValid(token *TokenCache) bool {
 	return token.ExpireTime > (now + tokenExpireBuffer)
 }
 
-// Login 登录获取accessToken
+// Login 登录获取accessToken。addr 可以是逗号分隔的多个节点，连接失败时依次尝试下一个节点
 func Login(addr, username, password string) (*AuthResponse, error) {
+	return LoginContext(context.Background(), addr, username, password)
+}
+
+// LoginContext 登录获取accessToken，ctx 取消或超时时中断请求
+func LoginContext(ctx context.Context, addr, username, password string) (*AuthResponse, error) {
+	return loginServers(ctx, http.DefaultClient, addr, username, password)
+}
+
+// loginServers 使用 httpClient 依次向节点登录，连接失败时尝试下一个节点
+func loginServers(ctx context.Context, httpClient *http.Client, addr, username, password string) (*AuthResponse, error) {
 	if addr == "" {
 		return nil, errors.New("address is required")
 	}
@@ -157,6 +177,19 @@ func Login(addr, username, password string) (*AuthResponse, error) {
 		return nil, errors.New("password is required")
 	}
 
+	var lastErr error
+	for _, server := range splitAddrs(addr) {
+		authResp, err := login(ctx, httpClient, server, username, password)
+		if err == nil || ctx.Err() != nil || !isConnectionError(err) {
+			return authResp, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// login 向单个节点登录
+func login(ctx context.Context, httpClient *http.Client, addr, username, password string) (*AuthResponse, error) {
 	// 确保地址以/nacos结尾
 	if !strings.HasSuffix(addr, "/nacos") {
 		if strings.HasSuffix(addr, "/") {
@@ -166,24 +199,16 @@ func Login(addr, username, password string) (*AuthResponse, error) {
 		}
 	}
 
-	// 构建登录URL
-	loginURL, err := url.JoinPath(addr, authUrl)
-	if err != nil {
-		return nil, err
-	}
-
 	// 构建请求体
 	formData := url.Values{}
 	formData.Set("username", username)
 	formData.Set("password", password)
 
-	resp, err := http.PostForm(loginURL, formData)
-	if err != nil {
-		return nil, fmt.Errorf("login request failed: %w", err)
+	// 先使用 v1 登录接口，服务端不存在该接口（3.x）时使用 v3 接口
+	resp, body, err := postLogin(ctx, httpClient, addr, authUrl, formData)
+	if err == nil && resp.StatusCode == http.StatusNotFound {
+		resp, body, err = postLogin(ctx, httpClient, addr, authUrlV3, formData)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
@@ -204,45 +229,171 @@ func Login(addr, username, password string) (*AuthResponse, error) {
 	return &authResp, nil
 }
 
-// GetAccessToken 获取有效的accessToken，优先从缓存获取，过期则重新登录
+// postLogin 向指定登录接口提交用户名密码
+func postLogin(ctx context.Context, httpClient *http.Client, addr, path string, formData url.Values) (*http.Response, []byte, error) {
+	loginURL, err := url.JoinPath(addr, path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, loginURL, strings.NewReader(formData.Encode()))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("login request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, body, nil
+}
+
+// GetAccessToken 获取有效的accessToken，优先从缓存获取，过期则重新登录。
+// 只有用户名没有密码时（nacosctl login --token-only）只使用缓存的 token。
+// 刷新时持有文件锁，多个进程同时发现 token 过期时只有一个进程登录，其余进程使用它刷新后的 token
 func GetAccessToken(config *NacosConfig) (string, error) {
-	if config.Username == "" || config.Password == "" {
-		// 没有配置用户名密码，返回空token（无需认证）
+	return GetAccessTokenContext(context.Background(), config)
+}
+
+// GetAccessTokenContext 获取有效的accessToken，需要登录时 ctx 取消或超时会中断登录请求
+func GetAccessTokenContext(ctx context.Context, config *NacosConfig) (string, error) {
+	return (&PasswordAuthenticator{Config: config}).AccessToken(ctx)
+}
+
+// AccessToken 获取有效的 accessToken，与 GetAccessTokenContext 相同，登录时使用 HTTPClient 发送请求
+func (a *PasswordAuthenticator) AccessToken(ctx context.Context) (string, error) {
+	config := a.Config
+	if config.Username == "" {
+		// 没有配置用户名，返回空token（无需认证）
 		return "", nil
 	}
 
 	// 尝试从缓存加载（基于地址和用户名）
-	cachedToken, err := loadToken(config.Addr, config.Username)
-	if err == nil && isTokenValid(cachedToken) {
-		// 验证缓存的token是否属于当前用户
-		if cachedToken.Username == config.Username {
-			return cachedToken.AccessToken, nil
+	if token := validCachedToken(config); token != nil {
+		return token.AccessToken, nil
+	}
+
+	if config.Password == "" {
+		return "", fmt.Errorf("%w: no valid token for %s, run nacosctl login", ErrTokenExpired, config.Username)
+	}
+
+	unlock, err := lockToken(config.Addr, config.Username)
+	if err != nil {
+		// 无法加锁（如缓存目录不可写）时退化为直接登录
+		a.warnf("failed to lock token cache: %v", err)
+	} else {
+		defer unlock()
+
+		// 等待锁期间其他进程可能已经完成刷新
+		if token := validCachedToken(config); token != nil {
+			return token.AccessToken, nil
 		}
 	}
 
 	// token过期或无效，重新登录
-	authResp, err := Login(config.Addr, config.Username, config.Password)
+	tokenCache, err := a.refreshToken(ctx)
 	if err != nil {
 		return "", err
 	}
 
+	return tokenCache.AccessToken, nil
+}
+
+// validCachedToken 返回属于当前用户且未过期的缓存 token
+func validCachedToken(config *NacosConfig) *TokenCache {
+	cachedToken, err := loadToken(config.Addr, config.Username)
+	if err != nil || !isTokenValid(cachedToken) || cachedToken.Username != config.Username {
+		return nil
+	}
+	return cachedToken
+}
+
+// RefreshAccessToken 使用用户名密码登录并缓存新的 token
+func RefreshAccessToken(config *NacosConfig) (*TokenCache, error) {
+	return RefreshAccessTokenContext(context.Background(), config)
+}
+
+// RefreshAccessTokenContext 使用用户名密码登录并缓存新的 token，ctx 取消或超时时中断登录请求
+func RefreshAccessTokenContext(ctx context.Context, config *NacosConfig) (*TokenCache, error) {
+	return (&PasswordAuthenticator{Config: config}).Refresh(ctx)
+}
+
+// Refresh 使用用户名密码登录并缓存新的 token，与 RefreshAccessTokenContext 相同，登录时使用 HTTPClient 发送请求
+func (a *PasswordAuthenticator) Refresh(ctx context.Context) (*TokenCache, error) {
+	config := a.Config
+	unlock, err := lockToken(config.Addr, config.Username)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return a.refreshToken(ctx)
+}
+
+// refreshToken 登录并保存 token，调用方负责加锁
+func (a *PasswordAuthenticator) refreshToken(ctx context.Context) (*TokenCache, error) {
+	config := a.Config
+	servers, err := resolveServers(config, a.httpClient())
+	if err != nil {
+		return nil, err
+	}
+
+	authResp, err := loginServers(ctx, a.httpClient(), strings.Join(servers.pick(), ","), config.Username, config.Password)
+	if err != nil {
+		return nil, err
+	}
+
 	// 保存到缓存（包含用户名）
 	expireTime := time.Now().Unix() + authResp.TokenTTL
 	tokenCache := &TokenCache{
 		AccessToken: authResp.AccessToken,
 		ExpireTime:  expireTime,
 		Username:    config.Username,
+		GlobalAdmin: authResp.GlobalAdmin,
 	}
 
 	if err := saveToken(config.Addr, tokenCache); err != nil {
 		// 保存失败不影响使用，只记录错误
-		fmt.Fprintf(os.Stderr, "Warning: failed to save token cache: %v\n", err)
+		a.warnf("failed to save token cache: %v", err)
+	}
+
+	return tokenCache, nil
+}
+
+// invalidateToken 服务端拒绝 token 时清除缓存。
+// 只有缓存中仍是被拒绝的 token 时才清除，避免删掉其他进程刚刷新的 token
+func invalidateToken(addr, username, token string) error {
+	unlock, err := lockToken(addr, username)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cached, err := loadToken(addr, username)
+	if err != nil || cached == nil || cached.AccessToken != token {
+		return err
 	}
+	return clearToken(addr, username)
+}
+
+// CachedToken 获取缓存的 token，没有缓存时返回 nil
+func CachedToken(addr, username string) (*TokenCache, error) {
+	return loadToken(addr, username)
+}
 
-	return authResp.AccessToken, nil
+// ClearAccessToken 清除指定服务器、指定用户缓存的accessToken
+func ClearAccessToken(addr, username string) error {
+	return clearToken(addr, username)
 }
 
-// ClearAccessToken 清除缓存的accessToken（清除该地址的所有缓存）
-func ClearAccessToken(addr string) error {
-	return clearAllTokens(addr)
+// ClearAllAccessTokens 清除所有服务器、所有用户缓存的accessToken
+func ClearAllAccessTokens() error {
+	return clearAllTokens()
 }
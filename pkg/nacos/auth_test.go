@@ -0,0 +1,166 @@
+package nacos
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClearAccessTokenScoped(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	tokens := []struct{ addr, username string }{
+		{"http://a:8848/nacos", "alice"},
+		{"http://a:8848/nacos", "bob"},
+		{"http://b:8848/nacos", "alice"},
+	}
+	for _, token := range tokens {
+		require.NoError(t, saveToken(token.addr, &TokenCache{AccessToken: token.username, ExpireTime: 9999999999, Username: token.username}))
+	}
+
+	require.NoError(t, ClearAccessToken("http://a:8848/nacos", "alice"))
+
+	cached, err := CachedToken("http://a:8848/nacos", "alice")
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+	for _, token := range tokens[1:] {
+		cached, err := CachedToken(token.addr, token.username)
+		require.NoError(t, err)
+		assert.NotNil(t, cached)
+	}
+
+	require.NoError(t, ClearAllAccessTokens())
+	for _, token := range tokens {
+		cached, err := CachedToken(token.addr, token.username)
+		require.NoError(t, err)
+		assert.Nil(t, cached)
+	}
+}
+
+// fakeAuthServer 模拟登录接口，登录较慢以放大并发窗口；validToken 之外的 token 返回 403
+func fakeAuthServer(t *testing.T, logins *int32, validToken string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/auth/login") {
+			atomic.AddInt32(logins, 1)
+			time.Sleep(100 * time.Millisecond)
+			_, _ = w.Write([]byte(`{"accessToken":"` + validToken + `","tokenTTL":18000}`))
+			return
+		}
+		if r.Header.Get(authHeader) != "Bearer "+validToken {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("token invalid!"))
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("content"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetAccessTokenSingleFlight(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	var logins int32
+	server := fakeAuthServer(t, &logins, "tok")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := GetAccessToken(&NacosConfig{Addr: server.URL + "/nacos", Username: "nacos", Password: "nacos"})
+			assert.NoError(t, err)
+			assert.Equal(t, "tok", token)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), logins)
+}
+
+func TestClientLoggerReceivesCacheWarnings(t *testing.T) {
+	// HOME 是文件时无法创建缓存目录，登录仍然成功，警告交给客户端的 Logger
+	home := filepath.Join(t.TempDir(), "home")
+	require.NoError(t, os.WriteFile(home, nil, 0600))
+	t.Setenv("HOME", home)
+	var logins int32
+	server := fakeAuthServer(t, &logins, "tok")
+
+	var warnings []string
+	client := New(server.URL+"/nacos", WithAPIVersion(ApiVersionV1), WithCredentials("nacos", "nacos"),
+		WithLogger(func(format string, args ...any) {
+			warnings = append(warnings, fmt.Sprintf(format, args...))
+		}))
+	detail, err := client.Get(ConfigGetOperation{NacosOperation: &NacosOperation{Group: "DEFAULT_GROUP"}, DataId: "app.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, "content", detail.Content)
+
+	require.Len(t, warnings, 2)
+	assert.Contains(t, warnings[0], "failed to lock token cache")
+	assert.Contains(t, warnings[1], "failed to save token cache")
+}
+
+func TestRejectedTokenRefreshedOnce(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	var logins int32
+	server := fakeAuthServer(t, &logins, "new-token")
+	addr := server.URL + "/nacos"
+
+	// 缓存中的 token 未过期但已被服务端吊销
+	require.NoError(t, saveToken(addr, &TokenCache{AccessToken: "revoked", ExpireTime: 9999999999, Username: "nacos"}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client := NewClient(addr, "v1", "nacos", "nacos")
+			detail, err := client.Get(ConfigGetOperation{NacosOperation: &NacosOperation{Namespace: "public", Group: "DEFAULT_GROUP"}, DataId: "app.yaml"})
+			if assert.NoError(t, err) {
+				assert.Equal(t, "content", detail.Content)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), logins)
+}
+
+// TestGetAccessTokenParallelProcesses 启动多个测试进程同时获取 token，只允许登录一次
+func TestGetAccessTokenParallelProcesses(t *testing.T) {
+	if addr := os.Getenv("NACOSCTL_TEST_TOKEN_ADDR"); addr != "" {
+		_, err := GetAccessToken(&NacosConfig{Addr: addr, Username: "nacos", Password: "nacos"})
+		require.NoError(t, err)
+		return
+	}
+
+	home := t.TempDir()
+	var logins int32
+	server := fakeAuthServer(t, &logins, "tok")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cmd := exec.Command(os.Args[0], "-test.run=^TestGetAccessTokenParallelProcesses$")
+			cmd.Env = append(os.Environ(), "HOME="+home, "NACOSCTL_TEST_TOKEN_ADDR="+server.URL+"/nacos")
+			out, err := cmd.CombinedOutput()
+			assert.NoError(t, err, string(out))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), logins)
+}
@@ -0,0 +1,88 @@
+package nacos
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessKeyAuthenticator(t *testing.T) {
+	authenticator := &AccessKeyAuthenticator{
+		AccessKey: "ak",
+		SecretKey: "sk",
+		now:       func() time.Time { return time.UnixMilli(1700000000000) },
+	}
+
+	sign := func(data string) string {
+		mac := hmac.New(sha1.New, []byte("sk"))
+		mac.Write([]byte(data))
+		return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+
+	form := url.Values{"dataId": {"app.yaml"}, "group": {"DEFAULT_GROUP"}, "tenant": {"dev"}}
+
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			_ = r.ParseForm()
+			assert.Equal(t, "app.yaml", r.PostForm.Get("dataId"), "signing must not consume the body")
+		}
+		headers = append(headers, r.Header.Clone())
+	}))
+	defer server.Close()
+
+	client := &Client{Config: &NacosConfig{Addr: server.URL}, Authenticator: authenticator}
+	_, _, err := client.sendForm(context.Background(), http.MethodPost, "/", nil, form)
+	require.NoError(t, err)
+	_, _, err = client.send(context.Background(), http.MethodGet, "/?group=DEFAULT_GROUP", nil, nil)
+	require.NoError(t, err)
+	_, _, err = client.send(context.Background(), http.MethodGet, "/", nil, nil)
+	require.NoError(t, err)
+
+	require.Len(t, headers, 3)
+	for _, header := range headers {
+		assert.Equal(t, "ak", header.Get(accessKeyHeader))
+		assert.Equal(t, "1700000000000", header.Get(timestampHeader))
+	}
+	assert.Equal(t, sign("dev+DEFAULT_GROUP+1700000000000"), headers[0].Get(signatureHeader))
+	assert.Equal(t, sign("DEFAULT_GROUP+1700000000000"), headers[1].Get(signatureHeader))
+	assert.Equal(t, sign("1700000000000"), headers[2].Get(signatureHeader))
+}
+
+func TestSignResource(t *testing.T) {
+	tests := []struct {
+		tenant, group string
+		want          string
+	}{
+		{tenant: "dev", group: "DEFAULT_GROUP", want: "dev+DEFAULT_GROUP"},
+		{tenant: "dev", group: "", want: "dev+"},
+		{tenant: "", group: "DEFAULT_GROUP", want: "DEFAULT_GROUP"},
+		{tenant: "", group: "", want: ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, signResource(tt.tenant, tt.group), "tenant=%q group=%q", tt.tenant, tt.group)
+	}
+}
+
+func TestNewAuthenticator(t *testing.T) {
+	assert.IsType(t, &TokenAuthenticator{}, newAuthenticator(&NacosConfig{Token: "t", AccessKey: "ak", SecretKey: "sk"}))
+	assert.IsType(t, &AccessKeyAuthenticator{}, newAuthenticator(&NacosConfig{AccessKey: "ak", SecretKey: "sk", Username: "nacos"}))
+	assert.IsType(t, &IdentityAuthenticator{}, newAuthenticator(&NacosConfig{IdentityKey: "serverIdentity", IdentityValue: "security"}))
+	assert.IsType(t, &PasswordAuthenticator{}, newAuthenticator(&NacosConfig{Username: "nacos", Password: "nacos"}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, newAuthenticator(&NacosConfig{IdentityKey: "serverIdentity", IdentityValue: "security"}).Authenticate(req))
+	assert.Equal(t, "security", req.Header.Get("serverIdentity"))
+
+	require.NoError(t, newAuthenticator(&NacosConfig{Token: "t"}).Authenticate(req))
+	assert.Equal(t, "Bearer t", req.Header.Get(authHeader))
+}
@@ -0,0 +1,132 @@
+package nacos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// betaIpsHeader 灰度发布时携带目标客户端 IP 的请求头
+const betaIpsHeader = "betaIps"
+
+// ErrBetaNotExist 配置没有进行中的灰度发布
+var ErrBetaNotExist = errors.New("beta config not exists")
+
+// GetBeta 查询配置的灰度发布内容和灰度 IP
+func (c *Client) GetBeta(operation ConfigGetOperation) (*NacosConfigBeta, error) {
+	return c.GetBetaContext(context.Background(), operation)
+}
+
+// GetBetaContext 查询配置的灰度发布内容和灰度 IP，ctx 取消或超时时中断请求
+func (c *Client) GetBetaContext(ctx context.Context, operation ConfigGetOperation) (*NacosConfigBeta, error) {
+	beta, err := c.getBeta(ctx, operation)
+	if err != nil {
+		return nil, err
+	}
+
+	detail, err := c.decrypt(&beta.NacosConfigDetail)
+	if err != nil {
+		return nil, err
+	}
+	beta.NacosConfigDetail = *detail
+	return beta, nil
+}
+
+// getBeta 查询灰度发布内容，加密的内容不解密
+func (c *Client) getBeta(ctx context.Context, operation ConfigGetOperation) (*NacosConfigBeta, error) {
+	resp, body, err := c.sendBeta(ctx, http.MethodGet, operation.NacosOperation, operation.DataId)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("response error,status code:%d\n%s", resp.StatusCode, body)
+	}
+
+	result := restResult[*NacosConfigBeta]{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	if result.Code != http.StatusOK {
+		return nil, fmt.Errorf("query beta failed: %s", result.Message)
+	}
+	if result.Data == nil {
+		return nil, ErrBetaNotExist
+	}
+
+	return result.Data, nil
+}
+
+// StopBeta 停止灰度发布，灰度客户端恢复使用正式配置
+func (c *Client) StopBeta(operation ConfigDeleteOperation) error {
+	return c.StopBetaContext(context.Background(), operation)
+}
+
+// StopBetaContext 停止灰度发布，ctx 取消或超时时中断请求
+func (c *Client) StopBetaContext(ctx context.Context, operation ConfigDeleteOperation) error {
+	resp, body, err := c.sendBeta(ctx, http.MethodDelete, operation.NacosOperation, operation.DataId)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("response error,status code:%d\n%s", resp.StatusCode, body)
+	}
+
+	result := restResult[bool]{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return err
+	}
+	if result.Code != http.StatusOK || !result.Data {
+		return fmt.Errorf("stop beta failed: %s", result.Message)
+	}
+
+	return nil
+}
+
+// PromoteBeta 将灰度内容正式发布给所有客户端，并停止灰度
+func (c *Client) PromoteBeta(operation ConfigGetOperation) error {
+	return c.PromoteBetaContext(context.Background(), operation)
+}
+
+// PromoteBetaContext 正式发布灰度内容并停止灰度，ctx 取消或超时时中断请求
+func (c *Client) PromoteBetaContext(ctx context.Context, operation ConfigGetOperation) error {
+	beta, err := c.getBeta(ctx, operation)
+	if err != nil {
+		return err
+	}
+
+	if err := c.EditContext(ctx, ConfigEditOperation{
+		NacosOperation: operation.NacosOperation,
+		DataId:         operation.DataId,
+		Content:        beta.Content,
+		Type:           beta.Type,
+
+		// 灰度内容未解密，密文和数据密钥原样正式发布
+		EncryptedDataKey: beta.EncryptedDataKey,
+	}); err != nil {
+		return err
+	}
+
+	return c.StopBetaContext(ctx, ConfigDeleteOperation{
+		NacosOperation: operation.NacosOperation,
+		DataId:         operation.DataId,
+	})
+}
+
+// sendBeta 发送 beta=true 的灰度查询或停止请求
+func (c *Client) sendBeta(ctx context.Context, method string, operation *NacosOperation, dataId string) (*http.Response, []byte, error) {
+	configUrl, err := c.v1Url(ctx, "beta")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := url.Values{}
+	query.Set("beta", "true")
+	query.Set("dataId", dataId)
+	query.Set("group", operation.Group)
+	query.Set("tenant", tenantOf(operation.Namespace))
+
+	return c.send(ctx, method, configUrl+"?"+query.Encode(), nil, nil)
+}
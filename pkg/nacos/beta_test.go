@@ -0,0 +1,107 @@
+package nacos
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Talbot3/nacos-cli/pkg/encrypt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBetaPublishAndPromote(t *testing.T) {
+	var published []string
+	betaContent := ""
+	betaStopped := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			_ = r.ParseForm()
+			if ips := r.Header.Get(betaIpsHeader); ips != "" {
+				betaContent = r.PostForm.Get("content")
+				published = append(published, "beta:"+ips)
+			} else {
+				published = append(published, "formal:"+r.PostForm.Get("content"))
+			}
+			_, _ = w.Write([]byte("true"))
+		case r.Method == http.MethodGet && r.URL.Query().Get("beta") == "true":
+			_, _ = w.Write([]byte(`{"code":200,"message":"query beta ok","data":{"dataId":"app.yaml","content":"` + betaContent + `","betaIps":"10.0.0.1"}}`))
+		case r.Method == http.MethodDelete && r.URL.Query().Get("beta") == "true":
+			betaStopped = true
+			_, _ = w.Write([]byte(`{"code":200,"message":"stop beta ok","data":true}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "v1", "", "")
+	operation := &NacosOperation{Namespace: "public", Group: "DEFAULT_GROUP"}
+
+	require.NoError(t, client.Edit(ConfigEditOperation{
+		NacosOperation: operation,
+		DataId:         "app.yaml",
+		Content:        "v2",
+		BetaIps:        []string{"10.0.0.1", "10.0.0.2"},
+	}))
+
+	beta, err := client.GetBeta(ConfigGetOperation{NacosOperation: operation, DataId: "app.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, "v2", beta.Content)
+	assert.Equal(t, "10.0.0.1", beta.BetaIps)
+
+	require.NoError(t, client.PromoteBeta(ConfigGetOperation{NacosOperation: operation, DataId: "app.yaml"}))
+	assert.Equal(t, []string{"beta:10.0.0.1,10.0.0.2", "formal:v2"}, published)
+	assert.True(t, betaStopped)
+}
+
+func TestPromoteCipherBeta(t *testing.T) {
+	var beta, formal NacosConfigDetail
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			_ = r.ParseForm()
+			target := &formal
+			if r.Header.Get(betaIpsHeader) != "" {
+				target = &beta
+			}
+			target.DataID = r.PostForm.Get("dataId")
+			target.Content = r.PostForm.Get("content")
+			target.EncryptedDataKey = r.PostForm.Get("encryptedDataKey")
+			_, _ = w.Write([]byte("true"))
+		case r.Method == http.MethodGet && r.URL.Query().Get("beta") == "true":
+			data, _ := json.Marshal(beta)
+			_, _ = w.Write([]byte(`{"code":200,"message":"query beta ok","data":` + string(data) + `}`))
+		case r.Method == http.MethodDelete && r.URL.Query().Get("beta") == "true":
+			_, _ = w.Write([]byte(`{"code":200,"message":"stop beta ok","data":true}`))
+		}
+	}))
+	defer server.Close()
+
+	key, err := encrypt.NewLocalKeyProvider([]byte("0123456789abcdef"))
+	require.NoError(t, err)
+
+	client := NewClient(server.URL, "v1", "", "")
+	client.KeyProvider = key
+	operation := &NacosOperation{Namespace: "public", Group: "DEFAULT_GROUP"}
+
+	require.NoError(t, client.Edit(ConfigEditOperation{
+		NacosOperation: operation,
+		DataId:         "cipher-db.yaml",
+		Content:        "password: s3cr3t",
+		BetaIps:        []string{"10.0.0.1"},
+	}))
+	require.NotEmpty(t, beta.EncryptedDataKey)
+
+	require.NoError(t, client.PromoteBeta(ConfigGetOperation{NacosOperation: operation, DataId: "cipher-db.yaml"}))
+
+	// 正式配置是灰度的密文和数据密钥，没有被再次加密
+	assert.Equal(t, beta.Content, formal.Content)
+	assert.Equal(t, beta.EncryptedDataKey, formal.EncryptedDataKey)
+	plaintext, err := encrypt.DecryptContent(key, formal.Content, formal.EncryptedDataKey)
+	require.NoError(t, err)
+	assert.Equal(t, "password: s3cr3t", plaintext)
+}
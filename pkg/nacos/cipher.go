@@ -0,0 +1,65 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/encrypt"
+	"github.com/Talbot3/nacos-cli/pkg/util"
+)
+
+// encryptedDataKeyHeader v1 配置查询接口返回数据密钥的响应头
+const encryptedDataKeyHeader = "encryptedDataKey"
+
+// ErrNoEncryptionKey 发布 cipher- 配置时没有加密密钥，也没有声明由服务端加密
+var ErrNoEncryptionKey = errors.New("cipher- config requires an encryption key")
+
+// encryptContent 发布 cipher- 前缀的配置前在客户端加密内容，返回密文和加密后的数据密钥。
+// 已有数据密钥的内容已经加密，不会重复加密；未配置密钥时返回 ErrNoEncryptionKey，
+// 除非 ServerSideEncryption 声明由服务端加密插件处理，此时原样发布
+func (c *Client) encryptContent(dataId, content, encryptedDataKey string) (string, string, error) {
+	if encryptedDataKey != "" || !encrypt.IsCipherDataId(dataId) {
+		return content, encryptedDataKey, nil
+	}
+	if c.KeyProvider == nil {
+		if c.ServerSideEncryption {
+			return content, "", nil
+		}
+		return "", "", fmt.Errorf("%w: %s", ErrNoEncryptionKey, dataId)
+	}
+
+	encrypted, dataKey, err := encrypt.EncryptContent(c.KeyProvider, content)
+	if err != nil {
+		return "", "", fmt.Errorf("encrypt %s: %w", dataId, err)
+	}
+	return encrypted, dataKey, nil
+}
+
+// decrypt 返回解密后的配置副本，Md5 按明文重新计算，EncryptedDataKey 清空。
+// 内容未加密（没有数据密钥）或未配置密钥时原样返回
+func (c *Client) decrypt(detail *NacosConfigDetail) (*NacosConfigDetail, error) {
+	if detail == nil || c.KeyProvider == nil || detail.EncryptedDataKey == "" {
+		return detail, nil
+	}
+
+	plaintext, err := encrypt.DecryptContent(c.KeyProvider, detail.Content, detail.EncryptedDataKey)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt %s: %w", detail.DataID, err)
+	}
+
+	decrypted := *detail
+	decrypted.Content = plaintext
+	decrypted.Md5 = util.Md5ToString(plaintext)
+	decrypted.EncryptedDataKey = ""
+	return &decrypted, nil
+}
+
+// get 查询配置内容，加密的内容不解密。
+// 查询接口不返回数据密钥（如 v2）时，cipher- 配置改为查询详情以获取 encryptedDataKey
+func (c *Client) get(ctx context.Context, operation ConfigGetOperation) (*NacosConfigDetail, error) {
+	detail, err := c.api(ctx).get(ctx, operation)
+	if err != nil || detail.EncryptedDataKey != "" || c.KeyProvider == nil || !encrypt.IsCipherDataId(operation.DataId) {
+		return detail, err
+	}
+	return c.detail(ctx, operation)
+}
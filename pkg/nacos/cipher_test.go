@@ -0,0 +1,113 @@
+package nacos
+
+import (
+	"github.com/Talbot3/nacos-cli/pkg/encrypt"
+	"github.com/Talbot3/nacos-cli/pkg/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCipherConfigRoundTrip(t *testing.T) {
+	stored, dataKeys := map[string]string{}, map[string]string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			_ = r.ParseForm()
+			stored[r.PostForm.Get("dataId")] = r.PostForm.Get("content")
+			dataKeys[r.PostForm.Get("dataId")] = r.PostForm.Get("encryptedDataKey")
+			_, _ = w.Write([]byte("true"))
+		case http.MethodGet:
+			dataId := r.URL.Query().Get("dataId")
+			content, ok := stored[dataId]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain;charset=UTF-8")
+			w.Header().Set(encryptedDataKeyHeader, dataKeys[dataId])
+			_, _ = w.Write([]byte(content))
+		}
+	}))
+	defer server.Close()
+
+	key, err := encrypt.NewLocalKeyProvider([]byte("0123456789abcdef"))
+	require.NoError(t, err)
+
+	client := NewClient(server.URL, "v1", "", "")
+	client.KeyProvider = key
+	operation := &NacosOperation{Namespace: "public", Group: "DEFAULT_GROUP"}
+
+	for _, dataId := range []string{"cipher-aes-db.yaml", "app.yaml"} {
+		require.NoError(t, client.Edit(ConfigEditOperation{
+			NacosOperation: operation,
+			DataId:         dataId,
+			Content:        "password: s3cr3t",
+		}))
+	}
+
+	// 内容只保存密文，数据密钥保存在 encryptedDataKey 中
+	assert.False(t, encrypt.IsEncrypted(stored["cipher-aes-db.yaml"]))
+	assert.NotContains(t, stored["cipher-aes-db.yaml"], "s3cr3t")
+	assert.NotEmpty(t, dataKeys["cipher-aes-db.yaml"])
+	assert.Equal(t, "password: s3cr3t", stored["app.yaml"])
+	assert.Empty(t, dataKeys["app.yaml"])
+
+	detail, err := client.Get(ConfigGetOperation{NacosOperation: operation, DataId: "cipher-aes-db.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, "password: s3cr3t", detail.Content)
+	assert.Equal(t, util.Md5ToString("password: s3cr3t"), detail.Md5)
+
+	// 未配置密钥时返回密文
+	client.KeyProvider = nil
+	detail, err = client.Get(ConfigGetOperation{NacosOperation: operation, DataId: "cipher-aes-db.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, stored["cipher-aes-db.yaml"], detail.Content)
+	assert.Equal(t, dataKeys["cipher-aes-db.yaml"], detail.EncryptedDataKey)
+
+	// 未解密的密文连同数据密钥原样发布，不重复加密
+	require.NoError(t, client.Edit(ConfigEditOperation{
+		NacosOperation:   operation,
+		DataId:           "cipher-aes-copy.yaml",
+		Content:          detail.Content,
+		EncryptedDataKey: detail.EncryptedDataKey,
+	}))
+	client.KeyProvider = key
+	detail, err = client.Get(ConfigGetOperation{NacosOperation: operation, DataId: "cipher-aes-copy.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, "password: s3cr3t", detail.Content)
+}
+
+func TestCipherConfigWithoutKey(t *testing.T) {
+	stored := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = r.ParseForm()
+		stored[r.PostForm.Get("dataId")] = r.PostForm.Get("content")
+		_, _ = w.Write([]byte("true"))
+	}))
+	defer server.Close()
+
+	operation := ConfigEditOperation{
+		NacosOperation: &NacosOperation{Namespace: "public", Group: "DEFAULT_GROUP"},
+		DataId:         "cipher-db.yaml",
+		Content:        "password: s3cr3t",
+	}
+
+	// 未配置密钥时拒绝发布，不以明文泄露
+	client := New(server.URL, WithAPIVersion(ApiVersionV1))
+	assert.ErrorIs(t, client.Edit(operation), ErrNoEncryptionKey)
+	assert.Empty(t, stored)
+
+	// 声明由服务端加密时原样发布
+	client = New(server.URL, WithAPIVersion(ApiVersionV1), WithServerSideEncryption())
+	require.NoError(t, client.Edit(operation))
+	assert.Equal(t, "password: s3cr3t", stored["cipher-db.yaml"])
+}
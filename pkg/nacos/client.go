@@ -1,430 +1,391 @@
 package nacos
 
 import (
-	"encoding/json"
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/encrypt"
+	"github.com/Talbot3/nacos-cli/pkg/util"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
-	"strings"
+	"sync"
+	"time"
 )
 
 const (
-	baseUrl = "/cs/configs"
-	authHeader = "Authorization"
+	baseUrl      = "/cs/configs"
+	authHeader   = "Authorization"
+	listPageSize = 500 // 列表接口每页条数
 )
 
+// ErrConfigNotExist 配置不存在
+var ErrConfigNotExist = errors.New("config not exists")
+
+// ErrConflict 带 casMd5 发布时服务器上的配置已被他人修改
+var ErrConflict = errors.New("config was modified by someone else")
+
 // Client Nacos客户端
 type Client struct {
-	Config *NacosConfig
+	Config               *NacosConfig
+	KeyProvider          encrypt.KeyProvider              // cipher- 配置的加密密钥，为 nil 时不在客户端加解密
+	ServerSideEncryption bool                             // 未配置 KeyProvider 时 cipher- 配置原样发布，由服务端加密插件加密
+	Authenticator        Authenticator                    // 认证方式，为 nil 时按 Config 选择
+	RetryPolicy          *RetryPolicy                     // 失败重试策略，为 nil 时不重试
+	RequestTimeout       time.Duration                    // 单次请求（包括重试）的超时时间，0 不限制，长轮询在服务端挂起时间之外另计
+	HTTPClient           *http.Client                     // 发送 HTTP 请求（包括登录和地址服务器查询），为 nil 时使用 http.DefaultClient
+	Logger               func(format string, args ...any) // 接收不影响请求结果的警告（如 token 缓存无法加锁或写入），为 nil 时忽略
+
+	apiMu     sync.Mutex
+	configAPI configAPI // 按服务端版本选择的接口实现，首次请求时确定
+
+	serversMu  sync.Mutex
+	serverList *serverList // 节点列表及健康状态，首次请求时解析
 }
 
-// doRequest 执行带有认证的HTTP请求
-func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
-	// 获取accessToken
-	token, err := GetAccessToken(c.Config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get access token: %w", err)
+// authenticator 获取认证方式，未指定时按 Config 中的认证信息选择
+func (c *Client) authenticator() Authenticator {
+	if c.Authenticator != nil {
+		return c.Authenticator
 	}
-
-	// 添加认证头
-	if token != "" {
-		req.Header.Set(authHeader, "Bearer "+token)
+	authenticator := newAuthenticator(c.Config)
+	if password, ok := authenticator.(*PasswordAuthenticator); ok {
+		password.HTTPClient = c.HTTPClient
+		password.Logger = c.Logger
 	}
-
-	client := &http.Client{}
-	return client.Do(req)
+	return authenticator
 }
 
-// createRequest 创建HTTP请求
-func (c *Client) createRequest(method, urlStr string, body io.Reader) (*http.Request, error) {
-	req, err := http.NewRequest(method, urlStr, body)
-	if err != nil {
-		return nil, err
+// httpClient 获取发送 HTTP 请求的客户端
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
 	}
+	return http.DefaultClient
+}
 
-	// 获取accessToken
-	token, err := GetAccessToken(c.Config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get access token: %w", err)
+func (c *Client) warnf(format string, args ...any) {
+	if c.Logger != nil {
+		c.Logger(format, args...)
 	}
+}
 
-	// 添加认证头
-	if token != "" {
-		req.Header.Set(authHeader, "Bearer "+token)
+// doRequest 执行带有认证的HTTP请求
+func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
+	before := req.Header.Clone()
+	if err := c.authenticator().Authenticate(req); err != nil {
+		return nil, fmt.Errorf("authentication failed: %w", err)
 	}
+	req = withAuthHeaders(req, before)
 
-	return req, nil
+	return c.httpClient().Do(req)
 }
 
-// Get获取配置
-func (c *Client) Get(operation ConfigGetOperation) (*NacosConfigDetail, error) {
+// send 发送请求并读取完整响应体，节点选择和失败重试由 sendWithRetry 处理，RequestTimeout 限制包括重试在内的总时长。
+// 认证失败（401/403）时由 Authenticator 刷新凭据（如使被拒绝的 token 失效并重新登录）后重试一次，其余状态码交由调用方处理。
+// 空响应体的 403 是配置不存在，不视为认证失败
+func (c *Client) send(ctx context.Context, method, urlStr string, header http.Header, body []byte) (*http.Response, []byte, error) {
+	if c.RequestTimeout > 0 {
+		timeout := c.RequestTimeout
+		if header.Get(longPollingHeader) != "" {
+			timeout += longPollingTimeout
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 
-	configUrl, err := getUrl(c.Config)
+	for attempt := 0; ; attempt++ {
+		resp, data, req, err := c.sendWithRetry(ctx, method, urlStr, header, body)
+		if err != nil {
+			return nil, nil, err
+		}
 
-	if err != nil {
-		return nil, err
-	}
+		authFailed := resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden && len(data) > 0
+		if attempt == 0 && authFailed && c.authenticator().Invalidate(req) {
+			continue
+		}
 
-	// Nacos API 中 public 命名空间用空字符串表示
-	tenant := operation.Namespace
-	if tenant == "public" {
-		tenant = ""
+		return resp, data, nil
 	}
+}
 
-	requestUrl := fmt.Sprintf(configUrl+"?dataId=%s&group=%s&tenant=%s", operation.DataId, operation.Group, tenant)
+// sendToServers 依次向节点发送请求，直到请求送达或遇到非连接错误。
+// 配置了多个节点时轮询选择起始节点，近期连接失败的节点排在最后；连接失败时换下一个节点重试，
+// 非幂等请求只在建立连接失败（服务端未收到请求）时重试
+func (c *Client) sendToServers(ctx context.Context, servers *serverList, method, urlStr string, header http.Header, body []byte) (*http.Response, []byte, *http.Request, error) {
+	var lastErr error
+	for _, addr := range servers.pick() {
+		resp, data, req, err := c.sendOnce(ctx, method, nodeURL(addr, urlStr), header, body)
+		if err == nil {
+			servers.markUp(addr)
+			return resp, data, req, nil
+		}
+		if ctx.Err() != nil || !isConnectionError(err) {
+			return nil, nil, nil, err
+		}
 
-	req, err := c.createRequest(http.MethodGet, requestUrl, nil)
-	if err != nil {
-		return nil, err
+		servers.markDown(addr)
+		lastErr = err
+		if !isDialError(err) && !idempotent(method) {
+			break
+		}
 	}
+	return nil, nil, nil, lastErr
+}
 
-	resp, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
+// sendOnce 向单个节点发送一次请求
+func (c *Client) sendOnce(ctx context.Context, method, urlStr string, header http.Header, body []byte) (*http.Response, []byte, *http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
 
+	req, err := http.NewRequestWithContext(ctx, method, urlStr, reader)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
-
-	if resp.StatusCode != http.StatusOK {
-		// 检查是否是认证错误
-		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
-			// 清除缓存并重试一次
-			_ = ClearAccessToken(c.Config.Addr)
-			token, err := GetAccessToken(c.Config)
-			if err != nil {
-				return nil, fmt.Errorf("authentication failed: %w", err)
-			}
-
-			req, err = http.NewRequest(http.MethodGet, requestUrl, nil)
-			if err != nil {
-				return nil, err
-			}
-			if token != "" {
-				req.Header.Set(authHeader, "Bearer "+token)
-			}
-
-			resp, err = http.DefaultClient.Do(req)
-			if err != nil {
-				return nil, err
-			}
-			defer resp.Body.Close()
-
-			body, err = io.ReadAll(resp.Body)
-			if err != nil {
-				return nil, err
-			}
-
-			if resp.StatusCode != http.StatusOK {
-				// 配置不存在时返回 403
-				if resp.StatusCode == http.StatusForbidden && len(body) == 0 {
-					return nil, errors.New("config not exists")
-				}
-				return nil, fmt.Errorf("response error,status code:%d\n%s", resp.StatusCode, body)
-			}
-		} else {
-			return nil, fmt.Errorf("response error,status code:%d\n%s", resp.StatusCode, body)
-		}
+	for k, v := range header {
+		req.Header[k] = v
 	}
 
-	// 检查是否为空响应（配置不存在）
-	if len(body) == 0 {
-		return nil, errors.New("config not exists")
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
-	// 检查 Content-Type，如果是 text/plain，直接返回内容
-	contentType := resp.Header.Get("Content-Type")
-	if strings.Contains(contentType, "text/plain") {
-		return &NacosConfigDetail{
-			DataID: operation.DataId,
-			Group:  operation.Group,
-			Tenant: operation.Namespace,
-			Content: string(body),
-			Md5: resp.Header.Get("Content-MD5"),
-		}, nil
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, nil, nil, err
 	}
+	return resp, data, req, nil
+}
 
-	detail := NacosConfigDetail{}
-
-	if err = json.Unmarshal(body, &detail); err != nil {
-		return nil, err
+// sendForm 以 application/x-www-form-urlencoded 格式发送表单
+func (c *Client) sendForm(ctx context.Context, method, urlStr string, header http.Header, form url.Values) (*http.Response, []byte, error) {
+	if header == nil {
+		header = http.Header{}
 	}
-
-	return &detail, nil
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return c.send(ctx, method, urlStr, header, []byte(form.Encode()))
 }
 
-// AllConfig 获取所有配置
-func (c *Client) AllConfig(operation ConfigGetOperation) ([]NacosPageItem, error) {
-
-	configUrl, err := getUrl(c.Config)
+// Get获取配置
+func (c *Client) Get(operation ConfigGetOperation) (*NacosConfigDetail, error) {
+	return c.GetContext(context.Background(), operation)
+}
 
+// GetContext 获取配置，ctx 取消或超时时中断请求
+func (c *Client) GetContext(ctx context.Context, operation ConfigGetOperation) (*NacosConfigDetail, error) {
+	detail, err := c.get(ctx, operation)
 	if err != nil {
 		return nil, err
 	}
 
-	// Nacos API 中 public 命名空间用空字符串表示
-	tenant := operation.Namespace
-	if tenant == "public" {
-		tenant = ""
-	}
+	return c.decrypt(detail)
+}
 
-	requestUrl := fmt.Sprintf(configUrl+"?dataId=&group=%s&tenant=%s&pageNo=1&pageSize=999&search=accurate", operation.Group, tenant)
+// AllConfig 获取所有配置。
+// 先查询第一页得到总页数，其余页并发获取
+func (c *Client) AllConfig(operation ConfigGetOperation) ([]NacosPageItem, error) {
+	return c.AllConfigContext(context.Background(), operation)
+}
 
-	req, err := c.createRequest(http.MethodGet, requestUrl, nil)
+// AllConfigContext 获取所有配置，ctx 取消或超时时中断请求
+func (c *Client) AllConfigContext(ctx context.Context, operation ConfigGetOperation) ([]NacosPageItem, error) {
+	first, err := c.configPage(ctx, operation, 1)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
+	pages := make([]*NacosPageResult, first.PagesAvailable)
+	if len(pages) == 0 {
+		return first.PageItems, nil
 	}
+	pages[0] = first
 
-	if resp.StatusCode != http.StatusOK {
-		// 检查是否是认证错误，尝试重新认证
-		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
-			_ = ClearAccessToken(c.Config.Addr)
-			token, err := GetAccessToken(c.Config)
-			if err != nil {
-				return nil, fmt.Errorf("authentication failed: %w", err)
-			}
-
-			req, err = http.NewRequest(http.MethodGet, requestUrl, nil)
-			if err != nil {
-				return nil, err
-			}
-			if token != "" {
-				req.Header.Set(authHeader, "Bearer "+token)
-			}
-
-			resp, err = http.DefaultClient.Do(req)
-			if err != nil {
-				return nil, err
-			}
-			defer resp.Body.Close()
-
-			if resp.StatusCode != http.StatusOK {
-				return nil, fmt.Errorf("response error,status code:%d", resp.StatusCode)
-			}
-		} else {
-			return nil, fmt.Errorf("response error,status code:%d", resp.StatusCode)
+	err = parallel(len(pages)-1, func(i int) error {
+		page, err := c.configPage(ctx, operation, i+2)
+		if err != nil {
+			return err
 		}
-	}
-
-	body, err := io.ReadAll(resp.Body)
-
+		pages[i+1] = page
+		return nil
+	})
 	if err != nil {
-		body = []byte{}
+		return nil, err
 	}
 
-	result := NacosPageResult{}
-
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, err
+	items := make([]NacosPageItem, 0, first.TotalCount)
+	for _, page := range pages {
+		items = append(items, page.PageItems...)
 	}
+	return items, nil
+}
 
-	return result.PageItems, nil
+// configPage 获取配置列表的一页
+func (c *Client) configPage(ctx context.Context, operation ConfigGetOperation, pageNo int) (*NacosPageResult, error) {
+	return c.api(ctx).page(ctx, operation, pageNo)
 }
 
-// Edit 更新配置
+// Edit 更新配置。
+// 未指定的元数据（Type 为空、ConfigMetadata 中为 nil 的字段）保留服务器上的值，
+// 避免发布内容时清空控制台中设置的描述、标签等信息
 func (c *Client) Edit(operation ConfigEditOperation) error {
+	return c.EditContext(context.Background(), operation)
+}
 
-	configUrl, err := getUrl(c.Config)
-
+// EditContext 更新配置，ctx 取消或超时时中断请求
+func (c *Client) EditContext(ctx context.Context, operation ConfigEditOperation) error {
+	content, encryptedDataKey, err := c.encryptContent(operation.DataId, operation.Content, operation.EncryptedDataKey)
 	if err != nil {
 		return err
 	}
 
-	// 获取token
-	token, err := GetAccessToken(c.Config)
-	if err != nil {
-		return fmt.Errorf("failed to get access token: %w", err)
+	current, err := c.detail(ctx, ConfigGetOperation{
+		NacosOperation: operation.NacosOperation,
+		DataId:         operation.DataId,
+	})
+	if err != nil && !errors.Is(err, ErrConfigNotExist) {
+		return err
 	}
 
-	// Nacos API 中 public 命名空间用空字符串表示
-	tenant := operation.Namespace
-	if tenant == "public" {
-		tenant = ""
+	configType := operation.Type
+	if configType == "" && current != nil {
+		configType = current.Type
 	}
 
-	formData := url.Values{
-		"dataId":  []string{operation.DataId},
-		"group":   []string{operation.Group},
-		"content": []string{operation.Content},
-		"tenant":  []string{tenant},
-		"type":    []string{operation.Type},
+	publishCtx, retried := withRetryRecord(ctx)
+	err = c.api(ctx).publish(publishCtx, publishRequest{
+		NacosOperation: operation.NacosOperation,
+		DataId:         operation.DataId,
+		Content:        content,
+		Type:           configType,
+		Metadata:       operation.ConfigMetadata.merge(current),
+		BetaIps:        operation.BetaIps,
+		CasMd5:         operation.CasMd5,
+
+		EncryptedDataKey: encryptedDataKey,
+	})
+	// 重试前的请求可能已经生效（如服务端保存后返回 5xx 或响应超时），重试因此冲突
+	if errors.Is(err, ErrConflict) && retried.Load() && len(operation.BetaIps) == 0 && c.published(ctx, operation, content) {
+		return nil
 	}
+	return err
+}
 
-	req, err := http.NewRequest(http.MethodPost, configUrl, strings.NewReader(formData.Encode()))
+// published 判断服务器上的配置内容是否已是 content，用于确认重试前的发布是否已生效
+func (c *Client) published(ctx context.Context, operation ConfigEditOperation, content string) bool {
+	current, err := c.detail(ctx, ConfigGetOperation{
+		NacosOperation: operation.NacosOperation,
+		DataId:         operation.DataId,
+	})
 	if err != nil {
-		return err
+		return false
 	}
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	if token != "" {
-		req.Header.Set(authHeader, "Bearer "+token)
+	md5 := current.Md5
+	if md5 == "" {
+		md5 = util.Md5ToString(current.Content)
 	}
+	return md5 == util.Md5ToString(content)
+}
 
-	resp, err := http.DefaultClient.Do(req)
+// Detail 获取配置内容及全部元数据（描述、标签、应用名等）
+func (c *Client) Detail(operation ConfigGetOperation) (*NacosConfigDetail, error) {
+	return c.DetailContext(context.Background(), operation)
+}
+
+// DetailContext 获取配置内容及全部元数据，ctx 取消或超时时中断请求
+func (c *Client) DetailContext(ctx context.Context, operation ConfigGetOperation) (*NacosConfigDetail, error) {
+	detail, err := c.detail(ctx, operation)
 	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		// 检查是否是认证错误
-		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
-			_ = ClearAccessToken(c.Config.Addr)
-			token, err := GetAccessToken(c.Config)
-			if err != nil {
-				return fmt.Errorf("authentication failed: %w", err)
-			}
-
-			req, err = http.NewRequest(http.MethodPost, configUrl, strings.NewReader(formData.Encode()))
-			if err != nil {
-				return err
-			}
-			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-			if token != "" {
-				req.Header.Set(authHeader, "Bearer "+token)
-			}
-
-			resp, err = http.DefaultClient.Do(req)
-			if err != nil {
-				return err
-			}
-			defer resp.Body.Close()
-
-			if resp.StatusCode != http.StatusOK {
-				return fmt.Errorf("response error,status code:%d", resp.StatusCode)
-			}
-		} else {
-			return fmt.Errorf("response error,status code:%d", resp.StatusCode)
-		}
+		return nil, err
 	}
 
-	return nil
+	return c.decrypt(detail)
+}
+
+// detail 获取配置详情，加密的内容不解密
+func (c *Client) detail(ctx context.Context, operation ConfigGetOperation) (*NacosConfigDetail, error) {
+	return c.api(ctx).detail(ctx, operation)
 }
 
 // DeleteConfig 删除配置
 func (c *Client) DeleteConfig(operation ConfigDeleteOperation) error {
-	configUrl, err := getUrl(c.Config)
-	if err != nil {
-		return err
-	}
-
-	// 获取token
-	token, err := GetAccessToken(c.Config)
-	if err != nil {
-		return fmt.Errorf("failed to get access token: %w", err)
-	}
+	return c.DeleteConfigContext(context.Background(), operation)
+}
 
-	// Nacos API 中 public 命名空间用空字符串表示
-	// 但删除时不应该包含 tenant 参数（而不是传空字符串）
-	var deleteUrl string
-	if operation.Namespace == "public" || operation.Namespace == "" {
-		deleteUrl = fmt.Sprintf("%s?dataId=%s&group=%s", configUrl, operation.DataId, operation.Group)
-	} else {
-		deleteUrl = fmt.Sprintf("%s?dataId=%s&group=%s&tenant=%s", configUrl, operation.DataId, operation.Group, operation.Namespace)
-	}
+// DeleteConfigContext 删除配置，ctx 取消或超时时中断请求
+func (c *Client) DeleteConfigContext(ctx context.Context, operation ConfigDeleteOperation) error {
+	return c.api(ctx).delete(ctx, operation)
+}
 
-	req, err := http.NewRequest(http.MethodDelete, deleteUrl, nil)
-	if err != nil {
-		return err
+// tenantOf 将命名空间转换为配置接口（v1、v2、gRPC）的 tenant 参数，public 命名空间用空字符串表示
+func tenantOf(namespace string) string {
+	if namespace == "public" {
+		return ""
 	}
+	return namespace
+}
 
-	if token != "" {
-		req.Header.Set(authHeader, "Bearer "+token)
-	}
+// getUrl 获取 v1 配置接口路径
+func getUrl() (string, error) {
+	return serverPath(ApiVersionV1, baseUrl)
+}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return err
+// v1Url 获取 v1 配置接口路径，用于长轮询监听、灰度查询和停止、导入导出等没有实现 v2/v3 版本的接口。
+// Nacos 3.x 不再提供 v1 配置接口，协商为 v3 时返回 ErrUnsupportedOnV3，feature 为错误中的功能名
+func (c *Client) v1Url(ctx context.Context, feature string) (string, error) {
+	api := c.api(ctx)
+	if g, ok := api.(grpcAPI); ok {
+		api = g.configAPI
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		// 检查是否是认证错误
-		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
-			_ = ClearAccessToken(c.Config.Addr)
-			token, err := GetAccessToken(c.Config)
-			if err != nil {
-				return fmt.Errorf("authentication failed: %w", err)
-			}
-
-			req, err = http.NewRequest(http.MethodDelete, deleteUrl, nil)
-			if err != nil {
-				return err
-			}
-			if token != "" {
-				req.Header.Set(authHeader, "Bearer "+token)
-			}
-
-			resp, err = http.DefaultClient.Do(req)
-			if err != nil {
-				return err
-			}
-			defer resp.Body.Close()
-
-			if resp.StatusCode != http.StatusOK {
-				return fmt.Errorf("response error,status code:%d", resp.StatusCode)
-			}
-		} else {
-			return fmt.Errorf("response error,status code:%d", resp.StatusCode)
-		}
+	if _, ok := api.(v3API); ok {
+		return "", fmt.Errorf("%s: %w", feature, ErrUnsupportedOnV3)
 	}
-
-	return nil
-}
-
-func getUrl(config *NacosConfig) (string, error) {
-	return url.JoinPath(config.Addr, config.ApiVersion, baseUrl)
+	return getUrl()
 }
 
 func NewDefaultClient() *Client {
 
+	// 多个节点用逗号分隔
 	addr := os.Getenv("NACOS_ADDR")
 	apiVersion := os.Getenv("NACOS_API_VERSION")
 	username := os.Getenv("NACOS_USERNAME")
 	password := os.Getenv("NACOS_PASSWORD")
 
-	if addr == "" {
+	endpoint := os.Getenv("NACOS_ENDPOINT")
+	if addr == "" && endpoint == "" {
 		addr = "http://127.0.0.1:8848/nacos"
 	}
 
 	if apiVersion == "" {
-		apiVersion = "v1"
-	}
-
-	return &Client{
-		Config: &NacosConfig{
-			Addr:       addr,
-			ApiVersion: apiVersion,
-			Username:   username,
-			Password:   password,
-		},
-	}
+		apiVersion = ApiVersionAuto
+	}
+
+	return New(addr, withConfig(&NacosConfig{
+		Addr:          addr,
+		ApiVersion:    apiVersion,
+		Transport:     os.Getenv("NACOS_TRANSPORT"),
+		Endpoint:      endpoint,
+		Username:      username,
+		Password:      password,
+		Token:         os.Getenv("NACOS_TOKEN"),
+		AccessKey:     os.Getenv("NACOS_ACCESS_KEY"),
+		SecretKey:     os.Getenv("NACOS_SECRET_KEY"),
+		IdentityKey:   os.Getenv("NACOS_AUTH_IDENTITY_KEY"),
+		IdentityValue: os.Getenv("NACOS_AUTH_IDENTITY_VALUE"),
+	}))
 }
 
-// NewClient 创建自定义配置的客户端
+// NewClient 创建自定义配置的客户端，与 New 一样按 DefaultRetryPolicy 重试
 func NewClient(addr, apiVersion, username, password string) *Client {
 	if apiVersion == "" {
-		apiVersion = "v1"
-	}
-
-	return &Client{
-		Config: &NacosConfig{
-			Addr:       addr,
-			ApiVersion: apiVersion,
-			Username:   username,
-			Password:   password,
-		},
+		apiVersion = ApiVersionAuto
 	}
+	return New(addr, WithAPIVersion(apiVersion), WithCredentials(username, password))
 }
@@ -1,15 +1,22 @@
 package nacos
 
 import (
+	"context"
+	"github.com/Talbot3/nacos-cli/pkg/nacos/nacostest"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewDefaultClient(t *testing.T) {
 	client := NewDefaultClient()
 	assert.NotNil(t, client)
 	assert.NotNil(t, client.Config)
+	assert.Equal(t, DefaultRetryPolicy(), client.RetryPolicy)
 }
 
 func TestNewClient(t *testing.T) {
@@ -20,16 +27,14 @@ func TestNewClient(t *testing.T) {
 	assert.Equal(t, "v1", client.Config.ApiVersion)
 	assert.Equal(t, "nacos", client.Config.Username)
 	assert.Equal(t, "nacos", client.Config.Password)
+	assert.Equal(t, DefaultRetryPolicy(), client.RetryPolicy, "所有构造函数使用相同的默认重试策略")
 }
 
 func TestGetUrl(t *testing.T) {
-	config := &NacosConfig{
-		Addr:       "http://localhost:8848/nacos",
-		ApiVersion: "v1",
-	}
-	url, err := getUrl(config)
+	url, err := getUrl()
 	assert.Nil(t, err)
-	assert.Equal(t, "http://localhost:8848/nacos/v1/cs/configs", url)
+	assert.Equal(t, "/v1/cs/configs", url)
+	assert.Equal(t, "http://localhost:8848/nacos/v1/cs/configs", nodeURL("http://localhost:8848/nacos/", url))
 }
 
 func TestIsTokenValid(t *testing.T) {
@@ -50,3 +55,101 @@ func TestIsTokenValid(t *testing.T) {
 		})
 	}
 }
+
+// slowServer 挂起请求直到客户端断开，/v1/console/server/state 返回 2.3.0
+func slowServer(t *testing.T) *httptest.Server {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/nacos/v1/console/server/state" {
+			_, _ = w.Write([]byte(`{"version":"2.3.0"}`))
+			return
+		}
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	t.Cleanup(func() {
+		close(release)
+		server.Close()
+	})
+	return server
+}
+
+func TestGetContextCanceled(t *testing.T) {
+	server := slowServer(t)
+	client := NewClient(server.URL+"/nacos", ApiVersionV1, "", "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.GetContext(ctx, ConfigGetOperation{NacosOperation: &NacosOperation{Group: "G"}, DataId: "app.yaml"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second, "取消后不再重试")
+}
+
+func TestRequestTimeout(t *testing.T) {
+	server := slowServer(t)
+	client := NewClient(server.URL+"/nacos", ApiVersionV1, "", "")
+	client.RequestTimeout = 50 * time.Millisecond
+
+	err := client.DeleteConfig(ConfigDeleteOperation{NacosOperation: &NacosOperation{Group: "G"}, DataId: "app.yaml"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestDetectVersionCanceled(t *testing.T) {
+	server := slowServer(t)
+	client := NewClient(server.URL+"/nacos", ApiVersionAuto, "", "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.api(ctx)
+	assert.Nil(t, client.configAPI, "取消时的探测结果不缓存")
+
+	assert.IsType(t, v2API{}, client.api(context.Background()))
+}
+
+func TestEditPreservesMetadata(t *testing.T) {
+	server := nacostest.NewServer()
+	defer server.Close()
+	server.SetConfig(nacostest.Config{
+		Group:   "DEFAULT_GROUP",
+		DataId:  "app.yaml",
+		Content: "port: 8080",
+		Type:    "yaml",
+		AppName: "order",
+		Desc:    "订单服务",
+		Tags:    "team=payments",
+	})
+
+	client := New(server.Addr)
+	require.NoError(t, client.Edit(ConfigEditOperation{
+		NacosOperation: &NacosOperation{Group: "DEFAULT_GROUP"},
+		DataId:         "app.yaml",
+		Content:        "port: 9090",
+	}))
+
+	config, _ := server.Config("public", "DEFAULT_GROUP", "app.yaml")
+	assert.Equal(t, "port: 9090", config.Content)
+	assert.Equal(t, "yaml", config.Type)
+	assert.Equal(t, "order", config.AppName)
+	assert.Equal(t, "订单服务", config.Desc)
+	assert.Equal(t, "team=payments", config.Tags)
+	assert.IsType(t, v1API{}, client.api(context.Background()), "按服务端版本选择 v1 接口")
+}
+
+func TestDeleteConfigPublicTenant(t *testing.T) {
+	server := nacostest.NewServer()
+	defer server.Close()
+	server.SetConfig(nacostest.Config{Group: "DEFAULT_GROUP", DataId: "app.yaml", Content: "a: 1"})
+	server.SetConfig(nacostest.Config{Namespace: "dev", Group: "DEFAULT_GROUP", DataId: "app.yaml", Content: "a: 1"})
+
+	client := New(server.Addr)
+	require.NoError(t, client.DeleteConfig(ConfigDeleteOperation{NacosOperation: &NacosOperation{Namespace: "public", Group: "DEFAULT_GROUP"}, DataId: "app.yaml"}))
+
+	_, ok := server.Config("", "DEFAULT_GROUP", "app.yaml")
+	assert.False(t, ok)
+	_, ok = server.Config("dev", "DEFAULT_GROUP", "app.yaml")
+	assert.True(t, ok, "其他命名空间的同名配置不受影响")
+}
@@ -1,48 +1,90 @@
 package nacos
 
 import (
-	"fmt"
+	"context"
+	"github.com/Talbot3/nacos-cli/pkg/render"
+	"github.com/Talbot3/nacos-cli/pkg/secret"
 	"io"
 	"os"
 	"path"
 	"strings"
 )
 
-// ApplyConfig 新增 or 修改配置
-// configFile:
+// templateExt 模板文件扩展名，渲染后从 dataId 中去掉
+const templateExt = ".tmpl"
+
+// ApplyConfig 读取配置文件并新增或修改配置，见 ConfigApplyOperation.ToEdit
 func (c *Client) ApplyConfig(operation ConfigApplyOperation) error {
+	return c.ApplyConfigContext(context.Background(), operation)
+}
 
-	file, err := os.Open(operation.File)
+// ApplyConfigContext 新增或修改配置，ctx 取消或超时时中断请求
+func (c *Client) ApplyConfigContext(ctx context.Context, operation ConfigApplyOperation) error {
+
+	edit, err := operation.ToEdit()
 
 	if err != nil {
 		return err
 	}
 
+	return c.EditContext(ctx, edit)
+}
+
+// ToEdit 读取配置文件并转换为更新操作。
+// 文件以 .tmpl 结尾或指定了 Values 时先按 Go text/template 渲染，
+// 此时 dataId 和类型从去掉 .tmpl 后的文件名推断。
+// 渲染后展开 ${env:X}、${file:/path} 等密钥引用，解析出的值记录在 SecretValues 中
+func (operation ConfigApplyOperation) ToEdit() (ConfigEditOperation, error) {
+
+	file, err := os.Open(operation.File)
+
+	if err != nil {
+		return ConfigEditOperation{}, err
+	}
+	defer file.Close()
+
 	buf, err := io.ReadAll(file)
 
 	if err != nil {
-		return err
+		return ConfigEditOperation{}, err
+	}
+
+	name := path.Base(operation.File)
+	content := string(buf)
+
+	if strings.HasSuffix(name, templateExt) || operation.Values != nil {
+		name = strings.TrimSuffix(name, templateExt)
+		if content, err = render.Render(name, content, operation.Values); err != nil {
+			return ConfigEditOperation{}, err
+		}
+	}
+
+	resolver := operation.Secrets
+	if resolver == nil {
+		resolver = secret.DefaultResolver()
+	}
+	content, secretValues, err := resolver.Expand(content)
+	if err != nil {
+		return ConfigEditOperation{}, err
 	}
 
 	dataType := operation.Type
 
 	if dataType == "" {
-		dataType = strings.ReplaceAll(path.Ext(operation.File), ".", "")
+		dataType = strings.ReplaceAll(path.Ext(name), ".", "")
 	}
 
 	if operation.DataId == "" {
-		operation.DataId = path.Base(operation.File)
+		operation.DataId = name
 	}
 
-	if err = c.Edit(ConfigEditOperation{
+	return ConfigEditOperation{
 		NacosOperation: operation.NacosOperation,
-		Content:        string(buf),
+		ConfigMetadata: operation.ConfigMetadata,
+		Content:        content,
 		DataId:         operation.DataId,
 		Type:           dataType,
-	}); err != nil {
-		return err
-	}
-	fmt.Println("OK!")
-	return nil
+		BetaIps:        operation.BetaIps,
+		SecretValues:   secretValues,
+	}, nil
 }
-
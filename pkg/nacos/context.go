@@ -0,0 +1,99 @@
+package nacos
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	contextFileName = "config.yaml"
+	contextFileEnv  = "NACOSCTL_CONFIG" // 覆盖上下文配置文件路径
+)
+
+// NacosContext 命名的 Nacos 服务器连接配置
+type NacosContext struct {
+	Name        string `json:"name" yaml:"name"`
+	NacosConfig `yaml:",inline"`
+}
+
+// ContextFile 上下文配置文件，默认位于 ~/.nacosctl/config.yaml
+//
+//	contexts:
+//	  - name: test
+//	    addr: http://test-nacos:8848/nacos
+//	    username: nacos
+//	    password: nacos
+//	  - name: mse
+//	    addr: http://mse-xxx.nacos.mse.aliyuncs.com:8848/nacos
+//	    accessKey: LTAI...
+//	    secretKey: ...
+type ContextFile struct {
+	Contexts []NacosContext `json:"contexts" yaml:"contexts"`
+}
+
+// contextFilePath 获取上下文配置文件路径
+func contextFilePath() (string, error) {
+	if path := os.Getenv(contextFileEnv); path != "" {
+		return path, nil
+	}
+
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, contextFileName), nil
+}
+
+// LoadContextFile 读取上下文配置文件，文件不存在时返回空配置
+func LoadContextFile() (*ContextFile, error) {
+	path, err := contextFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &ContextFile{}, nil
+		}
+		return nil, err
+	}
+
+	file := &ContextFile{}
+	if err := yaml.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("invalid context file %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// Context 按名称查找上下文
+func (f *ContextFile) Context(name string) (*NacosContext, error) {
+	for i := range f.Contexts {
+		if f.Contexts[i].Name == name {
+			return &f.Contexts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("context %q not found", name)
+}
+
+// NewContextClient 根据上下文名称创建客户端，与 New 一样按 DefaultRetryPolicy 重试
+func NewContextClient(name string) (*Client, error) {
+	file, err := LoadContextFile()
+	if err != nil {
+		return nil, err
+	}
+
+	nacosContext, err := file.Context(name)
+	if err != nil {
+		return nil, err
+	}
+
+	config := nacosContext.NacosConfig
+	if config.ApiVersion == "" {
+		config.ApiVersion = ApiVersionAuto
+	}
+	return New(config.Addr, withConfig(&config)), nil
+}
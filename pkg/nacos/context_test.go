@@ -0,0 +1,31 @@
+package nacos
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewContextClient(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`contexts:
+  - name: prod
+    addr: http://prod-nacos:8848/nacos
+    username: admin
+    password: secret
+`), 0600))
+	t.Setenv(contextFileEnv, path)
+
+	client, err := NewContextClient("prod")
+	require.NoError(t, err)
+	assert.Equal(t, "http://prod-nacos:8848/nacos", client.Config.Addr)
+	assert.Equal(t, ApiVersionAuto, client.Config.ApiVersion)
+	assert.Equal(t, "admin", client.Config.Username)
+	assert.Equal(t, DefaultRetryPolicy(), client.RetryPolicy)
+
+	_, err = NewContextClient("missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,24 @@
+// Package nacos 是 Nacos 配置管理和服务发现的 Go 客户端，nacosctl 的所有命令都基于它实现。
+//
+// 通过 New 和 Option 创建客户端：
+//
+//	client := nacos.New("http://127.0.0.1:8848/nacos",
+//		nacos.WithCredentials("nacos", "nacos"),
+//		nacos.WithRequestTimeout(10*time.Second))
+//	defer client.Close()
+//
+// 每个方法都有带 context.Context 的版本（如 GetContext），ctx 取消或超时时中断请求和重试；
+// 不带 ctx 的版本使用 context.Background()。*Client 实现了 ConfigService 和 NamingService 接口，
+// 调用方可以依赖接口并在测试中替换为 mock。
+//
+// # 命名空间
+//
+// 操作中的 Namespace 为命名空间 ID，"" 和 "public" 都表示默认命名空间，客户端按接口要求转换：
+// 配置的 v1、v2 接口及 gRPC 请求中默认命名空间的 tenant 为空字符串，
+// v3 接口和服务发现接口的 namespaceId 为 "public"。
+//
+// # 输出
+//
+// 库代码不向 stdout/stderr 输出任何内容。不影响结果的警告（如 token 缓存写入失败）交给 WithLogger 设置的函数，
+// 需要记录请求时可以通过 WithRoundTripper 使用 TraceTransport。
+package nacos
@@ -0,0 +1,94 @@
+package nacos_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/Talbot3/nacos-cli/pkg/nacos"
+)
+
+func ExampleNew() {
+	// 模拟 Nacos 配置查询接口
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprintf(w, "greeting: hello from %s", r.URL.Query().Get("dataId"))
+	}))
+	defer server.Close()
+
+	client := nacos.New(server.URL+"/nacos",
+		nacos.WithAPIVersion(nacos.ApiVersionV1),
+		nacos.WithRequestTimeout(5*time.Second))
+	defer client.Close()
+
+	detail, err := client.GetContext(context.Background(), nacos.ConfigGetOperation{
+		NacosOperation: &nacos.NacosOperation{Namespace: "public", Group: "DEFAULT_GROUP"},
+		DataId:         "app.yaml",
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(detail.Content)
+	// Output: greeting: hello from app.yaml
+}
+
+// stubConfigService 测试中替换 *nacos.Client 的 ConfigService，只实现用到的方法
+type stubConfigService struct {
+	nacos.ConfigService
+	configs map[string]string
+}
+
+func (s stubConfigService) GetContext(ctx context.Context, operation nacos.ConfigGetOperation) (*nacos.NacosConfigDetail, error) {
+	content, ok := s.configs[operation.DataId]
+	if !ok {
+		return nil, nacos.ErrConfigNotExist
+	}
+	return &nacos.NacosConfigDetail{DataID: operation.DataId, Content: content}, nil
+}
+
+// featureEnabled 依赖 ConfigService 接口而不是 *nacos.Client
+func featureEnabled(ctx context.Context, configs nacos.ConfigService, name string) bool {
+	detail, err := configs.GetContext(ctx, nacos.ConfigGetOperation{
+		NacosOperation: &nacos.NacosOperation{Group: "FEATURES"},
+		DataId:         name,
+	})
+	return err == nil && detail.Content == "on"
+}
+
+func ExampleConfigService() {
+	configs := stubConfigService{configs: map[string]string{"dark-mode": "on"}}
+
+	fmt.Println(featureEnabled(context.Background(), configs, "dark-mode"))
+	fmt.Println(featureEnabled(context.Background(), configs, "beta-checkout"))
+	// Output:
+	// true
+	// false
+}
+
+func ExampleClient_ListInstancesContext() {
+	// 模拟 Nacos 实例查询接口
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"hosts":[{"ip":"10.0.0.1","port":8080,"healthy":true,"serviceName":"%s@@%s"}]}`,
+			r.URL.Query().Get("groupName"), r.URL.Query().Get("serviceName"))
+	}))
+	defer server.Close()
+
+	client := nacos.New(server.URL + "/nacos")
+
+	instances, err := client.ListInstancesContext(context.Background(), nacos.InstanceListOperation{
+		NacosOperation: &nacos.NacosOperation{Group: "DEFAULT_GROUP"},
+		ServiceName:    "order",
+		HealthyOnly:    true,
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, instance := range instances {
+		fmt.Printf("%s %s:%d\n", instance.ServiceName, instance.Ip, instance.Port)
+	}
+	// Output: DEFAULT_GROUP@@order 10.0.0.1:8080
+}
@@ -0,0 +1,220 @@
+package nacos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/encrypt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Export 导出配置，返回与 Nacos 控制台一致的 zip 导出包内容
+func (c *Client) Export(operation ConfigExportOperation) ([]byte, error) {
+	return c.ExportContext(context.Background(), operation)
+}
+
+// ExportContext 导出配置，ctx 取消或超时时中断请求
+func (c *Client) ExportContext(ctx context.Context, operation ConfigExportOperation) ([]byte, error) {
+	configUrl, err := c.v1Url(ctx, "export")
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("exportV2", "true")
+	query.Set("tenant", tenantOf(operation.Namespace))
+	query.Set("group", operation.Group)
+	query.Set("appName", operation.AppName)
+	query.Set("dataId", "")
+	query.Set("ids", strings.Join(operation.Ids, ","))
+
+	resp, body, err := c.send(ctx, http.MethodGet, configUrl+"?"+query.Encode(), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("response error,status code:%d\n%s", resp.StatusCode, body)
+	}
+
+	return c.addDataKeys(ctx, operation.NacosOperation, body)
+}
+
+// addDataKeys 服务端导出包不包含 cipher- 配置的数据密钥，逐个查询后写入 .metadata.yml，
+// 否则导入后的密文无法解密。没有 cipher- 配置时原样返回
+func (c *Client) addDataKeys(ctx context.Context, operation *NacosOperation, data []byte) ([]byte, error) {
+	items, err := ReadConfigArchive(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var ciphers []int
+	for i, item := range items {
+		if encrypt.IsCipherDataId(item.DataId) {
+			ciphers = append(ciphers, i)
+		}
+	}
+	if len(ciphers) == 0 {
+		return data, nil
+	}
+
+	err = parallel(len(ciphers), func(i int) error {
+		item := &items[ciphers[i]]
+		detail, err := c.detail(ctx, ConfigGetOperation{
+			NacosOperation: &NacosOperation{Namespace: operation.Namespace, Group: item.Group},
+			DataId:         item.DataId,
+		})
+		if err != nil {
+			return fmt.Errorf("get data key of %s: %w", item.DataId, err)
+		}
+		// 密文与数据密钥取自同一次查询，避免导出期间配置被更新导致两者不匹配
+		item.Content = detail.Content
+		item.EncryptedDataKey = detail.EncryptedDataKey
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &bytes.Buffer{}
+	if err := WriteConfigArchive(archive, items); err != nil {
+		return nil, err
+	}
+	return archive.Bytes(), nil
+}
+
+// Import 导入配置，按 Policy 处理已存在的同名配置
+func (c *Client) Import(operation ConfigImportOperation) (*ImportResult, error) {
+	return c.ImportContext(context.Background(), operation)
+}
+
+// ImportContext 导入配置，ctx 取消或超时时中断请求。
+// 带数据密钥的 cipher- 配置不能通过导入接口保存密钥，改为逐个发布
+func (c *Client) ImportContext(ctx context.Context, operation ConfigImportOperation) (*ImportResult, error) {
+	if len(operation.Items) == 0 {
+		return nil, errors.New("no config to import")
+	}
+
+	policy := operation.Policy
+	if policy == "" {
+		policy = ImportPolicyAbort
+	}
+
+	var items, encrypted []ConfigArchiveItem
+	for _, item := range operation.Items {
+		if item.EncryptedDataKey != "" {
+			encrypted = append(encrypted, item)
+		} else {
+			items = append(items, item)
+		}
+	}
+
+	result := &ImportResult{}
+	if len(items) > 0 {
+		var err error
+		result, err = c.importArchive(ctx, operation.NacosOperation, items, policy)
+		if err != nil || policy == ImportPolicyAbort && len(result.FailData) > 0 {
+			return result, err
+		}
+	}
+
+	for _, item := range encrypted {
+		if err := c.importEncrypted(ctx, operation.NacosOperation, item, policy, result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// importArchive 通过导入接口上传不含数据密钥的配置
+func (c *Client) importArchive(ctx context.Context, operation *NacosOperation, items []ConfigArchiveItem, policy ImportPolicy) (*ImportResult, error) {
+	configUrl, err := c.v1Url(ctx, "import")
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &bytes.Buffer{}
+	if err := WriteConfigArchive(archive, items); err != nil {
+		return nil, err
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "nacos_config_import.zip")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(archive.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("import", "true")
+	query.Set("namespace", tenantOf(operation.Namespace))
+	query.Set("policy", strings.ToUpper(string(policy)))
+
+	header := http.Header{}
+	header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, data, err := c.send(ctx, http.MethodPost, configUrl+"?"+query.Encode(), header, body.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("response error,status code:%d\n%s", resp.StatusCode, data)
+	}
+
+	result := restResult[ImportResult]{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	if result.Code != http.StatusOK {
+		return &result.Data, fmt.Errorf("import failed: %s", result.Message)
+	}
+
+	return &result.Data, nil
+}
+
+// importEncrypted 发布单个带数据密钥的配置，密文和数据密钥原样保存，按 policy 处理已存在的配置
+func (c *Client) importEncrypted(ctx context.Context, operation *NacosOperation, item ConfigArchiveItem, policy ImportPolicy, result *ImportResult) error {
+	target := &NacosOperation{Namespace: operation.Namespace, Group: item.Group}
+	resultItem := ImportResultItem{DataId: item.DataId, Group: item.Group}
+
+	if policy != ImportPolicyOverwrite {
+		_, err := c.detail(ctx, ConfigGetOperation{NacosOperation: target, DataId: item.DataId})
+		switch {
+		case err == nil && policy == ImportPolicySkip:
+			result.SkipCount++
+			result.SkipData = append(result.SkipData, resultItem)
+			return nil
+		case err == nil:
+			result.FailData = append(result.FailData, resultItem)
+			return fmt.Errorf("import failed: %s (%s) already exists", item.DataId, item.Group)
+		case !errors.Is(err, ErrConfigNotExist):
+			return err
+		}
+	}
+
+	err := c.EditContext(ctx, ConfigEditOperation{
+		NacosOperation: target,
+		ConfigMetadata: ConfigMetadata{Desc: &item.Desc, AppName: &item.AppName},
+		DataId:         item.DataId,
+		Content:        item.Content,
+		Type:           item.Type,
+
+		EncryptedDataKey: item.EncryptedDataKey,
+	})
+	if err != nil {
+		result.FailData = append(result.FailData, resultItem)
+		return err
+	}
+	result.SuccCount++
+	return nil
+}
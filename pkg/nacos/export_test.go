@@ -0,0 +1,63 @@
+package nacos
+
+import (
+	"testing"
+
+	"github.com/Talbot3/nacos-cli/pkg/encrypt"
+	"github.com/Talbot3/nacos-cli/pkg/nacos/nacostest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportCipherConfig(t *testing.T) {
+	server := nacostest.NewServer()
+	defer server.Close()
+
+	key, err := encrypt.NewLocalKeyProvider([]byte("0123456789abcdef"))
+	require.NoError(t, err)
+	client := New(server.Addr)
+	client.KeyProvider = key
+
+	dev := &NacosOperation{Namespace: "dev", Group: "DEFAULT_GROUP"}
+	for dataId, content := range map[string]string{"cipher-aes-db.yaml": "password: s3cr3t", "app.yaml": "port: 8080"} {
+		require.NoError(t, client.Edit(ConfigEditOperation{NacosOperation: dev, DataId: dataId, Content: content, Type: "yaml"}))
+	}
+	stored, _ := server.Config("dev", "DEFAULT_GROUP", "cipher-aes-db.yaml")
+
+	// 导出不需要密钥，密文和数据密钥原样写入导出包
+	client.KeyProvider = nil
+	data, err := client.Export(ConfigExportOperation{NacosOperation: &NacosOperation{Namespace: "dev"}})
+	require.NoError(t, err)
+	items, err := ReadConfigArchive(data)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "app.yaml", items[0].DataId)
+	assert.Empty(t, items[0].EncryptedDataKey)
+	assert.Equal(t, "cipher-aes-db.yaml", items[1].DataId)
+	assert.Equal(t, stored.Content, items[1].Content)
+	assert.Equal(t, stored.EncryptedDataKey, items[1].EncryptedDataKey)
+
+	result, err := client.Import(ConfigImportOperation{NacosOperation: &NacosOperation{Namespace: "prod"}, Items: items})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.SuccCount)
+
+	imported, ok := server.Config("prod", "DEFAULT_GROUP", "cipher-aes-db.yaml")
+	require.True(t, ok)
+	assert.Equal(t, stored.EncryptedDataKey, imported.EncryptedDataKey)
+	assert.Equal(t, "yaml", imported.Type)
+
+	client.KeyProvider = key
+	detail, err := client.Get(ConfigGetOperation{NacosOperation: &NacosOperation{Namespace: "prod", Group: "DEFAULT_GROUP"}, DataId: "cipher-aes-db.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, "password: s3cr3t", detail.Content)
+
+	// 再次导入时按策略处理已存在的加密配置
+	result, err = client.Import(ConfigImportOperation{NacosOperation: &NacosOperation{Namespace: "prod"}, Items: items, Policy: ImportPolicySkip})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.SkipCount)
+	assert.Contains(t, result.SkipData, ImportResultItem{DataId: "cipher-aes-db.yaml", Group: "DEFAULT_GROUP"})
+
+	result, err = client.Import(ConfigImportOperation{NacosOperation: &NacosOperation{Namespace: "prod"}, Items: items[1:]})
+	assert.Error(t, err)
+	assert.Equal(t, []ImportResultItem{{DataId: "cipher-aes-db.yaml", Group: "DEFAULT_GROUP"}}, result.FailData)
+}
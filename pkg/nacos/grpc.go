@@ -0,0 +1,611 @@
+package nacos
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// 传输方式，对应 NACOS_TRANSPORT / --transport
+const (
+	TransportHTTP = "http"
+	TransportGRPC = "grpc"
+)
+
+const (
+	grpcPortOffset       = 1000 // Nacos 2.x gRPC 端口为 HTTP 端口 + 1000
+	grpcRequestMethod    = "/Request/request"
+	grpcBiStreamMethod   = "/BiRequestStream/requestBiStream"
+	grpcUnregisteredCode = 301 // 连接尚未在服务端注册完成
+	grpcRegisterRetries  = 10
+	grpcRegisterInterval = 100 * time.Millisecond
+	grpcClientVersion    = "nacosctl"
+)
+
+// grpcPayload Nacos gRPC 协议的 Payload 消息：
+//
+//	message Metadata { string type = 3; map<string, string> headers = 7; string clientIp = 8; }
+//	message Payload { Metadata metadata = 2; google.protobuf.Any body = 3; }
+//
+// body 的 value 为请求或响应的 JSON
+type grpcPayload struct {
+	Type     string
+	Headers  map[string]string
+	ClientIp string
+	Body     []byte
+}
+
+func (p *grpcPayload) marshal() []byte {
+	var metadata []byte
+	if p.Type != "" {
+		metadata = protowire.AppendTag(metadata, 3, protowire.BytesType)
+		metadata = protowire.AppendString(metadata, p.Type)
+	}
+
+	keys := make([]string, 0, len(p.Headers))
+	for key := range p.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var entry []byte
+		entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+		entry = protowire.AppendString(entry, key)
+		entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+		entry = protowire.AppendString(entry, p.Headers[key])
+		metadata = protowire.AppendTag(metadata, 7, protowire.BytesType)
+		metadata = protowire.AppendBytes(metadata, entry)
+	}
+
+	if p.ClientIp != "" {
+		metadata = protowire.AppendTag(metadata, 8, protowire.BytesType)
+		metadata = protowire.AppendString(metadata, p.ClientIp)
+	}
+
+	var body []byte
+	body = protowire.AppendTag(body, 2, protowire.BytesType)
+	body = protowire.AppendBytes(body, p.Body)
+
+	var data []byte
+	data = protowire.AppendTag(data, 2, protowire.BytesType)
+	data = protowire.AppendBytes(data, metadata)
+	data = protowire.AppendTag(data, 3, protowire.BytesType)
+	data = protowire.AppendBytes(data, body)
+	return data
+}
+
+func (p *grpcPayload) unmarshal(data []byte) error {
+	return consumeFields(data, func(num protowire.Number, value []byte) error {
+		switch num {
+		case 2:
+			return consumeFields(value, func(num protowire.Number, value []byte) error {
+				switch num {
+				case 3:
+					p.Type = string(value)
+				case 7:
+					var key, val string
+					err := consumeFields(value, func(num protowire.Number, value []byte) error {
+						if num == 1 {
+							key = string(value)
+						} else if num == 2 {
+							val = string(value)
+						}
+						return nil
+					})
+					if err != nil {
+						return err
+					}
+					if p.Headers == nil {
+						p.Headers = make(map[string]string)
+					}
+					p.Headers[key] = val
+				case 8:
+					p.ClientIp = string(value)
+				}
+				return nil
+			})
+		case 3:
+			return consumeFields(value, func(num protowire.Number, value []byte) error {
+				if num == 2 {
+					p.Body = append([]byte(nil), value...)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+}
+
+// consumeFields 遍历消息的字段，fn 只接收 length-delimited 字段，其他类型的字段被跳过
+func consumeFields(data []byte, fn func(num protowire.Number, value []byte) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			data = data[n:]
+			continue
+		}
+
+		value, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		if err := fn(num, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// payloadCodec 直接编解码 grpcPayload，无需生成 protobuf 代码
+type payloadCodec struct{}
+
+func (payloadCodec) Marshal(v any) ([]byte, error) {
+	p, ok := v.(*grpcPayload)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+	return p.marshal(), nil
+}
+
+func (payloadCodec) Unmarshal(data []byte, v any) error {
+	p, ok := v.(*grpcPayload)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+	return p.unmarshal(data)
+}
+
+func (payloadCodec) Name() string {
+	return "proto"
+}
+
+// grpcRequest 请求 JSON 的公共字段
+type grpcRequest struct {
+	RequestId string            `json:"requestId"`
+	Headers   map[string]string `json:"headers"`
+	Module    string            `json:"module,omitempty"`
+}
+
+// grpcResponse 响应 JSON 的公共字段
+type grpcResponse struct {
+	ResultCode int    `json:"resultCode"`
+	ErrorCode  int    `json:"errorCode"`
+	Message    string `json:"message"`
+	RequestId  string `json:"requestId"`
+}
+
+// grpcConn 到 Nacos gRPC 端口的连接。
+// 首次请求时完成 ServerCheck 和双向流上的 ConnectionSetup，服务端通过双向流推送配置变更通知。
+// 双向流断开（服务端重启、网络中断）后连接被重置，下次请求重新连接并注册，
+// 等待推送的监听随之返回，由 Watch 重新发送 ConfigBatchListen 注册监听
+type grpcConn struct {
+	client *Client
+	target string
+
+	connMu sync.Mutex
+	conn   *grpc.ClientConn // 为 nil 时未连接
+	stream grpc.ClientStream
+	cancel context.CancelFunc
+	lost   chan struct{} // 当前连接断开时关闭
+
+	sendMu    sync.Mutex
+	requestId atomic.Int64
+
+	listenersMu sync.Mutex
+	listeners   map[ConfigKey]map[chan ConfigKey]struct{} // 每个配置的监听者，服务端推送的变更只转发给监听该配置的 channel
+}
+
+func newGrpcConn(client *Client) *grpcConn {
+	return &grpcConn{
+		client:    client,
+		listeners: map[ConfigKey]map[chan ConfigKey]struct{}{},
+	}
+}
+
+// listenerKey 规范化配置作为监听者的键，public 命名空间与推送中的空 tenant 一致
+func listenerKey(namespace, group, dataId string) ConfigKey {
+	return ConfigKey{Namespace: tenantOf(namespace), Group: group, DataId: dataId}
+}
+
+// subscribe 注册接收 keys 变更通知的 channel，多个 Watch 共用连接时各自只收到自己监听的配置。
+// 返回的函数取消注册
+func (g *grpcConn) subscribe(keys []ConfigKey) (<-chan ConfigKey, func()) {
+	ch := make(chan ConfigKey, len(keys))
+
+	g.listenersMu.Lock()
+	defer g.listenersMu.Unlock()
+	for _, key := range keys {
+		k := listenerKey(key.Namespace, key.Group, key.DataId)
+		if g.listeners[k] == nil {
+			g.listeners[k] = map[chan ConfigKey]struct{}{}
+		}
+		g.listeners[k][ch] = struct{}{}
+	}
+
+	return ch, func() {
+		g.listenersMu.Lock()
+		defer g.listenersMu.Unlock()
+		for _, key := range keys {
+			k := listenerKey(key.Namespace, key.Group, key.DataId)
+			delete(g.listeners[k], ch)
+			if len(g.listeners[k]) == 0 {
+				delete(g.listeners, k)
+			}
+		}
+	}
+}
+
+// dispatch 将服务端推送的变更转发给监听该配置的所有 channel
+func (g *grpcConn) dispatch(key ConfigKey) {
+	g.listenersMu.Lock()
+	defer g.listenersMu.Unlock()
+	for ch := range g.listeners[key] {
+		select {
+		case ch <- key:
+		default:
+			// channel 已满说明监听者还未处理之前的通知，重新监听时服务端会比较 MD5
+		}
+	}
+}
+
+// grpcTarget 根据 HTTP 地址计算 gRPC 地址：主机不变，端口加 1000
+func grpcTarget(addr string) (string, bool, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return "", false, err
+	}
+	if u.Hostname() == "" {
+		return "", false, fmt.Errorf("invalid nacos address %q", addr)
+	}
+
+	secure := u.Scheme == "https"
+	port := 8848
+	if p := u.Port(); p != "" {
+		if port, err = strconv.Atoi(p); err != nil {
+			return "", false, fmt.Errorf("invalid nacos address %q: %w", addr, err)
+		}
+	} else if secure {
+		port = 443
+	}
+
+	return net.JoinHostPort(u.Hostname(), strconv.Itoa(port+grpcPortOffset)), secure, nil
+}
+
+// ready 返回已注册的连接，未连接或连接已断开时重新连接并注册
+func (g *grpcConn) ready(ctx context.Context) (*grpc.ClientConn, error) {
+	g.connMu.Lock()
+	defer g.connMu.Unlock()
+
+	if g.conn != nil {
+		return g.conn, nil
+	}
+	if err := g.connect(ctx); err != nil {
+		return nil, err
+	}
+	return g.conn, nil
+}
+
+// lostCh 返回当前连接断开时关闭的 channel，未连接时返回 nil
+func (g *grpcConn) lostCh() <-chan struct{} {
+	g.connMu.Lock()
+	defer g.connMu.Unlock()
+	return g.lost
+}
+
+func (g *grpcConn) connect(ctx context.Context) error {
+	if g.target != "" {
+		return g.dial(ctx, g.target, false)
+	}
+
+	// 多个节点时依次尝试，直到 ServerCheck 成功
+	servers, err := g.client.servers()
+	if err != nil {
+		return err
+	}
+	var lastErr error
+	for _, addr := range servers.pick() {
+		target, secure, err := grpcTarget(addr)
+		if err == nil {
+			if err = g.dial(ctx, target, secure); err == nil {
+				return nil
+			}
+		}
+		servers.markDown(addr)
+		lastErr = err
+	}
+	return lastErr
+}
+
+// dial 连接指定节点并完成注册，成功后保存连接，调用方持有 connMu
+func (g *grpcConn) dial(ctx context.Context, target string, secure bool) error {
+	creds := insecure.NewCredentials()
+	if secure {
+		creds = credentials.NewTLS(&tls.Config{})
+	}
+
+	conn, err := grpc.Dial(target,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(payloadCodec{})),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := g.invoke(ctx, conn, "ServerCheckRequest", &grpcRequest{}, &grpcResponse{}); err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("grpc server check %s: %w", target, err)
+	}
+
+	// 双向流的生命周期与连接相同，不受单次请求的 ctx 影响
+	streamCtx, cancel := context.WithCancel(context.Background())
+	stream, err := conn.NewStream(streamCtx, &grpc.StreamDesc{
+		StreamName:    "requestBiStream",
+		ServerStreams: true,
+		ClientStreams: true,
+	}, grpcBiStreamMethod)
+	if err != nil {
+		cancel()
+		_ = conn.Close()
+		return err
+	}
+
+	setup := struct {
+		grpcRequest
+		ClientVersion string            `json:"clientVersion"`
+		Abilities     map[string]any    `json:"abilities"`
+		Tenant        string            `json:"tenant"`
+		Labels        map[string]string `json:"labels"`
+	}{
+		grpcRequest:   grpcRequest{Headers: map[string]string{}, Module: "internal"},
+		ClientVersion: grpcClientVersion,
+		Abilities:     map[string]any{},
+		Labels:        map[string]string{"source": "sdk", "module": "config"},
+	}
+	if err := g.sendStream(stream, "ConnectionSetupRequest", setup); err != nil {
+		cancel()
+		_ = conn.Close()
+		return err
+	}
+
+	g.conn, g.stream, g.cancel, g.lost = conn, stream, cancel, make(chan struct{})
+	go g.receive(conn, stream)
+	return nil
+}
+
+// reset 关闭 conn，连接已被替换时不做处理
+func (g *grpcConn) reset(conn *grpc.ClientConn) {
+	g.connMu.Lock()
+	defer g.connMu.Unlock()
+
+	if g.conn == conn {
+		g.closeLocked()
+	}
+}
+
+// current 判断 conn 是否仍是当前连接
+func (g *grpcConn) current(conn *grpc.ClientConn) bool {
+	g.connMu.Lock()
+	defer g.connMu.Unlock()
+	return g.conn == conn
+}
+
+func (g *grpcConn) closeLocked() error {
+	if g.conn == nil {
+		return nil
+	}
+	g.cancel()
+	close(g.lost)
+	err := g.conn.Close()
+	g.conn, g.stream, g.cancel, g.lost = nil, nil, nil, nil
+	return err
+}
+
+// receive 处理服务端通过双向流推送的请求：配置变更通知转发给监听者，其余请求直接应答。
+// 双向流断开时重置连接
+func (g *grpcConn) receive(conn *grpc.ClientConn, stream grpc.ClientStream) {
+	defer g.reset(conn)
+
+	for {
+		payload := &grpcPayload{}
+		if err := stream.RecvMsg(payload); err != nil {
+			return
+		}
+		if !strings.HasSuffix(payload.Type, "Request") {
+			continue
+		}
+
+		request := struct {
+			RequestId string `json:"requestId"`
+			DataId    string `json:"dataId"`
+			Group     string `json:"group"`
+			Tenant    string `json:"tenant"`
+		}{}
+		if err := json.Unmarshal(payload.Body, &request); err != nil {
+			continue
+		}
+
+		if payload.Type == "ConfigChangeNotifyRequest" {
+			// 没有监听者时丢弃，重新监听时服务端会比较 MD5
+			g.dispatch(listenerKey(request.Tenant, request.Group, request.DataId))
+		}
+
+		_ = g.sendStream(stream, strings.TrimSuffix(payload.Type, "Request")+"Response", grpcResponse{
+			ResultCode: http.StatusOK,
+			RequestId:  request.RequestId,
+		})
+	}
+}
+
+func (g *grpcConn) sendStream(stream grpc.ClientStream, typ string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	g.sendMu.Lock()
+	defer g.sendMu.Unlock()
+	return stream.SendMsg(&grpcPayload{Type: typ, Body: data})
+}
+
+// request 发送一个配置请求，tenant 和 group 参与 AK/SK 签名。
+// 连接未注册完成时稍后重试，服务端一直不认可该连接（如服务端重启后）时重新连接并注册一次，
+// 认证失败时由 Authenticator 刷新凭据后重试一次
+func (g *grpcConn) request(ctx context.Context, typ, tenant, group string, body, result any) error {
+	if timeout := g.client.RequestTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	for attempt, registerRetries, reconnected := 0, 0, false; ; {
+		conn, err := g.ready(ctx)
+		if err != nil {
+			return err
+		}
+
+		headers, req, err := g.authHeaders(ctx, tenant, group)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		err = g.invokeWithHeaders(ctx, conn, typ, headers, body, result)
+		var respErr *grpcResponseError
+		switch {
+		case errors.As(err, &respErr) && respErr.ErrorCode == grpcUnregisteredCode && registerRetries < grpcRegisterRetries:
+			registerRetries++
+			select {
+			case <-time.After(grpcRegisterInterval):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			continue
+		case errors.As(err, &respErr) && respErr.ErrorCode == grpcUnregisteredCode && !reconnected:
+			g.reset(conn)
+			registerRetries, reconnected = 0, true
+			continue
+		case err != nil && !reconnected && !g.current(conn):
+			// 请求期间双向流断开，连接已被关闭
+			reconnected = true
+			continue
+		case errors.As(err, &respErr) && respErr.authFailed() && attempt == 0 && g.client.authenticator().Invalidate(req):
+			attempt++
+			continue
+		}
+		return err
+	}
+}
+
+// authHeaders 使用 HTTP 的 Authenticator 生成认证信息并转换为 gRPC 元数据中的请求头
+func (g *grpcConn) authHeaders(ctx context.Context, tenant, group string) (map[string]string, *http.Request, error) {
+	form := url.Values{}
+	if tenant != "" {
+		form.Set("tenant", tenant)
+	}
+	if group != "" {
+		form.Set("group", group)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://nacos-grpc/", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if err := g.client.authenticator().Authenticate(req); err != nil {
+		return nil, nil, err
+	}
+	req.Header.Del("Content-Type")
+
+	headers := make(map[string]string)
+	for key := range req.Header {
+		headers[key] = req.Header.Get(key)
+	}
+	// gRPC 接口从 accessToken 请求头读取 token
+	if token := strings.TrimPrefix(headers[authHeader], "Bearer "); token != "" {
+		delete(headers, authHeader)
+		headers["accessToken"] = token
+	}
+	return headers, req, nil
+}
+
+func (g *grpcConn) invoke(ctx context.Context, conn *grpc.ClientConn, typ string, body, result any) error {
+	return g.invokeWithHeaders(ctx, conn, typ, map[string]string{}, body, result)
+}
+
+func (g *grpcConn) invokeWithHeaders(ctx context.Context, conn *grpc.ClientConn, typ string, headers map[string]string, body, result any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	// 请求 ID 写入 JSON 的 requestId 字段
+	request := map[string]any{}
+	if err := json.Unmarshal(data, &request); err != nil {
+		return err
+	}
+	request["requestId"] = strconv.FormatInt(g.requestId.Add(1), 10)
+	request["headers"] = headers
+	if data, err = json.Marshal(request); err != nil {
+		return err
+	}
+
+	out := &grpcPayload{}
+	if err := conn.Invoke(ctx, grpcRequestMethod, &grpcPayload{Type: typ, Headers: headers, Body: data}, out); err != nil {
+		return err
+	}
+
+	resp := grpcResponse{}
+	if err := json.Unmarshal(out.Body, &resp); err != nil {
+		return fmt.Errorf("invalid %s response: %w", typ, err)
+	}
+	if out.Type == "ErrorResponse" || resp.ResultCode != http.StatusOK {
+		return &grpcResponseError{Type: typ, grpcResponse: resp}
+	}
+
+	return json.Unmarshal(out.Body, result)
+}
+
+// Close 关闭连接
+func (g *grpcConn) Close() error {
+	g.connMu.Lock()
+	defer g.connMu.Unlock()
+	return g.closeLocked()
+}
+
+// grpcResponseError 服务端返回的错误响应
+type grpcResponseError struct {
+	Type string
+	grpcResponse
+}
+
+func (e *grpcResponseError) Error() string {
+	return fmt.Sprintf("%s failed, error code:%d\n%s", e.Type, e.ErrorCode, e.Message)
+}
+
+func (e *grpcResponseError) authFailed() bool {
+	return e.ErrorCode == http.StatusUnauthorized || e.ErrorCode == http.StatusForbidden
+}
@@ -0,0 +1,398 @@
+package nacos
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Talbot3/nacos-cli/pkg/util"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// fakeGrpcServer 进程内的 Nacos gRPC 服务端，支持配置查询、发布、删除和批量监听
+type fakeGrpcServer struct {
+	token string // 非空时要求 accessToken
+
+	mu         sync.Mutex
+	registered bool
+	setups     int           // 收到的 ConnectionSetupRequest 数量
+	drop       chan struct{} // 关闭时断开所有双向流
+	configs    map[ConfigKey]string
+	streams    []grpc.ServerStream
+	published  map[string]string // 最近一次发布的 additionMap
+}
+
+func startFakeGrpcServer(t *testing.T, token string) (*fakeGrpcServer, string) {
+	fake := &fakeGrpcServer{token: token, drop: make(chan struct{}), configs: map[ConfigKey]string{}}
+
+	server := grpc.NewServer(grpc.ForceServerCodec(payloadCodec{}))
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "Request",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "request",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				in := &grpcPayload{}
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				return srv.(*fakeGrpcServer).handle(in), nil
+			},
+		}},
+	}, fake)
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "BiRequestStream",
+		HandlerType: (*interface{})(nil),
+		Streams: []grpc.StreamDesc{{
+			StreamName:    "requestBiStream",
+			ServerStreams: true,
+			ClientStreams: true,
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				return srv.(*fakeGrpcServer).biStream(stream)
+			},
+		}},
+	}, fake)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	return fake, lis.Addr().String()
+}
+
+func (f *fakeGrpcServer) biStream(stream grpc.ServerStream) error {
+	f.mu.Lock()
+	drop := f.drop
+	f.mu.Unlock()
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			in := &grpcPayload{}
+			if err := stream.RecvMsg(in); err != nil {
+				return
+			}
+			if in.Type == "ConnectionSetupRequest" {
+				// 与真实服务端一样异步完成注册
+				time.AfterFunc(50*time.Millisecond, func() {
+					f.mu.Lock()
+					defer f.mu.Unlock()
+					f.registered = true
+					f.setups++
+					f.streams = append(f.streams, stream)
+				})
+			}
+		}
+	}()
+
+	select {
+	case <-closed:
+	case <-drop:
+	}
+	return nil
+}
+
+// restart 模拟服务端重启：断开所有双向流并丢弃连接注册，配置保留
+func (f *fakeGrpcServer) restart() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.registered = false
+	f.streams = nil
+	close(f.drop)
+	f.drop = make(chan struct{})
+}
+
+func (f *fakeGrpcServer) setupCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.setups
+}
+
+func (f *fakeGrpcServer) handle(in *grpcPayload) *grpcPayload {
+	request := struct {
+		grpcConfigRequest
+		Content              string              `json:"content"`
+		AdditionMap          map[string]string   `json:"additionMap"`
+		ConfigListenContexts []grpcConfigContext `json:"configListenContexts"`
+	}{}
+	if err := json.Unmarshal(in.Body, &request); err != nil {
+		return f.reply(in, map[string]interface{}{"resultCode": 500, "errorCode": 500, "message": err.Error()})
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case in.Type == "ServerCheckRequest":
+		return f.reply(in, map[string]interface{}{"resultCode": 200, "connectionId": "fake"})
+	case !f.registered:
+		return f.reply(in, map[string]interface{}{"resultCode": 500, "errorCode": grpcUnregisteredCode, "message": "Connection is unregistered."})
+	case f.token != "" && in.Headers["accessToken"] != f.token:
+		return f.reply(in, map[string]interface{}{"resultCode": 500, "errorCode": 403, "message": "token invalid"})
+	}
+
+	key := ConfigKey{Namespace: request.Tenant, Group: request.Group, DataId: request.DataId}
+	switch in.Type {
+	case "ConfigQueryRequest":
+		content, ok := f.configs[key]
+		if !ok {
+			return f.reply(in, map[string]interface{}{"resultCode": 500, "errorCode": grpcConfigNotFound, "message": "config data not exist"})
+		}
+		return f.reply(in, map[string]interface{}{"resultCode": 200, "content": content, "md5": util.Md5ToString(content), "contentType": "yaml"})
+	case "ConfigPublishRequest":
+		f.configs[key] = request.Content
+		f.published = request.AdditionMap
+		f.push(key)
+	case "ConfigRemoveRequest":
+		delete(f.configs, key)
+		f.push(key)
+	case "ConfigBatchListenRequest":
+		var changed []grpcConfigContext
+		for _, listen := range request.ConfigListenContexts {
+			content, ok := f.configs[ConfigKey{Namespace: listen.Tenant, Group: listen.Group, DataId: listen.DataId}]
+			md5 := ""
+			if ok {
+				md5 = util.Md5ToString(content)
+			}
+			if md5 != listen.Md5 {
+				changed = append(changed, listen)
+			}
+		}
+		return f.reply(in, map[string]interface{}{"resultCode": 200, "changedConfigs": changed})
+	}
+	return f.reply(in, map[string]interface{}{"resultCode": 200})
+}
+
+// push 通过所有连接的双向流通知配置变更，调用方持有锁
+func (f *fakeGrpcServer) push(key ConfigKey) {
+	body, _ := json.Marshal(map[string]string{"requestId": "push", "dataId": key.DataId, "group": key.Group, "tenant": key.Namespace})
+	for _, stream := range f.streams {
+		_ = stream.SendMsg(&grpcPayload{Type: "ConfigChangeNotifyRequest", Body: body})
+	}
+}
+
+func (f *fakeGrpcServer) reply(in *grpcPayload, body map[string]interface{}) *grpcPayload {
+	data, _ := json.Marshal(body)
+	return &grpcPayload{Type: strings.TrimSuffix(in.Type, "Request") + "Response", Body: data}
+}
+
+// newGrpcTestClient 创建使用 gRPC 传输的客户端，HTTP 接口（元数据查询）返回 404
+func newGrpcTestClient(t *testing.T, target string) *Client {
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL, ApiVersionV1, "", "")
+	client.Config.Transport = TransportGRPC
+	client.api(context.Background()).(grpcAPI).conn.target = target
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestGrpcPayloadCodec(t *testing.T) {
+	payload := &grpcPayload{
+		Type:     "ConfigQueryRequest",
+		Headers:  map[string]string{"accessToken": "tok", "app": "nacosctl"},
+		ClientIp: "10.0.0.1",
+		Body:     []byte(`{"dataId":"app.yaml"}`),
+	}
+
+	data, err := payloadCodec{}.Marshal(payload)
+	require.NoError(t, err)
+
+	decoded := &grpcPayload{}
+	require.NoError(t, payloadCodec{}.Unmarshal(data, decoded))
+	assert.Equal(t, payload, decoded)
+}
+
+func TestGrpcTarget(t *testing.T) {
+	target, secure, err := grpcTarget("http://nacos:8848/nacos")
+	require.NoError(t, err)
+	assert.Equal(t, "nacos:9848", target)
+	assert.False(t, secure)
+
+	target, secure, err = grpcTarget("https://nacos.example.com/nacos")
+	require.NoError(t, err)
+	assert.Equal(t, "nacos.example.com:1443", target)
+	assert.True(t, secure)
+}
+
+func TestGrpcTransport(t *testing.T) {
+	fake, target := startFakeGrpcServer(t, "tok")
+	client := newGrpcTestClient(t, target)
+	client.Authenticator = &TokenAuthenticator{Token: "tok"}
+
+	op := &NacosOperation{Namespace: "dev", Group: "G"}
+	get := ConfigGetOperation{NacosOperation: op, DataId: "app.yaml"}
+
+	_, err := client.Get(get)
+	assert.ErrorIs(t, err, ErrConfigNotExist)
+
+	require.NoError(t, client.Edit(ConfigEditOperation{NacosOperation: op, DataId: "app.yaml", Content: "a: 1", Type: "yaml"}))
+	assert.Equal(t, "yaml", fake.published["type"])
+
+	detail, err := client.Get(get)
+	require.NoError(t, err)
+	assert.Equal(t, "a: 1", detail.Content)
+	assert.Equal(t, util.Md5ToString("a: 1"), detail.Md5)
+
+	require.NoError(t, client.DeleteConfig(ConfigDeleteOperation{NacosOperation: op, DataId: "app.yaml"}))
+	_, err = client.Get(get)
+	assert.ErrorIs(t, err, ErrConfigNotExist)
+}
+
+func TestGrpcTransportAuthFailure(t *testing.T) {
+	_, target := startFakeGrpcServer(t, "tok")
+	client := newGrpcTestClient(t, target)
+	client.Authenticator = &TokenAuthenticator{Token: "wrong"}
+
+	_, err := client.Get(ConfigGetOperation{NacosOperation: &NacosOperation{Group: "G"}, DataId: "app.yaml"})
+	assert.ErrorContains(t, err, "token invalid")
+}
+
+func TestGrpcWatch(t *testing.T) {
+	_, target := startFakeGrpcServer(t, "")
+	client := newGrpcTestClient(t, target)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	key := ConfigKey{Namespace: "dev", Group: "G", DataId: "app.yaml"}
+	events, err := client.Watch(ctx, key)
+	require.NoError(t, err)
+
+	event := <-events
+	require.NoError(t, event.Err)
+	assert.Nil(t, event.Config)
+
+	publisher := newGrpcTestClient(t, target)
+	require.NoError(t, publisher.Edit(ConfigEditOperation{
+		NacosOperation: &NacosOperation{Namespace: "dev", Group: "G"},
+		DataId:         "app.yaml",
+		Content:        "a: 2",
+	}))
+
+	event = <-events
+	require.NoError(t, event.Err)
+	require.NotNil(t, event.Config)
+	assert.Equal(t, "a: 2", event.Config.Content)
+}
+
+func TestGrpcReconnect(t *testing.T) {
+	fake, target := startFakeGrpcServer(t, "")
+	client := newGrpcTestClient(t, target)
+
+	op := &NacosOperation{Namespace: "dev", Group: "G"}
+	require.NoError(t, client.Edit(ConfigEditOperation{NacosOperation: op, DataId: "app.yaml", Content: "a: 1"}))
+	assert.Equal(t, 1, fake.setupCount())
+
+	// 双向流断开后重新连接并发送 ConnectionSetup
+	fake.restart()
+	require.Eventually(t, func() bool { return client.api(context.Background()).(grpcAPI).conn.lostCh() == nil }, 5*time.Second, 10*time.Millisecond)
+
+	detail, err := client.Get(ConfigGetOperation{NacosOperation: op, DataId: "app.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, "a: 1", detail.Content)
+	assert.Equal(t, 2, fake.setupCount())
+
+	// 服务端不认可连接但双向流未断开时，重试用尽后重新连接
+	fake.mu.Lock()
+	fake.registered = false
+	fake.mu.Unlock()
+
+	detail, err = client.Get(ConfigGetOperation{NacosOperation: op, DataId: "app.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, "a: 1", detail.Content)
+	assert.Equal(t, 3, fake.setupCount())
+}
+
+func TestGrpcWatchReconnect(t *testing.T) {
+	fake, target := startFakeGrpcServer(t, "")
+	client := newGrpcTestClient(t, target)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	key := ConfigKey{Namespace: "dev", Group: "G", DataId: "app.yaml"}
+	events, err := client.Watch(ctx, key)
+	require.NoError(t, err)
+
+	event := <-events
+	require.NoError(t, event.Err)
+	assert.Nil(t, event.Config)
+
+	// 重启后监听在新连接上重新注册，之后的变更仍能收到
+	fake.restart()
+
+	publisher := newGrpcTestClient(t, target)
+	require.NoError(t, publisher.Edit(ConfigEditOperation{
+		NacosOperation: &NacosOperation{Namespace: "dev", Group: "G"},
+		DataId:         "app.yaml",
+		Content:        "a: 2",
+	}))
+
+	select {
+	case event = <-events:
+	case <-ctx.Done():
+		t.Fatal("重启后没有收到变更")
+	}
+	require.NoError(t, event.Err)
+	require.NotNil(t, event.Config)
+	assert.Equal(t, "a: 2", event.Config.Content)
+}
+
+func TestGrpcConcurrentWatches(t *testing.T) {
+	_, target := startFakeGrpcServer(t, "")
+	client := newGrpcTestClient(t, target)
+	conn := client.api(context.Background()).(grpcAPI).conn
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// 两个 Watch 共用同一个 gRPC 连接，每个只收到自己监听的配置的变更
+	keys := []ConfigKey{
+		{Namespace: "dev", Group: "G", DataId: "a.yaml"},
+		{Namespace: "dev", Group: "G", DataId: "b.yaml"},
+	}
+	var watches []<-chan ConfigChangeEvent
+	for _, key := range keys {
+		events, err := client.Watch(ctx, key)
+		require.NoError(t, err)
+		event := <-events
+		require.NoError(t, event.Err)
+		watches = append(watches, events)
+	}
+	require.Eventually(t, func() bool {
+		conn.listenersMu.Lock()
+		defer conn.listenersMu.Unlock()
+		return len(conn.listeners) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	publisher := newGrpcTestClient(t, target)
+	for i := len(keys) - 1; i >= 0; i-- {
+		require.NoError(t, publisher.Edit(ConfigEditOperation{
+			NacosOperation: &NacosOperation{Namespace: "dev", Group: "G"},
+			DataId:         keys[i].DataId,
+			Content:        "v: " + keys[i].DataId,
+		}))
+
+		select {
+		case event := <-watches[i]:
+			require.NoError(t, event.Err)
+			assert.Equal(t, keys[i], event.Key)
+			require.NotNil(t, event.Config)
+			assert.Equal(t, "v: "+keys[i].DataId, event.Config.Content)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: change not delivered", keys[i])
+		}
+	}
+}
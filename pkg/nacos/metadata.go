@@ -0,0 +1,92 @@
+package nacos
+
+import (
+	"context"
+	"github.com/Talbot3/nacos-cli/pkg/util"
+	"strings"
+)
+
+// merge 用服务器上的当前值补全未指定的元数据，返回所有字段都非 nil 的副本
+func (m ConfigMetadata) merge(current *NacosConfigDetail) ConfigMetadata {
+	if current == nil {
+		current = &NacosConfigDetail{}
+	}
+
+	keep := func(value *string, remote string) *string {
+		if value != nil {
+			return value
+		}
+		return &remote
+	}
+
+	merged := ConfigMetadata{
+		Desc:    keep(m.Desc, current.Desc),
+		Tags:    m.Tags,
+		AppName: keep(m.AppName, current.AppName),
+		Use:     keep(m.Use, current.Use),
+		Effect:  keep(m.Effect, current.Effect),
+		Schema:  keep(m.Schema, current.Schema),
+	}
+	if merged.Tags == nil {
+		merged.Tags = ParseTags(current.ConfigTags)
+	}
+	return merged
+}
+
+// Metadata 返回配置的全部元数据，用于将元数据原样发布到其他配置
+func (d *NacosConfigDetail) Metadata() ConfigMetadata {
+	desc, appName, use, effect, schema := d.Desc, d.AppName, d.Use, d.Effect, d.Schema
+	return ConfigMetadata{
+		Desc:    &desc,
+		Tags:    ParseTags(d.ConfigTags),
+		AppName: &appName,
+		Use:     &use,
+		Effect:  &effect,
+		Schema:  &schema,
+	}
+}
+
+// ParseTags 解析逗号分隔的 config_tags
+func ParseTags(s string) []string {
+	tags := []string{}
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// UpdateMetadata 只更新配置的元数据，内容和类型保持不变（加密内容原样保留）
+func (c *Client) UpdateMetadata(operation ConfigMetadataOperation) error {
+	return c.UpdateMetadataContext(context.Background(), operation)
+}
+
+// UpdateMetadataContext 只更新配置的元数据，ctx 取消或超时时中断请求。
+// 以读取时的 MD5 作为 casMd5 重新发布内容，期间内容被他人修改时返回 ErrConflict 而不是覆盖
+func (c *Client) UpdateMetadataContext(ctx context.Context, operation ConfigMetadataOperation) error {
+	current, err := c.detail(ctx, ConfigGetOperation{
+		NacosOperation: operation.NacosOperation,
+		DataId:         operation.DataId,
+	})
+	if err != nil {
+		return err
+	}
+
+	// 内容未解密，MD5 与服务端保存的内容一致
+	casMd5 := current.Md5
+	if casMd5 == "" {
+		casMd5 = util.Md5ToString(current.Content)
+	}
+
+	return c.EditContext(ctx, ConfigEditOperation{
+		NacosOperation: operation.NacosOperation,
+		ConfigMetadata: operation.ConfigMetadata,
+		DataId:         operation.DataId,
+		Content:        current.Content,
+		Type:           current.Type,
+		CasMd5:         casMd5,
+
+		EncryptedDataKey: current.EncryptedDataKey,
+	})
+}
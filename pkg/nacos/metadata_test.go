@@ -0,0 +1,85 @@
+package nacos
+
+import (
+	"github.com/Talbot3/nacos-cli/pkg/util"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditKeepsRemoteMetadata(t *testing.T) {
+	var published url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(t, "all", r.URL.Query().Get("show"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"dataId":"app.yaml","group":"DEFAULT_GROUP","content":"a: 1","type":"yaml",` +
+				`"desc":"orders","appName":"order-service","configTags":"team=payments,tier=prod","use":"u","effect":"e","schema":"s"}`))
+		case http.MethodPost:
+			_ = r.ParseForm()
+			published = r.PostForm
+			_, _ = w.Write([]byte("true"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "v1", "", "")
+	desc := "new desc"
+	require.NoError(t, client.Edit(ConfigEditOperation{
+		NacosOperation: &NacosOperation{Namespace: "public", Group: "DEFAULT_GROUP"},
+		ConfigMetadata: ConfigMetadata{Desc: &desc},
+		DataId:         "app.yaml",
+		Content:        "a: 2",
+	}))
+
+	assert.Equal(t, "a: 2", published.Get("content"))
+	assert.Equal(t, "yaml", published.Get("type"))
+	assert.Equal(t, "new desc", published.Get("desc"))
+	assert.Equal(t, "order-service", published.Get("appName"))
+	assert.Equal(t, "team=payments,tier=prod", published.Get("config_tags"))
+	assert.Equal(t, "u", published.Get("use"))
+	assert.Equal(t, "e", published.Get("effect"))
+	assert.Equal(t, "s", published.Get("schema"))
+}
+
+func TestParseTags(t *testing.T) {
+	assert.Equal(t, []string{"a=1", "b"}, ParseTags(" a=1, ,b "))
+	assert.Equal(t, []string{}, ParseTags(""))
+}
+
+func TestUpdateMetadataConflict(t *testing.T) {
+	var casMd5 string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"dataId":"app.yaml","group":"DEFAULT_GROUP","content":"a: 1","type":"yaml"}`))
+		case http.MethodPost:
+			// 读取之后内容已被他人修改为 a: 2
+			_ = r.ParseForm()
+			casMd5 = r.PostForm.Get("casMd5")
+			if casMd5 != util.Md5ToString("a: 2") {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte("Cas publish fail, server md5 may have changed."))
+				return
+			}
+			_, _ = w.Write([]byte("true"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "v1", "", "")
+	desc := "orders"
+	err := client.UpdateMetadata(ConfigMetadataOperation{
+		NacosOperation: &NacosOperation{Namespace: "public", Group: "DEFAULT_GROUP"},
+		ConfigMetadata: ConfigMetadata{Desc: &desc},
+		DataId:         "app.yaml",
+	})
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Equal(t, util.Md5ToString("a: 1"), casMd5)
+}
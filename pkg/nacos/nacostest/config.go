@@ -0,0 +1,548 @@
+package nacostest
+
+import (
+	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/util"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageSize = 100
+	wordSeparator   = "\x02"
+	lineSeparator   = "\x01"
+)
+
+// Config 服务端保存的配置
+type Config struct {
+	Namespace string // 命名空间 ID，public 命名空间为空字符串
+	Group     string
+	DataId    string
+	Content   string
+	Md5       string // 内容 MD5，由服务端计算
+	Type      string // 为空时为 text
+	AppName   string
+	Desc      string
+	Tags      string // 逗号分隔的标签
+	Use       string
+	Effect    string
+	Schema    string
+
+	EncryptedDataKey string // 客户端加密配置的数据密钥
+
+	id         int64
+	createTime time.Time
+	modifyTime time.Time
+}
+
+// History 配置的历史版本。与 Nacos 一致，新增（I）记录新增的内容，更新（U）和删除（D）记录变更前的内容
+type History struct {
+	Id     int64
+	OpType string // I、U 或 D
+	Config
+	Time time.Time
+}
+
+type configKey struct {
+	namespace string
+	group     string
+	dataId    string
+}
+
+func keyOf(namespace, group, dataId string) configKey {
+	return configKey{namespace: tenantOf(namespace), group: group, dataId: dataId}
+}
+
+// tenantOf public 命名空间统一保存为空字符串
+func tenantOf(namespace string) string {
+	if namespace == "public" {
+		return ""
+	}
+	return namespace
+}
+
+// SetConfig 新增或更新配置，与通过接口发布一样记录历史并通知监听者
+func (s *Server) SetConfig(config Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.publishLocked(config)
+}
+
+// Config 查询配置，namespace 为 "public" 或空字符串时为 public 命名空间
+func (s *Server) Config(namespace, group, dataId string) (Config, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	config, ok := s.configs[keyOf(namespace, group, dataId)]
+	if !ok {
+		return Config{}, false
+	}
+	return *config, true
+}
+
+// Configs 按创建顺序返回所有配置
+func (s *Server) Configs() []Config {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	configs := make([]Config, 0, len(s.configs))
+	for _, config := range s.sortedConfigsLocked() {
+		configs = append(configs, *config)
+	}
+	return configs
+}
+
+// History 按时间倒序返回配置的历史版本
+func (s *Server) History(namespace, group, dataId string) []History {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.historyLocked(keyOf(namespace, group, dataId))
+}
+
+func (s *Server) publishLocked(config Config) {
+	config.Namespace = tenantOf(config.Namespace)
+	config.Md5 = util.Md5ToString(config.Content)
+	if config.Type == "" {
+		config.Type = "text"
+	}
+
+	now := time.Now()
+	config.modifyTime = now
+	key := keyOf(config.Namespace, config.Group, config.DataId)
+	if current, ok := s.configs[key]; ok {
+		s.recordLocked("U", *current, now)
+		config.id = current.id
+		config.createTime = current.createTime
+	} else {
+		s.nextId++
+		config.id = s.nextId
+		config.createTime = now
+		s.recordLocked("I", config, now)
+	}
+
+	s.configs[key] = &config
+	s.notifyLocked()
+}
+
+func (s *Server) deleteLocked(key configKey) {
+	current, ok := s.configs[key]
+	if !ok {
+		return
+	}
+	s.recordLocked("D", *current, time.Now())
+	delete(s.configs, key)
+	s.notifyLocked()
+}
+
+func (s *Server) recordLocked(opType string, config Config, now time.Time) {
+	s.history = append(s.history, History{
+		Id:     int64(len(s.history) + 1),
+		OpType: opType,
+		Config: config,
+		Time:   now,
+	})
+}
+
+func (s *Server) historyLocked(key configKey) []History {
+	var history []History
+	for i := len(s.history) - 1; i >= 0; i-- {
+		item := s.history[i]
+		if keyOf(item.Namespace, item.Group, item.DataId) == key {
+			history = append(history, item)
+		}
+	}
+	return history
+}
+
+func (s *Server) sortedConfigsLocked() []*Config {
+	configs := make([]*Config, 0, len(s.configs))
+	for _, config := range s.configs {
+		configs = append(configs, config)
+	}
+	sort.Slice(configs, func(i, j int) bool { return configs[i].id < configs[j].id })
+	return configs
+}
+
+// handleConfigs 处理 /v1/cs/configs 的查询、列表、发布、删除、导出和导入
+func (s *Server) handleConfigs(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeText(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.Form.Get("exportV2") == "true":
+		s.exportConfigs(w, r)
+	case r.Method == http.MethodPost && r.Form.Get("import") == "true":
+		s.importConfigs(w, r)
+	case r.Method == http.MethodGet && r.Form.Get("pageNo") != "":
+		s.listConfigs(w, r)
+	case r.Method == http.MethodGet:
+		s.getConfig(w, r)
+	case r.Method == http.MethodPost:
+		s.publishConfig(w, r)
+	case r.Method == http.MethodDelete:
+		s.deleteConfig(w, r)
+	default:
+		writeText(w, http.StatusMethodNotAllowed, "Request method '"+r.Method+"' not supported")
+	}
+}
+
+func (s *Server) getConfig(w http.ResponseWriter, r *http.Request) {
+	if missing := missingParam(r.Form, "dataId", "group"); missing != "" {
+		writeText(w, http.StatusBadRequest, missing)
+		return
+	}
+
+	config, ok := s.Config(r.Form.Get("tenant"), r.Form.Get("group"), r.Form.Get("dataId"))
+	if !ok {
+		writeText(w, http.StatusNotFound, "config data not exist")
+		return
+	}
+
+	if r.Form.Get("show") == "all" {
+		writeJSON(w, configDetail(config))
+		return
+	}
+
+	w.Header().Set("Content-MD5", config.Md5)
+	w.Header().Set("Config-Type", config.Type)
+	if config.EncryptedDataKey != "" {
+		w.Header().Set("encryptedDataKey", config.EncryptedDataKey)
+	}
+	writeText(w, http.StatusOK, config.Content)
+}
+
+// listConfigs 分页列出配置。search=blur 时 dataId、group 中的 * 匹配任意字符，否则为精确匹配；参数为空时不过滤
+func (s *Server) listConfigs(w http.ResponseWriter, r *http.Request) {
+	blur := r.Form.Get("search") == "blur"
+	tenant := tenantOf(r.Form.Get("tenant"))
+	dataId := r.Form.Get("dataId")
+	group := r.Form.Get("group")
+	appName := r.Form.Get("appName")
+	tags := r.Form.Get("config_tags")
+
+	s.mu.Lock()
+	var items []map[string]any
+	for _, config := range s.sortedConfigsLocked() {
+		if config.Namespace != tenant ||
+			!matchParam(blur, dataId, config.DataId) ||
+			!matchParam(blur, group, config.Group) ||
+			appName != "" && config.AppName != appName ||
+			tags != "" && !hasTags(config.Tags, tags) {
+			continue
+		}
+		items = append(items, map[string]any{
+			"id":               strconv.FormatInt(config.id, 10),
+			"dataId":           config.DataId,
+			"group":            config.Group,
+			"content":          config.Content,
+			"md5":              config.Md5,
+			"tenant":           config.Namespace,
+			"appName":          config.AppName,
+			"type":             config.Type,
+			"encryptedDataKey": config.EncryptedDataKey,
+		})
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, page(r.Form, items))
+}
+
+func (s *Server) publishConfig(w http.ResponseWriter, r *http.Request) {
+	if missing := missingParam(r.Form, "dataId", "group", "content"); missing != "" {
+		writeText(w, http.StatusBadRequest, missing)
+		return
+	}
+	if r.Header.Get("betaIps") != "" {
+		writeText(w, http.StatusNotImplemented, "beta publish is not supported by nacostest")
+		return
+	}
+
+	config := Config{
+		Namespace: r.Form.Get("tenant"),
+		Group:     r.Form.Get("group"),
+		DataId:    r.Form.Get("dataId"),
+		Content:   r.Form.Get("content"),
+		Type:      r.Form.Get("type"),
+		AppName:   r.Form.Get("appName"),
+		Desc:      r.Form.Get("desc"),
+		Tags:      r.Form.Get("config_tags"),
+		Use:       r.Form.Get("use"),
+		Effect:    r.Form.Get("effect"),
+		Schema:    r.Form.Get("schema"),
+
+		EncryptedDataKey: r.Form.Get("encryptedDataKey"),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 与 Nacos 一致：配置存在且 MD5 与 casMd5 不同时发布失败，配置不存在时直接新增
+	if casMd5 := r.Form.Get("casMd5"); casMd5 != "" {
+		current, ok := s.configs[keyOf(config.Namespace, config.Group, config.DataId)]
+		if ok && current.Md5 != casMd5 {
+			writeText(w, http.StatusInternalServerError, "Cas publish fail, server md5 may have changed.")
+			return
+		}
+	}
+
+	s.publishLocked(config)
+	writeText(w, http.StatusOK, "true")
+}
+
+func (s *Server) deleteConfig(w http.ResponseWriter, r *http.Request) {
+	if missing := missingParam(r.Form, "dataId", "group"); missing != "" {
+		writeText(w, http.StatusBadRequest, missing)
+		return
+	}
+
+	s.mu.Lock()
+	s.deleteLocked(keyOf(r.Form.Get("tenant"), r.Form.Get("group"), r.Form.Get("dataId")))
+	s.mu.Unlock()
+
+	writeText(w, http.StatusOK, "true")
+}
+
+// handleListener 处理 /v1/cs/configs/listener 长轮询。
+// 监听的配置中有 MD5 与服务端不同的立即返回，否则挂起到配置变更或超时；没有 Long-Pulling-Timeout 请求头时立即返回
+func (s *Server) handleListener(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeText(w, http.StatusMethodNotAllowed, "Request method '"+r.Method+"' not supported")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeText(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	listening, ok := r.Form["Listening-Configs"]
+	if !ok {
+		writeText(w, http.StatusBadRequest, "invalid probeModify")
+		return
+	}
+	probes := parseListening(strings.Join(listening, ""))
+
+	// 与 Nacos 一致，提前 500ms 返回以免客户端超时
+	timeout := time.Duration(0)
+	if ms, err := strconv.Atoi(r.Header.Get("Long-Pulling-Timeout")); err == nil {
+		timeout = time.Duration(ms)*time.Millisecond - 500*time.Millisecond
+		if timeout > s.longPollingTimeout {
+			timeout = s.longPollingTimeout
+		}
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		changed := s.changedLocked(probes)
+		wait := s.changed
+		s.mu.Unlock()
+
+		if len(changed) > 0 || timeout <= 0 {
+			writeText(w, http.StatusOK, url.QueryEscape(strings.Join(changed, "")))
+			return
+		}
+
+		select {
+		case <-wait:
+		case <-timer.C:
+			writeText(w, http.StatusOK, "")
+			return
+		case <-s.closed:
+			writeText(w, http.StatusOK, "")
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// probe 监听请求中的一个配置及客户端持有内容的 MD5
+type probe struct {
+	key configKey
+	md5 string
+}
+
+// parseListening 解析 Listening-Configs：dataId^2group^2md5[^2tenant]^1 列表
+func parseListening(listening string) []probe {
+	var probes []probe
+	for _, line := range strings.Split(listening, lineSeparator) {
+		fields := strings.Split(line, wordSeparator)
+		if len(fields) < 3 {
+			continue
+		}
+		tenant := ""
+		if len(fields) > 3 {
+			tenant = fields[3]
+		}
+		probes = append(probes, probe{key: keyOf(tenant, fields[1], fields[0]), md5: fields[2]})
+	}
+	return probes
+}
+
+// changedLocked 返回 MD5 发生变化的配置，格式为 dataId^2group[^2tenant]^1
+func (s *Server) changedLocked(probes []probe) []string {
+	var changed []string
+	for _, p := range probes {
+		md5 := ""
+		if config, ok := s.configs[p.key]; ok {
+			md5 = config.Md5
+		}
+		if md5 == p.md5 {
+			continue
+		}
+
+		line := p.key.dataId + wordSeparator + p.key.group
+		if p.key.namespace != "" {
+			line += wordSeparator + p.key.namespace
+		}
+		changed = append(changed, line+lineSeparator)
+	}
+	return changed
+}
+
+// handleHistory 处理 /v1/cs/history：带 nid 时查询单个历史版本，否则分页列出配置的历史版本
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeText(w, http.StatusMethodNotAllowed, "Request method '"+r.Method+"' not supported")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeText(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if missing := missingParam(r.Form, "dataId", "group"); missing != "" {
+		writeText(w, http.StatusBadRequest, missing)
+		return
+	}
+
+	history := s.History(r.Form.Get("tenant"), r.Form.Get("group"), r.Form.Get("dataId"))
+
+	if nid := r.Form.Get("nid"); nid != "" {
+		for _, item := range history {
+			if strconv.FormatInt(item.Id, 10) == nid {
+				writeJSON(w, historyDetail(item))
+				return
+			}
+		}
+		writeText(w, http.StatusNotFound, "history not found")
+		return
+	}
+
+	items := make([]map[string]any, 0, len(history))
+	for _, item := range history {
+		items = append(items, historyDetail(item))
+	}
+	writeJSON(w, page(r.Form, items))
+}
+
+func configDetail(config Config) map[string]any {
+	return map[string]any{
+		"id":               strconv.FormatInt(config.id, 10),
+		"dataId":           config.DataId,
+		"group":            config.Group,
+		"content":          config.Content,
+		"md5":              config.Md5,
+		"encryptedDataKey": config.EncryptedDataKey,
+		"tenant":           config.Namespace,
+		"appName":          config.AppName,
+		"type":             config.Type,
+		"createTime":       config.createTime.UnixMilli(),
+		"modifyTime":       config.modifyTime.UnixMilli(),
+		"createUser":       nil,
+		"createIp":         "127.0.0.1",
+		"desc":             config.Desc,
+		"use":              config.Use,
+		"effect":           config.Effect,
+		"schema":           config.Schema,
+		"configTags":       config.Tags,
+	}
+}
+
+func historyDetail(item History) map[string]any {
+	return map[string]any{
+		"id":               strconv.FormatInt(item.Id, 10),
+		"lastId":           -1,
+		"dataId":           item.DataId,
+		"group":            item.Group,
+		"tenant":           item.Namespace,
+		"appName":          item.AppName,
+		"md5":              item.Md5,
+		"content":          item.Content,
+		"srcIp":            "127.0.0.1",
+		"srcUser":          nil,
+		"opType":           item.OpType,
+		"createdTime":      item.Time.UnixMilli(),
+		"lastModifiedTime": item.Time.UnixMilli(),
+	}
+}
+
+// page 按 pageNo、pageSize 参数构造 Nacos 分页响应
+func page(params url.Values, items []map[string]any) map[string]any {
+	pageNo, err := strconv.Atoi(params.Get("pageNo"))
+	if err != nil || pageNo < 1 {
+		pageNo = 1
+	}
+	pageSize, err := strconv.Atoi(params.Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+
+	start := (pageNo - 1) * pageSize
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + pageSize
+	if end > len(items) {
+		end = len(items)
+	}
+
+	return map[string]any{
+		"totalCount":     len(items),
+		"pageNumber":     pageNo,
+		"pagesAvailable": (len(items) + pageSize - 1) / pageSize,
+		"pageItems":      append([]map[string]any{}, items[start:end]...),
+	}
+}
+
+// missingParam 返回第一个缺失参数的错误信息，参数齐全时返回空字符串
+func missingParam(params url.Values, names ...string) string {
+	for _, name := range names {
+		if params.Get(name) == "" {
+			return fmt.Sprintf("Required parameter '%s' is not present", name)
+		}
+	}
+	return ""
+}
+
+// matchParam 参数为空时匹配所有值，blur 时 * 匹配任意字符
+func matchParam(blur bool, param, value string) bool {
+	switch {
+	case param == "":
+		return true
+	case !blur:
+		return param == value
+	}
+	pattern := strings.ReplaceAll(regexp.QuoteMeta(param), `\*`, ".*")
+	return regexp.MustCompile("^" + pattern + "$").MatchString(value)
+}
+
+// hasTags 配置是否带有 tags 中的所有标签
+func hasTags(configTags, tags string) bool {
+	have := map[string]bool{}
+	for _, tag := range strings.Split(configTags, ",") {
+		have[strings.TrimSpace(tag)] = true
+	}
+	for _, tag := range strings.Split(tags, ",") {
+		if !have[strings.TrimSpace(tag)] {
+			return false
+		}
+	}
+	return true
+}
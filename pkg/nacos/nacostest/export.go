@@ -0,0 +1,193 @@
+package nacostest
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// exportMetadataFile 导出包中的元数据文件
+const exportMetadataFile = ".metadata.yml"
+
+// exportMetadata .metadata.yml 中单个配置的元数据。与 Nacos 一致，不包含数据密钥
+type exportMetadata struct {
+	DataId  string `yaml:"dataId"`
+	Group   string `yaml:"group"`
+	Type    string `yaml:"type"`
+	AppName string `yaml:"appName"`
+	Desc    string `yaml:"desc"`
+}
+
+// exportConfigs 处理 exportV2=true 的导出请求，按 tenant、group、appName、ids 过滤
+func (s *Server) exportConfigs(w http.ResponseWriter, r *http.Request) {
+	tenant := tenantOf(r.Form.Get("tenant"))
+	group := r.Form.Get("group")
+	appName := r.Form.Get("appName")
+	ids := map[string]bool{}
+	for _, id := range strings.Split(r.Form.Get("ids"), ",") {
+		if id != "" {
+			ids[id] = true
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	var metadata []exportMetadata
+
+	s.mu.Lock()
+	for _, config := range s.sortedConfigsLocked() {
+		if config.Namespace != tenant ||
+			group != "" && config.Group != group ||
+			appName != "" && config.AppName != appName ||
+			len(ids) > 0 && !ids[strconv.FormatInt(config.id, 10)] {
+			continue
+		}
+		f, err := zw.Create(path.Join(config.Group, config.DataId))
+		if err == nil {
+			_, err = f.Write([]byte(config.Content))
+		}
+		if err != nil {
+			s.mu.Unlock()
+			writeText(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		metadata = append(metadata, exportMetadata{
+			DataId:  config.DataId,
+			Group:   config.Group,
+			Type:    config.Type,
+			AppName: config.AppName,
+			Desc:    config.Desc,
+		})
+	}
+	s.mu.Unlock()
+
+	data, err := yaml.Marshal(map[string]any{"metadata": metadata})
+	if err == nil {
+		var f io.Writer
+		if f, err = zw.Create(exportMetadataFile); err == nil {
+			_, err = f.Write(data)
+		}
+	}
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		writeText(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// importConfigs 处理 import=true 的导入请求，上传的 zip 在表单字段 file 中。
+// 与 Nacos 一致按 policy 处理已存在的配置：ABORT 时在第一个冲突处停止，其余配置记为失败
+func (s *Server) importConfigs(w http.ResponseWriter, r *http.Request) {
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeText(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeText(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	configs, err := readImportArchive(data)
+	if err != nil {
+		writeJSON(w, map[string]any{"code": 100002, "message": err.Error(), "data": nil})
+		return
+	}
+
+	namespace := r.Form.Get("namespace")
+	policy := r.Form.Get("policy")
+	var (
+		succCount int
+		skipData  []map[string]string
+		failData  []map[string]string
+	)
+
+	s.mu.Lock()
+	for i, config := range configs {
+		config.Namespace = namespace
+		if _, ok := s.configs[keyOf(namespace, config.Group, config.DataId)]; ok {
+			if policy == "SKIP" {
+				skipData = append(skipData, map[string]string{"dataId": config.DataId, "group": config.Group})
+				continue
+			}
+			if policy != "OVERWRITE" {
+				for _, failed := range configs[i:] {
+					failData = append(failData, map[string]string{"dataId": failed.DataId, "group": failed.Group})
+				}
+				break
+			}
+		}
+		s.publishLocked(config)
+		succCount++
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]any{
+		"code":    200,
+		"message": "success",
+		"data": map[string]any{
+			"succCount": succCount,
+			"skipCount": len(skipData),
+			"skipData":  skipData,
+			"failData":  failData,
+		},
+	})
+}
+
+// readImportArchive 解析导入包中的配置内容和 .metadata.yml
+func readImportArchive(data []byte) ([]Config, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		configs  []Config
+		metadata struct {
+			Metadata []exportMetadata `yaml:"metadata"`
+		}
+	)
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if f.Name == exportMetadataFile {
+			if err := yaml.Unmarshal(content, &metadata); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		group, dataId, _ := strings.Cut(f.Name, "/")
+		configs = append(configs, Config{Group: group, DataId: dataId, Content: string(content)})
+	}
+
+	for i := range configs {
+		for _, item := range metadata.Metadata {
+			if item.Group == configs[i].Group && item.DataId == configs[i].DataId {
+				configs[i].Type = item.Type
+				configs[i].AppName = item.AppName
+				configs[i].Desc = item.Desc
+			}
+		}
+	}
+	return configs, nil
+}
@@ -0,0 +1,152 @@
+package nacostest
+
+import (
+	"net/http"
+)
+
+// Namespace 自定义命名空间，public 命名空间始终存在，不在其中
+type Namespace struct {
+	Id   string
+	Name string
+	Desc string
+}
+
+// AddNamespace 创建命名空间，Id 已存在时更新名称和描述
+func (s *Server) AddNamespace(namespace Namespace) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.namespaces {
+		if s.namespaces[i].Id == namespace.Id {
+			s.namespaces[i] = namespace
+			return
+		}
+	}
+	s.namespaces = append(s.namespaces, namespace)
+}
+
+// Namespaces 按创建顺序返回自定义命名空间
+func (s *Server) Namespaces() []Namespace {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Namespace{}, s.namespaces...)
+}
+
+// handleNamespaces 处理 /v1/console/namespaces 的查询、创建、修改和删除。
+// 与 Nacos 一致，删除命名空间不删除其中的配置
+func (s *Server) handleNamespaces(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeText(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.listNamespaces(w)
+	case http.MethodPost:
+		s.createNamespace(w, r)
+	case http.MethodPut:
+		s.updateNamespace(w, r)
+	case http.MethodDelete:
+		s.deleteNamespace(w, r)
+	default:
+		writeText(w, http.StatusMethodNotAllowed, "Request method '"+r.Method+"' not supported")
+	}
+}
+
+func (s *Server) listNamespaces(w http.ResponseWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := map[string]int{}
+	for key := range s.configs {
+		counts[key.namespace]++
+	}
+
+	data := []map[string]any{namespaceInfo(Namespace{Name: "public", Desc: "Public Namespace"}, 0, counts[""])}
+	for _, namespace := range s.namespaces {
+		data = append(data, namespaceInfo(namespace, 2, counts[namespace.Id]))
+	}
+	writeJSON(w, map[string]any{"code": http.StatusOK, "message": nil, "data": data})
+}
+
+func (s *Server) createNamespace(w http.ResponseWriter, r *http.Request) {
+	if missing := missingParam(r.Form, "namespaceName"); missing != "" {
+		writeText(w, http.StatusBadRequest, missing)
+		return
+	}
+
+	namespace := Namespace{
+		Id:   r.Form.Get("customNamespaceId"),
+		Name: r.Form.Get("namespaceName"),
+		Desc: r.Form.Get("namespaceDesc"),
+	}
+	if namespace.Id == "" {
+		namespace.Id = randomHex(16)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if namespace.Id == "public" || s.namespaceIndexLocked(namespace.Id) >= 0 {
+		writeText(w, http.StatusInternalServerError, "the namespace id is already exist")
+		return
+	}
+	s.namespaces = append(s.namespaces, namespace)
+	writeText(w, http.StatusOK, "true")
+}
+
+func (s *Server) updateNamespace(w http.ResponseWriter, r *http.Request) {
+	if missing := missingParam(r.Form, "namespace", "namespaceShowName"); missing != "" {
+		writeText(w, http.StatusBadRequest, missing)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.namespaceIndexLocked(r.Form.Get("namespace"))
+	if i < 0 {
+		writeText(w, http.StatusNotFound, "namespace not exist")
+		return
+	}
+	s.namespaces[i].Name = r.Form.Get("namespaceShowName")
+	s.namespaces[i].Desc = r.Form.Get("namespaceDesc")
+	writeText(w, http.StatusOK, "true")
+}
+
+func (s *Server) deleteNamespace(w http.ResponseWriter, r *http.Request) {
+	if missing := missingParam(r.Form, "namespaceId"); missing != "" {
+		writeText(w, http.StatusBadRequest, missing)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := s.namespaceIndexLocked(r.Form.Get("namespaceId")); i >= 0 {
+		s.namespaces = append(s.namespaces[:i], s.namespaces[i+1:]...)
+	}
+	writeText(w, http.StatusOK, "true")
+}
+
+func (s *Server) namespaceIndexLocked(id string) int {
+	for i, namespace := range s.namespaces {
+		if namespace.Id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// namespaceInfo 命名空间列表中的一项，namespaceType 0 为 public，2 为自定义
+func namespaceInfo(namespace Namespace, namespaceType, configCount int) map[string]any {
+	return map[string]any{
+		"namespace":         namespace.Id,
+		"namespaceShowName": namespace.Name,
+		"namespaceDesc":     namespace.Desc,
+		"quota":             200,
+		"configCount":       configCount,
+		"type":              namespaceType,
+	}
+}
@@ -0,0 +1,230 @@
+// Package nacostest 提供基于 httptest 的内存 Nacos 服务端，用于在不启动真实 Nacos 的情况下测试。
+//
+// 服务端实现 Nacos 1.x/2.x 的 v1 Open API：登录、配置增删改查、分页列表、长轮询监听、
+// 历史版本、命名空间和导出导入，发布时按 casMd5 检查冲突，开启认证后校验 accessToken：
+//
+//	server := nacostest.NewServer(nacostest.WithAuth("nacos", "nacos"))
+//	defer server.Close()
+//
+//	server.SetConfig(nacostest.Config{Group: "DEFAULT_GROUP", DataId: "app.yaml", Content: "port: 8080"})
+//	client := nacos.New(server.Addr, nacos.WithCredentials("nacos", "nacos"))
+//
+// 服务端报告的版本为 Version，客户端自动识别版本时使用 v1 接口。
+package nacostest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Version 服务端状态接口报告的版本，低于 2.2 时客户端使用 v1 Open API
+const Version = "2.1.2"
+
+const (
+	contextPath               = "/nacos"
+	defaultTokenTTL           = 5 * time.Hour
+	defaultLongPollingTimeout = 30 * time.Second
+)
+
+// Server 内存中的 Nacos 服务端，所有方法可并发调用
+type Server struct {
+	*httptest.Server
+	Addr string // 客户端使用的 Nacos 地址，如 http://127.0.0.1:port/nacos
+
+	username           string
+	password           string
+	tokenTTL           time.Duration
+	longPollingTimeout time.Duration
+
+	mu         sync.Mutex
+	tokens     map[string]time.Time // accessToken 及其过期时间
+	logins     int
+	nextId     int64
+	configs    map[configKey]*Config
+	history    []History
+	namespaces []Namespace
+	changed    chan struct{} // 配置变更时关闭并替换，唤醒挂起的长轮询
+	closed     chan struct{} // Close 时关闭，结束挂起的长轮询
+}
+
+// Option 创建服务端时的可选配置
+type Option func(*Server)
+
+// WithAuth 开启认证，只有 username/password 可以登录，其他接口需要携带登录返回的 accessToken
+func WithAuth(username, password string) Option {
+	return func(s *Server) {
+		s.username = username
+		s.password = password
+	}
+}
+
+// WithTokenTTL 设置登录返回的 token 有效期，默认 5 小时
+func WithTokenTTL(ttl time.Duration) Option {
+	return func(s *Server) {
+		s.tokenTTL = ttl
+	}
+}
+
+// WithLongPollingTimeout 设置长轮询最长挂起时间，默认与 Nacos 一致为 30 秒。
+// 请求头 Long-Pulling-Timeout 更短时按请求头
+func WithLongPollingTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.longPollingTimeout = timeout
+	}
+}
+
+// NewServer 创建并启动服务端，调用方需要调用 Close
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		tokenTTL:           defaultTokenTTL,
+		longPollingTimeout: defaultLongPollingTimeout,
+		tokens:             map[string]time.Time{},
+		configs:            map[configKey]*Config{},
+		changed:            make(chan struct{}),
+		closed:             make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(contextPath+"/v1/console/server/state", s.handleState)
+	mux.HandleFunc(contextPath+"/v1/auth/login", s.handleLogin)
+	mux.HandleFunc(contextPath+"/v1/auth/users/login", s.handleLogin)
+	mux.HandleFunc(contextPath+"/v1/cs/configs", s.authorized(s.handleConfigs))
+	mux.HandleFunc(contextPath+"/v1/cs/configs/listener", s.authorized(s.handleListener))
+	mux.HandleFunc(contextPath+"/v1/cs/history", s.authorized(s.handleHistory))
+	mux.HandleFunc(contextPath+"/v1/console/namespaces", s.authorized(s.handleNamespaces))
+
+	s.Server = httptest.NewServer(mux)
+	s.Addr = s.URL + contextPath
+	return s
+}
+
+// Close 结束挂起的长轮询并关闭服务端
+func (s *Server) Close() {
+	s.mu.Lock()
+	select {
+	case <-s.closed:
+	default:
+		close(s.closed)
+	}
+	s.mu.Unlock()
+
+	s.Server.Close()
+}
+
+// Logins 返回登录成功的次数，用于检查客户端是否复用了 token
+func (s *Server) Logins() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logins
+}
+
+// ExpireTokens 使已签发的 token 全部过期，之后携带这些 token 的请求返回 403 token expired!
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for token := range s.tokens {
+		s.tokens[token] = now
+	}
+}
+
+func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]any{
+		"version":         Version,
+		"standalone_mode": "standalone",
+		"function_mode":   nil,
+		"auth_enabled":    s.username != "",
+	})
+}
+
+// handleLogin 处理 /v1/auth/login，用户名密码可以在表单或查询参数中
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeText(w, http.StatusMethodNotAllowed, "Request method '"+r.Method+"' not supported")
+		return
+	}
+
+	username := r.FormValue("username")
+	if s.username != "" && (username != s.username || r.FormValue("password") != s.password) {
+		writeText(w, http.StatusForbidden, "unknown user!")
+		return
+	}
+
+	token := randomHex(16)
+	s.mu.Lock()
+	s.tokens[token] = time.Now().Add(s.tokenTTL)
+	s.logins++
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]any{
+		"accessToken": token,
+		"tokenTtl":    int64(s.tokenTTL.Seconds()),
+		"globalAdmin": true,
+		"username":    username,
+	})
+}
+
+// authorized 开启认证时校验请求头 Authorization: Bearer 或参数 accessToken 中的 token，
+// 失败时与 Nacos 一样返回 403 和原因
+func (s *Server) authorized(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.username == "" {
+			handler(w, r)
+			return
+		}
+
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" {
+			token = r.FormValue("accessToken")
+		}
+		if token == "" {
+			writeText(w, http.StatusForbidden, "user not found!")
+			return
+		}
+
+		s.mu.Lock()
+		expireTime, ok := s.tokens[token]
+		s.mu.Unlock()
+		switch {
+		case !ok:
+			writeText(w, http.StatusForbidden, "token invalid!")
+		case !time.Now().Before(expireTime):
+			writeText(w, http.StatusForbidden, "token expired!")
+		default:
+			handler(w, r)
+		}
+	}
+}
+
+// notifyLocked 唤醒挂起的长轮询，调用方需持有 s.mu
+func (s *Server) notifyLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeText(w http.ResponseWriter, status int, text string) {
+	w.Header().Set("Content-Type", "text/plain;charset=UTF-8")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(text))
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,272 @@
+package nacostest_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/nacos"
+	"github.com/Talbot3/nacos-cli/pkg/nacos/nacostest"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(t *testing.T, opts ...nacostest.Option) *nacostest.Server {
+	server := nacostest.NewServer(opts...)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func operation(namespace string) *nacos.NacosOperation {
+	return &nacos.NacosOperation{Namespace: namespace, Group: "DEFAULT_GROUP"}
+}
+
+func TestConfigCRUD(t *testing.T) {
+	server := newServer(t)
+	client := nacos.New(server.Addr)
+
+	_, err := client.Get(nacos.ConfigGetOperation{NacosOperation: operation("public"), DataId: "app.yaml"})
+	assert.ErrorIs(t, err, nacos.ErrConfigNotExist)
+
+	desc := "订单服务"
+	require.NoError(t, client.Edit(nacos.ConfigEditOperation{
+		NacosOperation: operation("public"),
+		ConfigMetadata: nacos.ConfigMetadata{Desc: &desc, Tags: []string{"team=payments"}},
+		DataId:         "app.yaml",
+		Content:        "port: 8080",
+		Type:           "yaml",
+	}))
+
+	detail, err := client.Detail(nacos.ConfigGetOperation{NacosOperation: operation(""), DataId: "app.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, "port: 8080", detail.Content)
+	assert.Equal(t, "yaml", detail.Type)
+	assert.Equal(t, "订单服务", detail.Desc)
+	assert.Equal(t, "team=payments", detail.ConfigTags)
+	assert.Equal(t, "", detail.Tenant, "public 命名空间保存为空字符串")
+
+	config, ok := server.Config("public", "DEFAULT_GROUP", "app.yaml")
+	require.True(t, ok)
+	assert.Equal(t, detail.Md5, config.Md5)
+
+	require.NoError(t, client.DeleteConfig(nacos.ConfigDeleteOperation{NacosOperation: operation("public"), DataId: "app.yaml"}))
+	_, ok = server.Config("", "DEFAULT_GROUP", "app.yaml")
+	assert.False(t, ok)
+}
+
+func TestListPagination(t *testing.T) {
+	server := newServer(t)
+	for i := 0; i < 1001; i++ {
+		server.SetConfig(nacostest.Config{Namespace: "dev", Group: "DEFAULT_GROUP", DataId: fmt.Sprintf("app-%04d.yaml", i), Content: "a: 1"})
+	}
+	server.SetConfig(nacostest.Config{Namespace: "dev", Group: "OTHER", DataId: "other.yaml", Content: "a: 1"})
+
+	client := nacos.New(server.Addr)
+	items, err := client.AllConfig(nacos.ConfigGetOperation{NacosOperation: operation("dev")})
+	require.NoError(t, err)
+	require.Len(t, items, 1001, "跨 3 页获取，只包含指定分组")
+	assert.Equal(t, "app-0000.yaml", items[0].DataId)
+	assert.Equal(t, "app-1000.yaml", items[1000].DataId)
+}
+
+func TestCasMd5Conflict(t *testing.T) {
+	server := newServer(t)
+	server.SetConfig(nacostest.Config{Group: "DEFAULT_GROUP", DataId: "app.yaml", Content: "v1"})
+	before, _ := server.Config("", "DEFAULT_GROUP", "app.yaml")
+	server.SetConfig(nacostest.Config{Group: "DEFAULT_GROUP", DataId: "app.yaml", Content: "v2"})
+
+	client := nacos.New(server.Addr)
+	err := client.Edit(nacos.ConfigEditOperation{
+		NacosOperation: operation("public"),
+		DataId:         "app.yaml",
+		Content:        "v3",
+		CasMd5:         before.Md5,
+	})
+	require.ErrorIs(t, err, nacos.ErrConflict)
+	assert.Contains(t, err.Error(), "Cas publish fail")
+
+	config, _ := server.Config("", "DEFAULT_GROUP", "app.yaml")
+	assert.Equal(t, "v2", config.Content, "冲突时不覆盖")
+
+	current := config.Md5
+	require.NoError(t, client.Edit(nacos.ConfigEditOperation{NacosOperation: operation("public"), DataId: "app.yaml", Content: "v3", CasMd5: current}))
+}
+
+func TestAuth(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := newServer(t, nacostest.WithAuth("nacos", "secret"))
+
+	_, err := nacos.Login(server.Addr, "nacos", "wrong")
+	assert.ErrorIs(t, err, nacos.ErrAuthFailed)
+
+	resp, err := http.Get(server.Addr + "/v1/cs/configs?dataId=a&group=DEFAULT_GROUP")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, "user not found!", string(body))
+
+	server.SetConfig(nacostest.Config{Group: "DEFAULT_GROUP", DataId: "app.yaml", Content: "a: 1"})
+	client := nacos.New(server.Addr, nacos.WithCredentials("nacos", "secret"))
+	get := func() error {
+		_, err := client.Get(nacos.ConfigGetOperation{NacosOperation: operation("public"), DataId: "app.yaml"})
+		return err
+	}
+
+	require.NoError(t, get())
+	require.NoError(t, get())
+	assert.Equal(t, 1, server.Logins(), "token 被缓存复用")
+
+	server.ExpireTokens()
+	require.NoError(t, get(), "token 过期后重新登录")
+	assert.Equal(t, 2, server.Logins())
+
+	_, err = nacos.New(server.Addr, nacos.WithToken("forged")).Get(nacos.ConfigGetOperation{NacosOperation: operation("public"), DataId: "app.yaml"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "token invalid!")
+}
+
+func TestWatch(t *testing.T) {
+	server := newServer(t)
+	server.SetConfig(nacostest.Config{Namespace: "dev", Group: "DEFAULT_GROUP", DataId: "app.yaml", Content: "v1"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := nacos.New(server.Addr)
+	events, err := client.Watch(ctx, nacos.ConfigKey{Namespace: "dev", Group: "DEFAULT_GROUP", DataId: "app.yaml"})
+	require.NoError(t, err)
+
+	event := <-events
+	require.NoError(t, event.Err)
+	assert.Equal(t, "v1", event.Config.Content)
+
+	// 等待长轮询挂起后再修改
+	time.Sleep(100 * time.Millisecond)
+	server.SetConfig(nacostest.Config{Namespace: "dev", Group: "DEFAULT_GROUP", DataId: "app.yaml", Content: "v2"})
+
+	select {
+	case event = <-events:
+		require.NoError(t, event.Err)
+		assert.Equal(t, "v2", event.Config.Content)
+		assert.Equal(t, "v1", event.Previous.Content)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change event")
+	}
+}
+
+func TestListenerTimeout(t *testing.T) {
+	server := newServer(t, nacostest.WithLongPollingTimeout(50*time.Millisecond))
+	server.SetConfig(nacostest.Config{Group: "DEFAULT_GROUP", DataId: "app.yaml", Content: "v1"})
+	config, _ := server.Config("", "DEFAULT_GROUP", "app.yaml")
+
+	listen := func(md5 string) string {
+		form := url.Values{"Listening-Configs": {"app.yaml\x02DEFAULT_GROUP\x02" + md5 + "\x01"}}
+		req, err := http.NewRequest(http.MethodPost, server.Addr+"/v1/cs/configs/listener", strings.NewReader(form.Encode()))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Long-Pulling-Timeout", "30000")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	start := time.Now()
+	assert.Equal(t, "", listen(config.Md5), "未变化时挂起到超时")
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	assert.Equal(t, url.QueryEscape("app.yaml\x02DEFAULT_GROUP\x01"), listen("stale"))
+}
+
+func TestHistory(t *testing.T) {
+	server := newServer(t)
+	server.SetConfig(nacostest.Config{Group: "DEFAULT_GROUP", DataId: "app.yaml", Content: "v1"})
+	server.SetConfig(nacostest.Config{Group: "DEFAULT_GROUP", DataId: "app.yaml", Content: "v2"})
+	require.NoError(t, nacos.New(server.Addr).DeleteConfig(nacos.ConfigDeleteOperation{NacosOperation: operation("public"), DataId: "app.yaml"}))
+
+	history := server.History("public", "DEFAULT_GROUP", "app.yaml")
+	require.Len(t, history, 3)
+	assert.Equal(t, []string{"D", "U", "I"}, []string{history[0].OpType, history[1].OpType, history[2].OpType})
+	assert.Equal(t, []string{"v2", "v1", "v1"}, []string{history[0].Content, history[1].Content, history[2].Content}, "U、D 记录变更前的内容")
+
+	var page struct {
+		TotalCount int `json:"totalCount"`
+		PageItems  []struct {
+			Id      string `json:"id"`
+			OpType  string `json:"opType"`
+			Content string `json:"content"`
+		} `json:"pageItems"`
+	}
+	getJSON(t, server.Addr+"/v1/cs/history?search=accurate&dataId=app.yaml&group=DEFAULT_GROUP&pageNo=1&pageSize=2", &page)
+	assert.Equal(t, 3, page.TotalCount)
+	require.Len(t, page.PageItems, 2)
+	assert.Equal(t, "D", page.PageItems[0].OpType)
+
+	var item struct {
+		Content string `json:"content"`
+		OpType  string `json:"opType"`
+	}
+	getJSON(t, server.Addr+"/v1/cs/history?dataId=app.yaml&group=DEFAULT_GROUP&nid="+page.PageItems[1].Id, &item)
+	assert.Equal(t, "U", item.OpType)
+	assert.Equal(t, "v1", item.Content)
+}
+
+func TestNamespaces(t *testing.T) {
+	server := newServer(t)
+	server.AddNamespace(nacostest.Namespace{Id: "dev", Name: "开发"})
+	server.SetConfig(nacostest.Config{Namespace: "dev", Group: "DEFAULT_GROUP", DataId: "app.yaml", Content: "a: 1"})
+
+	resp, err := http.PostForm(server.Addr+"/v1/console/namespaces", url.Values{
+		"customNamespaceId": {"prod"},
+		"namespaceName":     {"生产"},
+	})
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.PostForm(server.Addr+"/v1/console/namespaces", url.Values{
+		"customNamespaceId": {"prod"},
+		"namespaceName":     {"重复"},
+	})
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	var result struct {
+		Data []struct {
+			Namespace         string `json:"namespace"`
+			NamespaceShowName string `json:"namespaceShowName"`
+			ConfigCount       int    `json:"configCount"`
+		} `json:"data"`
+	}
+	getJSON(t, server.Addr+"/v1/console/namespaces", &result)
+	require.Len(t, result.Data, 3)
+	assert.Equal(t, "public", result.Data[0].NamespaceShowName)
+	assert.Equal(t, "dev", result.Data[1].Namespace)
+	assert.Equal(t, 1, result.Data[1].ConfigCount)
+	assert.Equal(t, "生产", result.Data[2].NamespaceShowName)
+
+	req, err := http.NewRequest(http.MethodDelete, server.Addr+"/v1/console/namespaces?namespaceId=prod", nil)
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, []nacostest.Namespace{{Id: "dev", Name: "开发"}}, server.Namespaces())
+}
+
+func getJSON(t *testing.T, url string, v any) {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+}
@@ -0,0 +1,156 @@
+package nacos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// 服务发现接口使用 v1 Open API，Nacos 1.x 至 3.x（开启 v1 兼容时）均支持
+const (
+	serviceListUrl  = "/v1/ns/service/list"
+	instanceListUrl = "/v1/ns/instance/list"
+	instanceUrl     = "/v1/ns/instance"
+)
+
+// ListServices 列出命名空间中分组下的所有服务名
+func (c *Client) ListServices(operation ServiceListOperation) ([]string, error) {
+	return c.ListServicesContext(context.Background(), operation)
+}
+
+// ListServicesContext 列出所有服务名，ctx 取消或超时时中断请求
+func (c *Client) ListServicesContext(ctx context.Context, operation ServiceListOperation) ([]string, error) {
+	var services []string
+	for pageNo := 1; ; pageNo++ {
+		query := namingQuery(operation.NacosOperation)
+		query.Set("pageNo", fmt.Sprint(pageNo))
+		query.Set("pageSize", fmt.Sprint(listPageSize))
+
+		result := struct {
+			Count int      `json:"count"`
+			Doms  []string `json:"doms"`
+		}{}
+		if err := c.namingRequest(ctx, http.MethodGet, serviceListUrl, query, &result); err != nil {
+			return nil, err
+		}
+
+		services = append(services, result.Doms...)
+		if len(result.Doms) == 0 || len(services) >= result.Count {
+			return services, nil
+		}
+	}
+}
+
+// ListInstances 查询服务的实例
+func (c *Client) ListInstances(operation InstanceListOperation) ([]Instance, error) {
+	return c.ListInstancesContext(context.Background(), operation)
+}
+
+// ListInstancesContext 查询服务的实例，ctx 取消或超时时中断请求
+func (c *Client) ListInstancesContext(ctx context.Context, operation InstanceListOperation) ([]Instance, error) {
+	query := namingQuery(operation.NacosOperation)
+	query.Set("serviceName", operation.ServiceName)
+	query.Set("clusters", strings.Join(operation.Clusters, ","))
+	query.Set("healthyOnly", fmt.Sprint(operation.HealthyOnly))
+
+	result := struct {
+		Hosts []Instance `json:"hosts"`
+	}{}
+	if err := c.namingRequest(ctx, http.MethodGet, instanceListUrl, query, &result); err != nil {
+		return nil, err
+	}
+	return result.Hosts, nil
+}
+
+// RegisterInstance 注册实例
+func (c *Client) RegisterInstance(operation InstanceRegisterOperation) error {
+	return c.RegisterInstanceContext(context.Background(), operation)
+}
+
+// RegisterInstanceContext 注册实例，ctx 取消或超时时中断请求
+func (c *Client) RegisterInstanceContext(ctx context.Context, operation InstanceRegisterOperation) error {
+	weight := operation.Weight
+	if weight == 0 {
+		weight = 1
+	}
+
+	query := namingQuery(operation.NacosOperation)
+	query.Set("serviceName", operation.ServiceName)
+	query.Set("ip", operation.Ip)
+	query.Set("port", fmt.Sprint(operation.Port))
+	query.Set("clusterName", operation.ClusterName)
+	query.Set("weight", fmt.Sprint(weight))
+	query.Set("enabled", fmt.Sprint(!operation.Disabled))
+	query.Set("healthy", "true")
+	query.Set("ephemeral", fmt.Sprint(operation.Ephemeral))
+	if operation.Metadata != nil {
+		metadata, err := json.Marshal(operation.Metadata)
+		if err != nil {
+			return err
+		}
+		query.Set("metadata", string(metadata))
+	}
+
+	return c.namingRequest(ctx, http.MethodPost, instanceUrl, query, nil)
+}
+
+// DeregisterInstance 注销实例
+func (c *Client) DeregisterInstance(operation InstanceDeregisterOperation) error {
+	return c.DeregisterInstanceContext(context.Background(), operation)
+}
+
+// DeregisterInstanceContext 注销实例，ctx 取消或超时时中断请求
+func (c *Client) DeregisterInstanceContext(ctx context.Context, operation InstanceDeregisterOperation) error {
+	query := namingQuery(operation.NacosOperation)
+	query.Set("serviceName", operation.ServiceName)
+	query.Set("ip", operation.Ip)
+	query.Set("port", fmt.Sprint(operation.Port))
+	query.Set("clusterName", operation.ClusterName)
+	query.Set("ephemeral", fmt.Sprint(operation.Ephemeral))
+
+	return c.namingRequest(ctx, http.MethodDelete, instanceUrl, query, nil)
+}
+
+// namingQuery 服务发现接口的公共参数。
+// 服务发现接口的 namespaceId 用 "public" 表示默认命名空间，与配置接口的 tenant 相反
+func namingQuery(operation *NacosOperation) url.Values {
+	query := url.Values{}
+	if operation == nil {
+		operation = &NacosOperation{}
+	}
+	query.Set("namespaceId", namespaceOf(operation.Namespace))
+	if operation.Group != "" {
+		query.Set("groupName", operation.Group)
+	}
+	return query
+}
+
+// namingRequest 发送服务发现请求，result 为 nil 时不解析响应（成功时响应为 ok）
+func (c *Client) namingRequest(ctx context.Context, method, path string, query url.Values, result any) error {
+	namingUrl, err := serverPath(path)
+	if err != nil {
+		return err
+	}
+
+	var resp *http.Response
+	var body []byte
+	if method == http.MethodPost {
+		resp, body, err = c.sendForm(ctx, method, namingUrl, nil, query)
+	} else {
+		resp, body, err = c.send(ctx, method, namingUrl+"?"+query.Encode(), nil, nil)
+	}
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("response error,status code:%d\n%s", resp.StatusCode, body)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(body, result)
+}
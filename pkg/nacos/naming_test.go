@@ -0,0 +1,155 @@
+package nacos
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// namingServer 记录服务发现请求的参数，/v1/ns/service/list 共返回 total 个服务
+func namingServer(t *testing.T, total int) (*httptest.Server, *[]url.Values) {
+	var requests []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		params := r.Form
+		params.Set("method", r.Method)
+		requests = append(requests, params)
+
+		switch r.URL.Path {
+		case "/nacos" + serviceListUrl:
+			pageNo, _ := strconv.Atoi(r.Form.Get("pageNo"))
+			pageSize, _ := strconv.Atoi(r.Form.Get("pageSize"))
+			var doms []string
+			for i := (pageNo - 1) * pageSize; i < total && i < pageNo*pageSize; i++ {
+				doms = append(doms, fmt.Sprintf("service-%d", i))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"count": total, "doms": doms})
+		case "/nacos" + instanceListUrl:
+			_, _ = w.Write([]byte(`{"hosts":[{"instanceId":"10.0.0.1#8080#DEFAULT#G@@order","ip":"10.0.0.1","port":8080,"weight":1,"healthy":true,"enabled":true,"ephemeral":true,"clusterName":"DEFAULT","serviceName":"G@@order","metadata":{"zone":"a"}}]}`))
+		case "/nacos" + instanceUrl:
+			_, _ = w.Write([]byte("ok"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestListServicesPaging(t *testing.T) {
+	server, requests := namingServer(t, listPageSize+1)
+	client := New(server.URL + "/nacos")
+
+	services, err := client.ListServices(ServiceListOperation{NacosOperation: &NacosOperation{Group: "G"}})
+	require.NoError(t, err)
+	assert.Len(t, services, listPageSize+1)
+	assert.Equal(t, fmt.Sprintf("service-%d", listPageSize), services[listPageSize])
+
+	require.Len(t, *requests, 2)
+	assert.Equal(t, "2", (*requests)[1].Get("pageNo"))
+	assert.Equal(t, "public", (*requests)[0].Get("namespaceId"), "服务发现接口默认命名空间为 public")
+	assert.Equal(t, "G", (*requests)[0].Get("groupName"))
+}
+
+func TestListInstances(t *testing.T) {
+	server, requests := namingServer(t, 0)
+	client := New(server.URL + "/nacos")
+
+	instances, err := client.ListInstances(InstanceListOperation{
+		NacosOperation: &NacosOperation{Namespace: "dev", Group: "G"},
+		ServiceName:    "order",
+		Clusters:       []string{"a", "b"},
+		HealthyOnly:    true,
+	})
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, Instance{
+		InstanceId:  "10.0.0.1#8080#DEFAULT#G@@order",
+		Ip:          "10.0.0.1",
+		Port:        8080,
+		Weight:      1,
+		Healthy:     true,
+		Enabled:     true,
+		Ephemeral:   true,
+		ClusterName: "DEFAULT",
+		ServiceName: "G@@order",
+		Metadata:    map[string]string{"zone": "a"},
+	}, instances[0])
+
+	params := (*requests)[0]
+	assert.Equal(t, "dev", params.Get("namespaceId"))
+	assert.Equal(t, "order", params.Get("serviceName"))
+	assert.Equal(t, "a,b", params.Get("clusters"))
+	assert.Equal(t, "true", params.Get("healthyOnly"))
+}
+
+func TestRegisterAndDeregisterInstance(t *testing.T) {
+	server, requests := namingServer(t, 0)
+	client := New(server.URL + "/nacos")
+
+	err := client.RegisterInstance(InstanceRegisterOperation{
+		NacosOperation: &NacosOperation{Group: "G"},
+		ServiceName:    "order",
+		Ip:             "10.0.0.1",
+		Port:           8080,
+		Ephemeral:      true,
+		Metadata:       map[string]string{"zone": "a"},
+	})
+	require.NoError(t, err)
+
+	err = client.DeregisterInstance(InstanceDeregisterOperation{
+		NacosOperation: &NacosOperation{Group: "G"},
+		ServiceName:    "order",
+		Ip:             "10.0.0.1",
+		Port:           8080,
+		Ephemeral:      true,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, *requests, 2)
+	register := (*requests)[0]
+	assert.Equal(t, http.MethodPost, register.Get("method"))
+	assert.Equal(t, "public", register.Get("namespaceId"))
+	assert.Equal(t, "1", register.Get("weight"), "未指定权重时为 1")
+	assert.Equal(t, "true", register.Get("enabled"))
+	assert.Equal(t, "true", register.Get("ephemeral"))
+	assert.JSONEq(t, `{"zone":"a"}`, register.Get("metadata"))
+
+	deregister := (*requests)[1]
+	assert.Equal(t, http.MethodDelete, deregister.Get("method"))
+	assert.Equal(t, "8080", deregister.Get("port"))
+	assert.Equal(t, "order", deregister.Get("serviceName"))
+}
+
+func TestNamingRequestError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("unknown user!"))
+	}))
+	t.Cleanup(server.Close)
+
+	client := New(server.URL+"/nacos", WithRetryPolicy(nil))
+	_, err := client.ListServices(ServiceListOperation{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+}
+
+func TestNewOptions(t *testing.T) {
+	client := New("http://localhost:8848/nacos",
+		WithAPIVersion(ApiVersionV2),
+		WithCredentials("nacos", "secret"),
+		WithRetryPolicy(nil))
+
+	assert.Equal(t, ApiVersionV2, client.Config.ApiVersion)
+	assert.Equal(t, "nacos", client.Config.Username)
+	assert.Equal(t, "secret", client.Config.Password)
+	assert.Nil(t, client.RetryPolicy)
+	assert.NotNil(t, New("http://localhost:8848/nacos").RetryPolicy, "默认重试")
+}
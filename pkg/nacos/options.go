@@ -0,0 +1,142 @@
+package nacos
+
+import (
+	"github.com/Talbot3/nacos-cli/pkg/encrypt"
+	"net/http"
+	"time"
+)
+
+// Option 创建客户端时的可选配置，见 New
+type Option func(*Client)
+
+// New 创建访问 addr 的客户端。addr 为 Nacos 地址（如 http://127.0.0.1:8848/nacos），集群多个节点用逗号分隔。
+// 默认自动识别 Open API 版本、使用 HTTP 传输、不认证，并按 DefaultRetryPolicy 重试。
+// 与 NewDefaultClient 不同，New 不读取任何环境变量
+func New(addr string, opts ...Option) *Client {
+	client := &Client{
+		Config: &NacosConfig{
+			Addr:       addr,
+			ApiVersion: ApiVersionAuto,
+		},
+		RetryPolicy: DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	return client
+}
+
+// withConfig 使用完整的连接配置，供从环境变量或上下文创建客户端的构造函数使用
+func withConfig(config *NacosConfig) Option {
+	return func(c *Client) {
+		c.Config = config
+	}
+}
+
+// WithAPIVersion 固定 Open API 版本（ApiVersionV1、ApiVersionV2、ApiVersionV3），跳过版本探测
+func WithAPIVersion(version string) Option {
+	return func(c *Client) {
+		c.Config.ApiVersion = version
+	}
+}
+
+// WithTransport 设置配置读写和监听的传输方式：TransportHTTP 或 TransportGRPC
+func WithTransport(transport string) Option {
+	return func(c *Client) {
+		c.Config.Transport = transport
+	}
+}
+
+// WithEndpoint 从地址服务器获取节点列表，此时不使用 addr 中的地址
+func WithEndpoint(endpoint string) Option {
+	return func(c *Client) {
+		c.Config.Endpoint = endpoint
+	}
+}
+
+// WithCredentials 使用用户名密码登录，token 缓存在 ~/.nacosctl 下并在过期前自动刷新
+func WithCredentials(username, password string) Option {
+	return func(c *Client) {
+		c.Config.Username = username
+		c.Config.Password = password
+	}
+}
+
+// WithToken 使用预先获取的 accessToken
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.Config.Token = token
+	}
+}
+
+// WithAccessKey 使用 AccessKey/SecretKey 对请求签名
+func WithAccessKey(accessKey, secretKey string) Option {
+	return func(c *Client) {
+		c.Config.AccessKey = accessKey
+		c.Config.SecretKey = secretKey
+	}
+}
+
+// WithIdentity 发送服务端配置的身份标识请求头
+func WithIdentity(key, value string) Option {
+	return func(c *Client) {
+		c.Config.IdentityKey = key
+		c.Config.IdentityValue = value
+	}
+}
+
+// WithAuthenticator 使用自定义的认证方式，优先于其他认证配置
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(c *Client) {
+		c.Authenticator = authenticator
+	}
+}
+
+// WithKeyProvider 设置 cipher- 配置的加密密钥
+func WithKeyProvider(provider encrypt.KeyProvider) Option {
+	return func(c *Client) {
+		c.KeyProvider = provider
+	}
+}
+
+// WithServerSideEncryption 声明由服务端加密插件加密 cipher- 配置，未设置密钥时原样发布而不是报错
+func WithServerSideEncryption() Option {
+	return func(c *Client) {
+		c.ServerSideEncryption = true
+	}
+}
+
+// WithRetryPolicy 设置失败重试策略，nil 表示不重试
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *Client) {
+		c.RetryPolicy = policy
+	}
+}
+
+// WithRequestTimeout 设置单次请求（包括重试）的超时时间
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.RequestTimeout = timeout
+	}
+}
+
+// WithHTTPClient 设置发送 HTTP 请求（包括登录和地址服务器查询）的客户端
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = client
+	}
+}
+
+// WithRoundTripper 使用 rt 发送 HTTP 请求，如使用 TraceTransport 记录请求
+func WithRoundTripper(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.HTTPClient = &http.Client{Transport: rt}
+	}
+}
+
+// WithLogger 接收不影响请求结果的警告（如 token 缓存无法加锁或写入）
+func WithLogger(logger func(format string, args ...any)) Option {
+	return func(c *Client) {
+		c.Logger = logger
+	}
+}
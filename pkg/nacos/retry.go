@@ -0,0 +1,132 @@
+package nacos
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// RetryPolicy 请求失败（5xx 或连接错误）时的重试策略。
+// 只重试幂等请求（GET、DELETE）和带 casMd5 的发布请求，
+// 后者即使首次请求已生效，重试也只会因 MD5 不一致而失败，不会覆盖其他人的修改，
+// 此时由 EditContext 重新读取配置，内容与发送的一致时视为发布成功。
+// MD5 不一致（Cas publish fail）是确定的结果，不重试
+type RetryPolicy struct {
+	MaxRetries     int                    // 最大重试次数，0 不重试
+	InitialBackoff time.Duration          // 第一次重试前的等待时间，之后每次翻倍
+	MaxBackoff     time.Duration          // 单次等待时间上限
+	Jitter         float64                // 等待时间随机缩短的比例（0~1），避免多个客户端同时重试
+	Timeout        time.Duration          // 从首次请求开始计算的重试时间上限，0 不限制
+	OnRetry        func(event RetryEvent) // 每次重试前调用，用于输出日志
+}
+
+// RetryEvent 一次重试的信息
+type RetryEvent struct {
+	Method     string
+	URL        string
+	Attempt    int           // 第几次重试，从 1 开始
+	Wait       time.Duration // 重试前的等待时间
+	StatusCode int           // 上一次请求的状态码，连接错误时为 0
+	Err        error         // 上一次请求的连接错误
+}
+
+// DefaultRetryPolicy 默认重试策略：最多重试 2 次，总时长不超过 30 秒
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries:     2,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Jitter:         0.5,
+		Timeout:        30 * time.Second,
+	}
+}
+
+// backoff 计算第 attempt 次重试前的等待时间
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.InitialBackoff
+	for i := 1; i < attempt && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		wait -= time.Duration(rand.Float64() * p.Jitter * float64(wait))
+	}
+	return wait
+}
+
+// retriedKey 请求 context 中记录请求是否经过重试的标志，见 withRetryRecord
+type retriedKey struct{}
+
+// withRetryRecord 返回带重试标志的 context，通过该 context 发送的请求重试时标志被设置
+func withRetryRecord(ctx context.Context) (context.Context, *atomic.Bool) {
+	retried := &atomic.Bool{}
+	return context.WithValue(ctx, retriedKey{}, retried), retried
+}
+
+// retryable 判断请求是否可以安全重试
+func retryable(method string, header http.Header, body []byte) bool {
+	if idempotent(method) {
+		return true
+	}
+	if body == nil || !strings.HasPrefix(header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		return false
+	}
+	form, err := url.ParseQuery(string(body))
+	return err == nil && form.Get("casMd5") != ""
+}
+
+// sendWithRetry 按重试策略发送请求，5xx 和所有节点都连接失败时等待后重试。
+// 每次重试重新获取节点列表，地址服务器模式下所有节点都失败后使用重新查询的列表
+func (c *Client) sendWithRetry(ctx context.Context, method, urlStr string, header http.Header, body []byte) (*http.Response, []byte, *http.Request, error) {
+	policy := c.RetryPolicy
+	if policy == nil || policy.MaxRetries <= 0 || !retryable(method, header, body) {
+		servers, err := c.servers()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return c.sendToServers(ctx, servers, method, urlStr, header, body)
+	}
+
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		servers, err := c.servers()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		resp, data, req, err := c.sendToServers(ctx, servers, method, urlStr, header, body)
+
+		event := RetryEvent{Method: method, URL: urlStr, Attempt: attempt, Err: err}
+		switch {
+		case err != nil && (ctx.Err() != nil || !isConnectionError(err)):
+			return nil, nil, nil, err
+		case err == nil && (resp.StatusCode < http.StatusInternalServerError || casConflict(string(data))):
+			return resp, data, req, nil
+		case err == nil:
+			event.StatusCode = resp.StatusCode
+		}
+
+		event.Wait = policy.backoff(attempt)
+		if attempt > policy.MaxRetries || policy.Timeout > 0 && time.Since(start)+event.Wait > policy.Timeout {
+			return resp, data, req, err
+		}
+		if policy.OnRetry != nil {
+			policy.OnRetry(event)
+		}
+
+		if retried, ok := ctx.Value(retriedKey{}).(*atomic.Bool); ok {
+			retried.Store(true)
+		}
+
+		select {
+		case <-time.After(event.Wait):
+		case <-ctx.Done():
+			return nil, nil, nil, ctx.Err()
+		}
+	}
+}
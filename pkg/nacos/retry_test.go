@@ -0,0 +1,216 @@
+package nacos
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Talbot3/nacos-cli/pkg/util"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.backoff(2))
+	assert.Equal(t, 300*time.Millisecond, policy.backoff(5))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		wait := policy.backoff(2)
+		assert.True(t, wait > 100*time.Millisecond && wait <= 200*time.Millisecond, wait)
+	}
+}
+
+// flakyServer 前 failures 次请求返回 502
+func flakyServer(t *testing.T, failures int32, hits *atomic.Int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) <= failures {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("true"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testRetryPolicy(events *[]RetryEvent) *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries:     2,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		OnRetry: func(event RetryEvent) {
+			*events = append(*events, event)
+		},
+	}
+}
+
+func TestRetryIdempotentRequest(t *testing.T) {
+	var hits atomic.Int32
+	server := flakyServer(t, 2, &hits)
+
+	var events []RetryEvent
+	client := NewClient(server.URL, ApiVersionV1, "", "")
+	client.RetryPolicy = testRetryPolicy(&events)
+
+	detail, err := client.Get(ConfigGetOperation{NacosOperation: &NacosOperation{Group: "G"}, DataId: "app.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, "true", detail.Content)
+	assert.Equal(t, int32(3), hits.Load())
+	require.Len(t, events, 2)
+	assert.Equal(t, http.StatusBadGateway, events[0].StatusCode)
+	assert.Equal(t, 2, events[1].Attempt)
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	var hits atomic.Int32
+	server := flakyServer(t, 10, &hits)
+
+	var events []RetryEvent
+	client := NewClient(server.URL, ApiVersionV1, "", "")
+	client.RetryPolicy = testRetryPolicy(&events)
+
+	err := client.DeleteConfig(ConfigDeleteOperation{NacosOperation: &NacosOperation{Group: "G"}, DataId: "app.yaml"})
+	assert.ErrorContains(t, err, "502")
+	assert.Equal(t, int32(3), hits.Load())
+}
+
+func TestRetryPublishOnlyWithCasMd5(t *testing.T) {
+	tests := []struct {
+		name     string
+		casMd5   string
+		wantErr  bool
+		wantHits int32
+	}{
+		{name: "without casMd5", wantErr: true, wantHits: 1},
+		{name: "with casMd5", casMd5: "0cc175b9c0f1b6a831c399e269772661", wantHits: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits atomic.Int32
+			server := flakyServer(t, 1, &hits)
+
+			var events []RetryEvent
+			client := NewClient(server.URL, ApiVersionV1, "", "")
+			client.RetryPolicy = testRetryPolicy(&events)
+
+			desc := ""
+			err := client.api(context.Background()).publish(context.Background(), publishRequest{
+				NacosOperation: &NacosOperation{Group: "G"},
+				DataId:         "app.yaml",
+				Content:        "a",
+				Metadata:       ConfigMetadata{Desc: &desc, AppName: &desc, Use: &desc, Effect: &desc, Schema: &desc},
+				CasMd5:         tt.casMd5,
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantHits, hits.Load())
+		})
+	}
+}
+
+func TestRetrySkipsCasConflict(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Cas publish fail, server md5 may have changed."))
+	}))
+	defer server.Close()
+
+	var events []RetryEvent
+	client := NewClient(server.URL, ApiVersionV1, "", "")
+	client.RetryPolicy = testRetryPolicy(&events)
+
+	desc := ""
+	err := client.api(context.Background()).publish(context.Background(), publishRequest{
+		NacosOperation: &NacosOperation{Group: "G"},
+		DataId:         "app.yaml",
+		Content:        "a",
+		Metadata:       ConfigMetadata{Desc: &desc, AppName: &desc, Use: &desc, Effect: &desc, Schema: &desc},
+		CasMd5:         "0cc175b9c0f1b6a831c399e269772661",
+	})
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Equal(t, int32(1), hits.Load())
+	assert.Empty(t, events)
+}
+
+func TestRetryPublishCommittedBeforeFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		commit  string // 第一次发布返回 503 前服务器保存的内容
+		wantErr error
+	}{
+		{name: "first attempt committed", commit: "port: 9090"},
+		{name: "modified by someone else", commit: "port: 7070", wantErr: ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu      sync.Mutex
+				content = "port: 8080"
+				posts   int
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				_ = r.ParseForm()
+
+				if r.Method == http.MethodGet {
+					w.Header().Set("Content-Type", "application/json")
+					_ = json.NewEncoder(w).Encode(map[string]string{
+						"dataId": "app.yaml", "group": "G", "content": content, "md5": util.Md5ToString(content),
+					})
+					return
+				}
+
+				posts++
+				if r.PostForm.Get("casMd5") != util.Md5ToString(content) {
+					w.WriteHeader(http.StatusInternalServerError)
+					_, _ = w.Write([]byte("Cas publish fail, server md5 may have changed."))
+					return
+				}
+				if posts == 1 {
+					content = tt.commit
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				content = r.PostForm.Get("content")
+				_, _ = w.Write([]byte("true"))
+			}))
+			defer server.Close()
+
+			var events []RetryEvent
+			client := NewClient(server.URL, ApiVersionV1, "", "")
+			client.RetryPolicy = testRetryPolicy(&events)
+
+			err := client.Edit(ConfigEditOperation{
+				NacosOperation: &NacosOperation{Group: "G"},
+				DataId:         "app.yaml",
+				Content:        "port: 9090",
+				CasMd5:         util.Md5ToString("port: 8080"),
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, 2, posts)
+			require.Len(t, events, 1)
+			assert.Equal(t, http.StatusServiceUnavailable, events[0].StatusCode)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"github.com/Talbot3/nacos-cli/pkg/selector"
+	"sync"
+)
+
+// listConcurrency 并发请求列表分页或配置详情时的最大并发数
+const listConcurrency = 8
+
+// SelectConfig 列出标签满足选择器的配置。
+// 列表接口未返回标签时，并发查询每个配置的详情补全 Tags
+func (c *Client) SelectConfig(operation ConfigGetOperation, sel selector.Selector) ([]NacosPageItem, error) {
+	return c.SelectConfigContext(context.Background(), operation, sel)
+}
+
+// SelectConfigContext 列出标签满足选择器的配置，ctx 取消或超时时中断请求
+func (c *Client) SelectConfigContext(ctx context.Context, operation ConfigGetOperation, sel selector.Selector) ([]NacosPageItem, error) {
+	items, err := c.AllConfigContext(ctx, operation)
+	if err != nil {
+		return nil, err
+	}
+	if len(sel) == 0 {
+		return items, nil
+	}
+
+	withTags := false
+	for _, item := range items {
+		if item.Tags != "" {
+			withTags = true
+			break
+		}
+	}
+
+	if !withTags {
+		err = parallel(len(items), func(i int) error {
+			detail, err := c.detail(ctx, ConfigGetOperation{
+				NacosOperation: &NacosOperation{
+					Namespace: operation.Namespace,
+					Group:     items[i].Group,
+				},
+				DataId: items[i].DataId,
+			})
+			if errors.Is(err, ErrConfigNotExist) {
+				// 列出后被删除
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			items[i].Tags = detail.ConfigTags
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	selected := make([]NacosPageItem, 0, len(items))
+	for _, item := range items {
+		if sel.Matches(selector.Labels(ParseTags(item.Tags))) {
+			selected = append(selected, item)
+		}
+	}
+	return selected, nil
+}
+
+// parallel 以有限并发执行 fn(0..n-1)，返回第一个错误
+func parallel(n int, fn func(i int) error) error {
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+
+	sem := make(chan struct{}, listConcurrency)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(i); err != nil {
+				once.Do(func() { firstErr = err })
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return firstErr
+}
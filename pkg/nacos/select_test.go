@@ -0,0 +1,59 @@
+package nacos
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/selector"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectConfig(t *testing.T) {
+	const total = listPageSize*2 + 3
+	var pageRequests, detailRequests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("show") == "all" {
+			atomic.AddInt32(&detailRequests, 1)
+			tags := "tier=dev"
+			if query.Get("dataId") == "cfg-7" {
+				tags = "team=payments,tier=prod"
+			}
+			_ = json.NewEncoder(w).Encode(NacosConfigDetail{DataID: query.Get("dataId"), ConfigTags: tags})
+			return
+		}
+
+		atomic.AddInt32(&pageRequests, 1)
+		pageNo, _ := strconv.Atoi(query.Get("pageNo"))
+		result := NacosPageResult{TotalCount: total, PageNumber: pageNo, PagesAvailable: 3}
+		for i := (pageNo - 1) * listPageSize; i < pageNo*listPageSize && i < total; i++ {
+			result.PageItems = append(result.PageItems, NacosPageItem{DataId: fmt.Sprintf("cfg-%d", i), Group: "DEFAULT_GROUP"})
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "v1", "", "")
+	operation := ConfigGetOperation{NacosOperation: &NacosOperation{Namespace: "dev"}}
+
+	items, err := client.AllConfig(operation)
+	require.NoError(t, err)
+	assert.Len(t, items, total)
+	assert.Equal(t, "cfg-1002", items[total-1].DataId)
+	assert.EqualValues(t, 3, pageRequests)
+
+	sel, err := selector.Parse("team=payments,tier!=dev")
+	require.NoError(t, err)
+	selected, err := client.SelectConfig(operation, sel)
+	require.NoError(t, err)
+	require.Len(t, selected, 1)
+	assert.Equal(t, "cfg-7", selected[0].DataId)
+	assert.EqualValues(t, total, detailRequests)
+}
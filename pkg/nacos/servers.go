@@ -0,0 +1,237 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultContextPath    = "/nacos"
+	defaultServerPort     = "8848"
+	defaultEndpointPort   = "8080"
+	endpointServerList    = "/nacos/serverlist"
+	serverDownDuration    = 30 * time.Second // 连接失败的节点在此期间内优先跳过
+	endpointLookupTimeout = 5 * time.Second
+	endpointRefresh       = 30 * time.Second // 地址服务器模式下重新查询节点列表的间隔
+)
+
+// serverList Nacos 集群节点列表，轮询选择节点，连接失败的节点暂时排到最后
+type serverList struct {
+	source   string    // 生成列表的 Addr 和 Endpoint，配置变化时重新解析
+	resolved time.Time // 从地址服务器查询到列表的时间，直接使用 Addr 时为零值
+	addrs    []string
+	next     atomic.Uint32
+
+	mu   sync.Mutex
+	down map[string]time.Time
+}
+
+// splitAddrs 解析逗号分隔的地址列表，没有协议的地址使用 http://
+func splitAddrs(addr string) []string {
+	var addrs []string
+	for _, a := range strings.Split(addr, ",") {
+		a = strings.TrimRight(strings.TrimSpace(a), "/")
+		if a == "" {
+			continue
+		}
+		if !strings.Contains(a, "://") {
+			a = "http://" + a
+		}
+		addrs = append(addrs, a)
+	}
+	return addrs
+}
+
+// serverPath 构造与节点无关的请求路径（如 /v1/cs/configs），发送时拼接到选中节点的地址后
+func serverPath(elem ...string) (string, error) {
+	return url.JoinPath("/", elem...)
+}
+
+// nodeURL 将请求路径拼接到节点地址后，节点地址包含上下文路径（如 http://10.0.0.1:8848/nacos）
+func nodeURL(addr, path string) string {
+	return strings.TrimRight(addr, "/") + path
+}
+
+// serverSource 节点列表的来源，Addr 或 Endpoint 变化后需要重新解析
+func serverSource(config *NacosConfig) string {
+	return config.Addr + "\x00" + config.Endpoint
+}
+
+// resolveServers 解析配置对应的节点列表：配置了 Endpoint 时使用 httpClient 从地址服务器查询，否则使用 Addr 中的地址
+func resolveServers(config *NacosConfig, httpClient *http.Client) (*serverList, error) {
+	list := &serverList{source: serverSource(config), addrs: splitAddrs(config.Addr), down: map[string]time.Time{}}
+	if config.Endpoint != "" {
+		addrs, err := lookupEndpoint(config.Endpoint, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		list.addrs, list.resolved = addrs, time.Now()
+	}
+	if len(list.addrs) == 0 {
+		return nil, errors.New("nacos address is required")
+	}
+	return list, nil
+}
+
+// servers 获取客户端的节点列表，节点健康状态在同一客户端的请求间共享。
+// 地址服务器模式下列表超过 endpointRefresh 或所有节点都连接失败时重新查询，查询失败时继续使用原列表
+func (c *Client) servers() (*serverList, error) {
+	c.serversMu.Lock()
+	defer c.serversMu.Unlock()
+
+	current := c.serverList
+	if current != nil && current.source == serverSource(c.Config) && !current.stale() {
+		return current, nil
+	}
+
+	list, err := resolveServers(c.Config, c.httpClient())
+	if err != nil {
+		if current == nil || current.source != serverSource(c.Config) {
+			return nil, err
+		}
+		c.warnf("failed to refresh nacos servers, using previous list: %v", err)
+		current.resolved = time.Now()
+		return current, nil
+	}
+	c.serverList = list
+	return list, nil
+}
+
+// lookupEndpoint 从地址服务器获取节点列表（与官方 SDK 的 endpoint 模式兼容），
+// 响应每行一个 ip:port，未指定端口时使用 8848
+func lookupEndpoint(endpoint string, httpClient *http.Client) ([]string, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), defaultEndpointPort)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = endpointServerList
+	}
+
+	client := *httpClient
+	if client.Timeout == 0 {
+		client.Timeout = endpointLookupTimeout
+	}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return nil, fmt.Errorf("lookup nacos servers from %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("lookup nacos servers from %s: status code %d\n%s", u, resp.StatusCode, body)
+	}
+
+	var addrs []string
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(line); err != nil {
+			line = net.JoinHostPort(line, defaultServerPort)
+		}
+		addrs = append(addrs, "http://"+line+defaultContextPath)
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("lookup nacos servers from %s: empty server list", u)
+	}
+	return addrs, nil
+}
+
+// pick 返回本次请求依次尝试的节点：从轮询位置开始，健康节点在前，近期连接失败的节点在后
+func (l *serverList) pick() []string {
+	start := int(l.next.Add(1)-1) % len(l.addrs)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	healthy := make([]string, 0, len(l.addrs))
+	var down []string
+	for i := range l.addrs {
+		addr := l.addrs[(start+i)%len(l.addrs)]
+		if l.down[addr].After(now) {
+			down = append(down, addr)
+		} else {
+			healthy = append(healthy, addr)
+		}
+	}
+	return append(healthy, down...)
+}
+
+// stale 判断从地址服务器查询的列表是否需要重新查询：超过 endpointRefresh 或所有节点都连接失败
+func (l *serverList) stale() bool {
+	if l.resolved.IsZero() {
+		return false
+	}
+	if time.Since(l.resolved) > endpointRefresh {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	for _, addr := range l.addrs {
+		if !l.down[addr].After(now) {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *serverList) markDown(addr string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.down[addr] = time.Now().Add(serverDownDuration)
+}
+
+func (l *serverList) markUp(addr string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.down, addr)
+}
+
+// isConnectionError 判断是否为连接失败、连接被重置等网络错误，这类错误换一个节点重试
+func isConnectionError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// isDialError 判断请求是否在建立连接时失败，此时服务端未收到请求，任何方法都可以安全重试
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// idempotent 判断请求方法是否幂等，幂等请求在连接中断后可以换节点重试
+func idempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodDelete
+}
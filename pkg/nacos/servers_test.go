@@ -0,0 +1,168 @@
+package nacos
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deadAddr 返回一个没有监听的地址
+func deadAddr(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close())
+	return "http://" + addr + "/nacos"
+}
+
+func countingServer(t *testing.T, hits *atomic.Int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("a: 1"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSplitAddrs(t *testing.T) {
+	assert.Equal(t, []string{"http://a:8848/nacos", "https://b/nacos", "http://c:8848"},
+		splitAddrs(" http://a:8848/nacos/ ,https://b/nacos,,c:8848"))
+}
+
+func TestSendFailover(t *testing.T) {
+	var hits atomic.Int32
+	server := countingServer(t, &hits)
+	dead := deadAddr(t)
+
+	client := NewClient(dead+","+server.URL, ApiVersionV1, "", "")
+	get := ConfigGetOperation{NacosOperation: &NacosOperation{Group: "G"}, DataId: "app.yaml"}
+
+	for i := 0; i < 4; i++ {
+		detail, err := client.Get(get)
+		require.NoError(t, err)
+		assert.Equal(t, "a: 1", detail.Content)
+	}
+	assert.Equal(t, int32(4), hits.Load())
+
+	// 连接失败的节点排到最后
+	servers, err := client.servers()
+	require.NoError(t, err)
+	assert.Equal(t, []string{server.URL, dead}, servers.pick())
+}
+
+func TestSendRoundRobin(t *testing.T) {
+	var hitsA, hitsB atomic.Int32
+	a := countingServer(t, &hitsA)
+	b := countingServer(t, &hitsB)
+
+	client := NewClient(a.URL+","+b.URL, ApiVersionV1, "", "")
+	for i := 0; i < 4; i++ {
+		_, err := client.Get(ConfigGetOperation{NacosOperation: &NacosOperation{Group: "G"}, DataId: "app.yaml"})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), hitsA.Load())
+	assert.Equal(t, int32(2), hitsB.Load())
+}
+
+func TestEndpointLookup(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		assert.Equal(t, "/nacos/v1/cs/configs", r.URL.Path)
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("a: 1"))
+	}))
+	defer server.Close()
+
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, endpointServerList, r.URL.Path)
+		_, _ = w.Write([]byte(strings.TrimPrefix(server.URL, "http://") + "\n"))
+	}))
+	defer endpoint.Close()
+
+	addrs, err := lookupEndpoint(endpoint.URL, http.DefaultClient)
+	require.NoError(t, err)
+	assert.Equal(t, []string{server.URL + "/nacos"}, addrs)
+
+	// 只配置地址服务器，不配置 addr
+	client := NewClient("", ApiVersionV1, "", "")
+	client.Config.Endpoint = endpoint.URL
+	detail, err := client.Get(ConfigGetOperation{NacosOperation: &NacosOperation{Group: "G"}, DataId: "app.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, "a: 1", detail.Content)
+	assert.Equal(t, int32(1), hits.Load())
+}
+
+func TestServerListPerClient(t *testing.T) {
+	var hits atomic.Int32
+	server := countingServer(t, &hits)
+	dead := deadAddr(t)
+	addr := dead + "," + server.URL
+	get := ConfigGetOperation{NacosOperation: &NacosOperation{Group: "G"}, DataId: "app.yaml"}
+
+	first := NewClient(addr, ApiVersionV1, "", "")
+	_, err := first.Get(get)
+	require.NoError(t, err)
+
+	// 其他客户端不受第一个客户端记录的节点状态影响
+	second := NewClient(addr, ApiVersionV1, "", "")
+	servers, err := second.servers()
+	require.NoError(t, err)
+	assert.NotSame(t, servers, mustServers(t, first))
+	assert.Contains(t, mustServers(t, first).down, dead)
+	assert.Empty(t, servers.down)
+}
+
+func TestEndpointRefresh(t *testing.T) {
+	var hits atomic.Int32
+	server := countingServer(t, &hits)
+	dead := deadAddr(t)
+
+	var lookups atomic.Int32
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 第一次返回已下线的节点，之后返回新节点
+		if lookups.Add(1) == 1 {
+			_, _ = w.Write([]byte(strings.TrimSuffix(strings.TrimPrefix(dead, "http://"), "/nacos") + "\n"))
+			return
+		}
+		_, _ = w.Write([]byte(strings.TrimPrefix(server.URL, "http://") + "\n"))
+	}))
+	defer endpoint.Close()
+
+	client := NewClient("", ApiVersionV1, "", "")
+	client.Config.Endpoint = endpoint.URL
+	client.RetryPolicy = nil
+	get := ConfigGetOperation{NacosOperation: &NacosOperation{Group: "G"}, DataId: "app.yaml"}
+
+	// 所有节点都连接失败后重新查询地址服务器
+	_, err := client.Get(get)
+	assert.Error(t, err)
+	detail, err := client.Get(get)
+	require.NoError(t, err)
+	assert.Equal(t, "a: 1", detail.Content)
+	assert.Equal(t, int32(2), lookups.Load())
+
+	// 节点正常时在刷新间隔内不重新查询，超过间隔后重新查询
+	_, err = client.Get(get)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), lookups.Load())
+
+	mustServers(t, client).resolved = time.Now().Add(-endpointRefresh - time.Second)
+	_, err = client.Get(get)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), lookups.Load())
+}
+
+func mustServers(t *testing.T, client *Client) *serverList {
+	servers, err := client.servers()
+	require.NoError(t, err)
+	return servers
+}
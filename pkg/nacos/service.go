@@ -0,0 +1,26 @@
+package nacos
+
+import "context"
+
+// ConfigService 配置管理接口。*Client 实现了该接口，依赖它的代码可以在测试中替换为 mock
+type ConfigService interface {
+	GetContext(ctx context.Context, operation ConfigGetOperation) (*NacosConfigDetail, error)
+	DetailContext(ctx context.Context, operation ConfigGetOperation) (*NacosConfigDetail, error)
+	AllConfigContext(ctx context.Context, operation ConfigGetOperation) ([]NacosPageItem, error)
+	EditContext(ctx context.Context, operation ConfigEditOperation) error
+	DeleteConfigContext(ctx context.Context, operation ConfigDeleteOperation) error
+	Watch(ctx context.Context, keys ...ConfigKey) (<-chan ConfigChangeEvent, error)
+}
+
+// NamingService 服务发现接口。*Client 实现了该接口，依赖它的代码可以在测试中替换为 mock
+type NamingService interface {
+	ListServicesContext(ctx context.Context, operation ServiceListOperation) ([]string, error)
+	ListInstancesContext(ctx context.Context, operation InstanceListOperation) ([]Instance, error)
+	RegisterInstanceContext(ctx context.Context, operation InstanceRegisterOperation) error
+	DeregisterInstanceContext(ctx context.Context, operation InstanceDeregisterOperation) error
+}
+
+var (
+	_ ConfigService = (*Client)(nil)
+	_ NamingService = (*Client)(nil)
+)
@@ -0,0 +1,182 @@
+package nacos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const redacted = "***"
+
+// 输出时隐藏值的请求头、查询参数和表单字段
+var (
+	sensitiveHeaders = map[string]bool{
+		http.CanonicalHeaderKey(authHeader):      true,
+		http.CanonicalHeaderKey(signatureHeader): true,
+		http.CanonicalHeaderKey("accessToken"):   true,
+	}
+	sensitiveParams = []string{"accessToken", "password"}
+
+	// 登录接口返回的 token
+	tokenPattern = regexp.MustCompile(`("accessToken"\s*:\s*)"[^"]*"`)
+)
+
+// authHeadersKey 请求 context 中记录 Authenticator 设置的请求头名称
+type authHeadersKey struct{}
+
+// withAuthHeaders 记录 Authenticator 相对 before 新增或修改的请求头，
+// 如 IdentityAuthenticator 使用的自定义请求头，TraceTransport 输出时隐藏这些请求头的值
+func withAuthHeaders(req *http.Request, before http.Header) *http.Request {
+	names := map[string]bool{}
+	for key, values := range req.Header {
+		if strings.Join(values, "\x00") != strings.Join(before[key], "\x00") {
+			names[http.CanonicalHeaderKey(key)] = true
+		}
+	}
+	if len(names) == 0 {
+		return req
+	}
+	return req.WithContext(context.WithValue(req.Context(), authHeadersKey{}, names))
+}
+
+// TraceTransport 将每个请求和响应输出到 Out（通常为 stderr），用于排查问题。
+// Level 1 输出请求方法、URL、状态码和耗时，2 增加请求头和响应头，3 增加请求体和响应体。
+// Authorization、签名请求头和 Authenticator 设置的其他请求头，accessToken 参数，登录密码和登录返回的 token 输出为 ***
+type TraceTransport struct {
+	Base  http.RoundTripper // 为 nil 时使用 http.DefaultTransport
+	Out   io.Writer
+	Level int
+
+	mu sync.Mutex // 并发请求的输出不交错
+}
+
+func (t *TraceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s\n", req.Method, redactURL(req.URL))
+	if t.Level >= 2 {
+		authHeaders, _ := req.Context().Value(authHeadersKey{}).(map[string]bool)
+		writeHeader(&buf, "> ", req.Header, authHeaders)
+	}
+	if t.Level >= 3 && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			body.Close()
+			writeBody(&buf, "> ", redactBody(req.Header.Get("Content-Type"), data))
+		}
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(&buf, "< %v (%s)\n", err, elapsed)
+		t.write(buf.Bytes())
+		return nil, err
+	}
+
+	fmt.Fprintf(&buf, "< %s (%s)\n", resp.Status, elapsed)
+	if t.Level >= 2 {
+		writeHeader(&buf, "< ", resp.Header, nil)
+	}
+	if t.Level >= 3 {
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			fmt.Fprintf(&buf, "< %v\n", err)
+			t.write(buf.Bytes())
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		writeBody(&buf, "< ", redactBody(resp.Header.Get("Content-Type"), data))
+	}
+
+	t.write(buf.Bytes())
+	return resp, nil
+}
+
+func (t *TraceTransport) write(data []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = t.Out.Write(data)
+}
+
+// writeHeader 按名称排序输出请求头，敏感请求头和 hidden 中的请求头的值输出为 ***
+func writeHeader(w io.Writer, prefix string, header http.Header, hidden map[string]bool) {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range header[key] {
+			if name := http.CanonicalHeaderKey(key); sensitiveHeaders[name] || hidden[name] {
+				value = redacted
+			}
+			fmt.Fprintf(w, "%s%s: %s\n", prefix, key, value)
+		}
+	}
+}
+
+func writeBody(w io.Writer, prefix, body string) {
+	if body == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(body, "\n"), "\n") {
+		fmt.Fprintf(w, "%s%s\n", prefix, line)
+	}
+}
+
+// redactURL 隐藏 URL 中的 accessToken 参数和用户密码
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	if !redactValues(query) {
+		return u.Redacted()
+	}
+	redactedURL := *u
+	redactedURL.RawQuery = encodeRedacted(query)
+	return redactedURL.Redacted()
+}
+
+// redactBody 隐藏表单中的 password、accessToken 字段和 JSON 中的 accessToken
+func redactBody(contentType string, body []byte) string {
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			if redactValues(form) {
+				return encodeRedacted(form)
+			}
+			return string(body)
+		}
+	}
+	return tokenPattern.ReplaceAllString(string(body), `$1"`+redacted+`"`)
+}
+
+// encodeRedacted 编码参数，*** 不转义以便阅读
+func encodeRedacted(values url.Values) string {
+	return strings.ReplaceAll(values.Encode(), url.QueryEscape(redacted), redacted)
+}
+
+// redactValues 将敏感参数的值替换为 ***，返回是否有替换
+func redactValues(values url.Values) bool {
+	changed := false
+	for _, key := range sensitiveParams {
+		if _, ok := values[key]; ok {
+			values.Set(key, redacted)
+			changed = true
+		}
+	}
+	return changed
+}
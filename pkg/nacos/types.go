@@ -1,10 +1,20 @@
 package nacos
 
+import "github.com/Talbot3/nacos-cli/pkg/secret"
+
 type NacosConfig struct {
 	Addr       string `json:"addr" yaml:"addr"`
 	Username   string `json:"username" yaml:"username"`
 	Password   string `json:"password" yaml:"password"`
 	ApiVersion string `json:"apiVersion" yaml:"apiVersion"`
+	Transport  string `json:"transport,omitempty" yaml:"transport,omitempty"` // http（默认）或 grpc
+	Endpoint   string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`   // 地址服务器，配置后从中获取节点列表
+
+	Token         string `json:"token,omitempty" yaml:"token,omitempty"`                 // 预先获取的 accessToken
+	AccessKey     string `json:"accessKey,omitempty" yaml:"accessKey,omitempty"`         // AK/SK 签名认证
+	SecretKey     string `json:"secretKey,omitempty" yaml:"secretKey,omitempty"`         // AK/SK 签名认证
+	IdentityKey   string `json:"identityKey,omitempty" yaml:"identityKey,omitempty"`     // 服务端身份标识请求头
+	IdentityValue string `json:"identityValue,omitempty" yaml:"identityValue,omitempty"` // 服务端身份标识的值
 }
 
 type NacosOperation struct {
@@ -12,12 +22,28 @@ type NacosOperation struct {
 	Group     string // 组
 }
 
+// ConfigMetadata 配置元数据，nil 字段表示保留服务器上的当前值
+type ConfigMetadata struct {
+	Desc    *string  // 描述
+	Tags    []string // 标签 (config_tags)
+	AppName *string  // 所属应用
+	Use     *string  // 用途
+	Effect  *string  // 影响
+	Schema  *string  // 约束
+}
+
 // ConfigEditOperation 配置更新操作
 type ConfigEditOperation struct {
 	*NacosOperation
-	Content string // 配置内容
-	DataId  string // data-id
-	Type    string // 文件类型
+	ConfigMetadata
+	Content      string   // 配置内容
+	DataId       string   // data-id
+	Type         string   // 文件类型
+	BetaIps      []string // 灰度发布的客户端 IP，为空时正式发布
+	SecretValues []string // 内容中由密钥引用解析出的值，输出时用于脱敏
+	CasMd5       string   // 非空时只有服务器上内容的 MD5 与之相同才发布，避免覆盖他人的修改
+
+	EncryptedDataKey string // Content 已加密时的数据密钥（如复制未解密的配置），内容原样发布不再加密
 }
 
 // ConfigGetOperation 配置查询操作
@@ -29,9 +55,20 @@ type ConfigGetOperation struct {
 // ConfigApplyOperation 配置应用操作
 type ConfigApplyOperation struct {
 	*NacosOperation
-	File   string // 配置文件
+	ConfigMetadata
+	File    string                 // 配置文件
+	DataId  string                 // data-id
+	Type    string                 // 文件类型
+	BetaIps []string               // 灰度发布的客户端 IP，为空时正式发布
+	Values  map[string]interface{} // 模板变量，非 nil 时按模板渲染文件
+	Secrets *secret.Resolver       // 展开 ${scheme:ref} 密钥引用，nil 时使用 secret.DefaultResolver()
+}
+
+// ConfigMetadataOperation 只更新元数据的操作，配置内容保持不变
+type ConfigMetadataOperation struct {
+	*NacosOperation
+	ConfigMetadata
 	DataId string // data-id
-	Type   string // 文件类型
 }
 
 // ConfigDeleteOperation 配置删除操作
@@ -40,21 +77,49 @@ type ConfigDeleteOperation struct {
 	DataId string // data-id
 }
 
+// ConfigExportOperation 配置导出操作，Group 为空时导出命名空间下的所有分组
+type ConfigExportOperation struct {
+	*NacosOperation
+	AppName string   // 按应用名过滤
+	Ids     []string // 只导出指定 id 的配置
+}
+
+// ImportPolicy 导入时遇到同名配置的处理策略
+type ImportPolicy string
+
+const (
+	ImportPolicyAbort     ImportPolicy = "ABORT"     // 遇到冲突时终止导入
+	ImportPolicySkip      ImportPolicy = "SKIP"      // 跳过已存在的配置
+	ImportPolicyOverwrite ImportPolicy = "OVERWRITE" // 覆盖已存在的配置
+)
+
+// ConfigImportOperation 配置导入操作
+type ConfigImportOperation struct {
+	*NacosOperation
+	Items  []ConfigArchiveItem // 待导入的配置
+	Policy ImportPolicy        // 冲突处理策略，默认 ABORT
+}
+
 var DefaultNacosOperation = NacosOperation{
 	Namespace: "public",
 	Group:     "DEFAULT_GROUP",
 }
 
 type NacosPageResult struct {
-	PageItems []NacosPageItem `json:"pageItems"`
+	TotalCount     int             `json:"totalCount"`
+	PageNumber     int             `json:"pageNumber"`
+	PagesAvailable int             `json:"pagesAvailable"`
+	PageItems      []NacosPageItem `json:"pageItems"`
 }
 
 type NacosPageItem struct {
 	Id     string `json:"id"`
 	DataId string `json:"dataId"`
 	Group  string `json:"group"`
-	Type   string `json:"type"`   // 文件类型
-	Tenant string `json:"tenant"` // 命名空间
+	Type   string `json:"type"`       // 文件类型
+	Tenant string `json:"tenant"`     // 命名空间
+	Md5    string `json:"md5"`        // 内容 MD5
+	Tags   string `json:"configTags"` // 逗号分隔的标签，列表接口未返回时为空
 }
 
 // NacosConfigDetail nacos配置结构体
@@ -76,6 +141,85 @@ type NacosConfigDetail struct {
 	Use              string `json:"use"`
 	Effect           string `json:"effect"`
 	Schema           string `json:"schema"`
+	ConfigTags       string `json:"configTags"` // 逗号分隔的标签
+}
+
+// restResult Nacos 接口通用的 {code,message,data} 响应结构
+type restResult[T any] struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    T      `json:"data"`
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	SuccCount int                `json:"succCount"`
+	SkipCount int                `json:"skipCount"`
+	SkipData  []ImportResultItem `json:"skipData"`
+	FailData  []ImportResultItem `json:"failData"`
+}
+
+// ImportResultItem 导入结果中被跳过或失败的配置
+type ImportResultItem struct {
+	DataId string `json:"dataId"`
+	Group  string `json:"group"`
+}
+
+// NacosConfigBeta 灰度发布中的配置
+type NacosConfigBeta struct {
+	NacosConfigDetail
+	BetaIps string `json:"betaIps"` // 逗号分隔的灰度 IP
+}
+
+// ServiceListOperation 服务列表查询操作，Group 为空时为 DEFAULT_GROUP
+type ServiceListOperation struct {
+	*NacosOperation
+}
+
+// InstanceListOperation 服务实例查询操作
+type InstanceListOperation struct {
+	*NacosOperation
+	ServiceName string
+	Clusters    []string // 只查询指定集群的实例，为空时查询所有集群
+	HealthyOnly bool     // 只返回健康的实例
+}
+
+// InstanceRegisterOperation 注册实例操作。
+// 默认注册持久实例；临时实例（Ephemeral）需要调用方定时发送心跳，否则会被服务端剔除
+type InstanceRegisterOperation struct {
+	*NacosOperation
+	ServiceName string
+	Ip          string
+	Port        int
+	ClusterName string            // 为空时为 DEFAULT
+	Weight      float64           // 为 0 时使用 1
+	Disabled    bool              // 注册为下线状态，不接收流量
+	Ephemeral   bool              // 注册为临时实例
+	Metadata    map[string]string // 实例元数据
+}
+
+// InstanceDeregisterOperation 注销实例操作，Ephemeral 需与注册时一致
+type InstanceDeregisterOperation struct {
+	*NacosOperation
+	ServiceName string
+	Ip          string
+	Port        int
+	ClusterName string
+	Ephemeral   bool
+}
+
+// Instance 服务实例
+type Instance struct {
+	InstanceId  string            `json:"instanceId"`
+	Ip          string            `json:"ip"`
+	Port        int               `json:"port"`
+	Weight      float64           `json:"weight"`
+	Healthy     bool              `json:"healthy"`
+	Enabled     bool              `json:"enabled"`
+	Ephemeral   bool              `json:"ephemeral"`
+	ClusterName string            `json:"clusterName"`
+	ServiceName string            `json:"serviceName"` // 带分组前缀，如 DEFAULT_GROUP@@order
+	Metadata    map[string]string `json:"metadata"`
 }
 
 // AuthResponse 登录响应
@@ -94,6 +238,7 @@ type AuthRequest struct {
 // TokenCache token缓存
 type TokenCache struct {
 	AccessToken string `json:"accessToken"`
-	ExpireTime  int64  `json:"expireTime"` // 过期时间戳(秒)
+	ExpireTime  int64  `json:"expireTime"`  // 过期时间戳(秒)
 	Username    string `json:"username"`    // 缓存时使用的用户名
+	GlobalAdmin bool   `json:"globalAdmin"` // 是否为管理员
 }
@@ -0,0 +1,298 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Talbot3/nacos-cli/pkg/util"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	listenerPath        = "listener"
+	longPollingTimeout  = 30 * time.Second // 服务端挂起长轮询请求的时长
+	watchRetryInterval  = 3 * time.Second  // 监听出错后的重试间隔
+	watchFetchErrors    = 3                // 变更后拉取配置连续失败该次数后发送错误事件
+	wordSeparator       = "\x02"
+	lineSeparator       = "\x01"
+	longPollingHeader   = "Long-Pulling-Timeout"
+	listeningConfigsKey = "Listening-Configs"
+)
+
+// watchBackoff 拉取配置失败后再次监听前的等待时间，连续失败时翻倍，避免服务端出错时反复请求
+var watchBackoff = &RetryPolicy{InitialBackoff: watchRetryInterval, MaxBackoff: time.Minute, Jitter: 0.2}
+
+// ConfigKey 唯一标识一个配置
+type ConfigKey struct {
+	Namespace string
+	Group     string
+	DataId    string
+}
+
+func (k ConfigKey) String() string {
+	namespace := k.Namespace
+	if namespace == "" {
+		namespace = "public"
+	}
+	return fmt.Sprintf("%s/%s/%s", namespace, k.Group, k.DataId)
+}
+
+// ConfigChangeEvent 配置变更事件
+type ConfigChangeEvent struct {
+	Key      ConfigKey
+	Config   *NacosConfigDetail // 变更后的配置，配置不存在或被删除时为 nil
+	Previous *NacosConfigDetail // 变更前的配置，首次事件或此前不存在时为 nil
+	Err      error              // 监听或拉取配置出错，此时其他字段仅 Key 有效（监听出错时 Key 为空）
+}
+
+// Watch 通过长轮询（/cs/configs/listener）或 gRPC 的 ConfigBatchListen 监听配置变更。
+// 返回的 channel 首先为每个配置发送一次当前状态，之后每次内容变化发送一个事件；
+// 出错时发送带 Err 的事件并在稍后重试，变更后拉取配置偶发失败时静默重试，连续失败时才发送错误事件。
+// ctx 取消后 channel 被关闭。
+func (c *Client) Watch(ctx context.Context, keys ...ConfigKey) (<-chan ConfigChangeEvent, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one config key is required")
+	}
+
+	var listen func(ctx context.Context, keys []ConfigKey, states map[ConfigKey]*NacosConfigDetail) ([]ConfigKey, error)
+	if listener, ok := c.api(ctx).(configListener); ok {
+		listen = listener.listen
+	} else {
+		// v3 没有长轮询接口，只能通过 gRPC 监听
+		configUrl, err := c.v1Url(ctx, "watch over HTTP (use --transport grpc)")
+		if err != nil {
+			return nil, err
+		}
+		listenerUrl, err := url.JoinPath(configUrl, listenerPath)
+		if err != nil {
+			return nil, err
+		}
+		listen = func(ctx context.Context, keys []ConfigKey, states map[ConfigKey]*NacosConfigDetail) ([]ConfigKey, error) {
+			return c.listen(ctx, listenerUrl, keys, states)
+		}
+	}
+
+	events := make(chan ConfigChangeEvent)
+
+	go func() {
+		defer close(events)
+
+		emit := func(event ConfigChangeEvent) bool {
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		wait := func(d time.Duration) bool {
+			select {
+			case <-time.After(d):
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// failures 每个配置连续拉取失败的次数。拉取失败的配置没有保存状态（MD5 为空），
+		// 服务端会立即再次通知变更，需要等待后再监听
+		failures := make(map[ConfigKey]int)
+		states := make(map[ConfigKey]*NacosConfigDetail, len(keys))
+		for _, key := range keys {
+			detail, err := c.fetchConfig(ctx, key)
+			if err != nil {
+				failures[key]++
+				if ctx.Err() != nil || !emit(ConfigChangeEvent{Key: key, Err: err}) {
+					return
+				}
+				continue
+			}
+			states[key] = detail
+			if !emit(c.changeEvent(key, detail, nil)) {
+				return
+			}
+		}
+
+		for ctx.Err() == nil {
+			if attempt := maxFailures(failures); attempt > 0 && !wait(watchBackoff.backoff(attempt)) {
+				return
+			}
+
+			changed, err := listen(ctx, keys, states)
+			if err != nil {
+				if ctx.Err() != nil || !emit(ConfigChangeEvent{Err: err}) || !wait(watchRetryInterval) {
+					return
+				}
+				continue
+			}
+
+			// 未再通知变更的配置与服务端一致，不再等待
+			for key := range failures {
+				if !containsKey(changed, key) {
+					delete(failures, key)
+				}
+			}
+
+			for _, key := range changed {
+				detail, err := c.fetchConfig(ctx, key)
+				if err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					// 偶发的失败在下一轮重试，连续失败时通知调用方
+					failures[key]++
+					if failures[key] >= watchFetchErrors && !emit(ConfigChangeEvent{Key: key, Err: err}) {
+						return
+					}
+					continue
+				}
+				delete(failures, key)
+
+				previous := states[key]
+				if configMd5(previous) == configMd5(detail) {
+					continue
+				}
+				states[key] = detail
+				if !emit(c.changeEvent(key, detail, previous)) {
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// maxFailures 返回配置连续拉取失败次数的最大值
+func maxFailures(failures map[ConfigKey]int) int {
+	max := 0
+	for _, n := range failures {
+		if n > max {
+			max = n
+		}
+	}
+	return max
+}
+
+func containsKey(keys []ConfigKey, key ConfigKey) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// changeEvent 构造变更事件，加密的配置解密后发送。
+// states 中保存原始内容，以便与服务端 MD5 比较
+func (c *Client) changeEvent(key ConfigKey, config, previous *NacosConfigDetail) ConfigChangeEvent {
+	config, err := c.decrypt(config)
+	if err != nil {
+		return ConfigChangeEvent{Key: key, Err: err}
+	}
+	previous, err = c.decrypt(previous)
+	if err != nil {
+		return ConfigChangeEvent{Key: key, Err: err}
+	}
+	return ConfigChangeEvent{Key: key, Config: config, Previous: previous}
+}
+
+// listen 发起一次长轮询，返回内容发生变化的配置
+func (c *Client) listen(ctx context.Context, listenerUrl string, keys []ConfigKey, states map[ConfigKey]*NacosConfigDetail) ([]ConfigKey, error) {
+	var sb strings.Builder
+	for _, key := range keys {
+		sb.WriteString(key.DataId)
+		sb.WriteString(wordSeparator)
+		sb.WriteString(key.Group)
+		sb.WriteString(wordSeparator)
+		sb.WriteString(configMd5(states[key]))
+		if tenant := tenantOf(key.Namespace); tenant != "" {
+			sb.WriteString(wordSeparator)
+			sb.WriteString(tenant)
+		}
+		sb.WriteString(lineSeparator)
+	}
+
+	header := http.Header{}
+	header.Set(longPollingHeader, fmt.Sprint(longPollingTimeout.Milliseconds()))
+
+	resp, body, err := c.sendForm(ctx, http.MethodPost, listenerUrl, header, url.Values{
+		listeningConfigsKey: []string{sb.String()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("response error,status code:%d\n%s", resp.StatusCode, body)
+	}
+
+	return parseChangedKeys(string(body), keys)
+}
+
+// parseChangedKeys 解析监听接口的响应：URL 编码的 dataId^2group^2tenant^1 列表
+func parseChangedKeys(body string, keys []ConfigKey) ([]ConfigKey, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, nil
+	}
+
+	decoded, err := url.QueryUnescape(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []ConfigKey
+	for _, line := range strings.Split(decoded, lineSeparator) {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, wordSeparator)
+		if len(fields) < 2 {
+			continue
+		}
+		tenant := ""
+		if len(fields) > 2 {
+			tenant = fields[2]
+		}
+		changed = append(changed, matchKeys(keys, fields[0], fields[1], tenant)...)
+	}
+	return changed, nil
+}
+
+// matchKeys 返回与服务端通知的 dataId、group、tenant 对应的监听配置
+func matchKeys(keys []ConfigKey, dataId, group, tenant string) []ConfigKey {
+	var matched []ConfigKey
+	for _, key := range keys {
+		if key.DataId == dataId && key.Group == group && tenantOf(key.Namespace) == tenantOf(tenant) {
+			matched = append(matched, key)
+		}
+	}
+	return matched
+}
+
+// fetchConfig 获取配置当前内容，配置不存在时返回 nil
+func (c *Client) fetchConfig(ctx context.Context, key ConfigKey) (*NacosConfigDetail, error) {
+	detail, err := c.get(ctx, ConfigGetOperation{
+		NacosOperation: &NacosOperation{
+			Namespace: key.Namespace,
+			Group:     key.Group,
+		},
+		DataId: key.DataId,
+	})
+	if errors.Is(err, ErrConfigNotExist) {
+		return nil, nil
+	}
+	return detail, err
+}
+
+// configMd5 计算配置内容的 MD5，与服务端监听比较时使用；配置不存在时为空字符串
+func configMd5(detail *NacosConfigDetail) string {
+	if detail == nil {
+		return ""
+	}
+	return util.Md5ToString(detail.Content)
+}
@@ -0,0 +1,150 @@
+package nacos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChangedKeys(t *testing.T) {
+	keys := []ConfigKey{
+		{Namespace: "public", Group: "DEFAULT_GROUP", DataId: "app.yaml"},
+		{Namespace: "dev", Group: "DEFAULT_GROUP", DataId: "app.yaml"},
+	}
+
+	body := url.QueryEscape("app.yaml\x02DEFAULT_GROUP\x02dev\x01")
+	changed, err := parseChangedKeys(body, keys)
+	require.NoError(t, err)
+	assert.Equal(t, []ConfigKey{keys[1]}, changed)
+
+	changed, err = parseChangedKeys("", keys)
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+}
+
+func TestWatch(t *testing.T) {
+	var mu sync.Mutex
+	content := "v1"
+	changes := make(chan struct{}, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/nacos/v1/cs/configs":
+			mu.Lock()
+			defer mu.Unlock()
+			w.Header().Set("Content-Type", "text/plain;charset=UTF-8")
+			_, _ = w.Write([]byte(content))
+		case "/nacos/v1/cs/configs/listener":
+			assert.Equal(t, "30000", r.Header.Get(longPollingHeader))
+			select {
+			case <-changes:
+				_, _ = w.Write([]byte(url.QueryEscape("app.yaml\x02DEFAULT_GROUP\x01")))
+			case <-time.After(50 * time.Millisecond):
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/nacos", "v1", "", "")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := client.Watch(ctx, ConfigKey{Namespace: "public", Group: "DEFAULT_GROUP", DataId: "app.yaml"})
+	require.NoError(t, err)
+
+	first := <-events
+	require.NoError(t, first.Err)
+	assert.Equal(t, "v1", first.Config.Content)
+	assert.Nil(t, first.Previous)
+
+	mu.Lock()
+	content = "v2"
+	mu.Unlock()
+	changes <- struct{}{}
+
+	second := <-events
+	require.NoError(t, second.Err)
+	assert.Equal(t, "v2", second.Config.Content)
+	assert.Equal(t, "v1", second.Previous.Content)
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestListenRequestFormat(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		got = r.PostForm.Get(listeningConfigsKey)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "v1", "", "")
+	keys := []ConfigKey{{Namespace: "dev", Group: "G", DataId: "a"}, {Namespace: "public", Group: "G", DataId: "b"}}
+	_, err := client.listen(context.Background(), "/", keys, map[ConfigKey]*NacosConfigDetail{
+		keys[0]: {Content: "x"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "a\x02G\x029dd4e461268c8034f5c8564e155c67a6\x02dev\x01b\x02G\x02\x01", got)
+}
+
+func TestWatchBacksOffFetchFailures(t *testing.T) {
+	backoff := watchBackoff
+	watchBackoff = &RetryPolicy{InitialBackoff: 20 * time.Millisecond, MaxBackoff: 80 * time.Millisecond}
+	t.Cleanup(func() { watchBackoff = backoff })
+
+	var mu sync.Mutex
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/nacos/v1/cs/configs":
+			mu.Lock()
+			fetches++
+			mu.Unlock()
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/nacos/v1/cs/configs/listener":
+			// 拉取失败的配置 MD5 为空，服务端总是立即通知变更
+			_, _ = w.Write([]byte(url.QueryEscape("app.yaml\x02DEFAULT_GROUP\x01")))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/nacos", "v1", "", "")
+	client.RetryPolicy = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := ConfigKey{Namespace: "public", Group: "DEFAULT_GROUP", DataId: "app.yaml"}
+	events, err := client.Watch(ctx, key)
+	require.NoError(t, err)
+
+	first := <-events
+	assert.Error(t, first.Err)
+
+	// 连续失败后发送错误事件，其间按退避等待而不是立即重新拉取
+	second := <-events
+	assert.Error(t, second.Err)
+	assert.Equal(t, key, second.Key)
+	mu.Lock()
+	assert.Equal(t, watchFetchErrors, fetches)
+	mu.Unlock()
+
+	time.Sleep(200 * time.Millisecond)
+	mu.Lock()
+	assert.Less(t, fetches, 10)
+	mu.Unlock()
+
+	cancel()
+	for range events {
+	}
+}
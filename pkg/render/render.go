@@ -0,0 +1,264 @@
+// Package render 在发布前渲染 Go text/template 格式的配置模板
+package render
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"gopkg.in/yaml.v3"
+)
+
+// lookupFunc 按路径查找变量的模板函数，变量不存在时返回 nil 而不是报错
+const lookupFunc = "lookup"
+
+// Render 使用 values 渲染模板。
+// 模板中引用但未提供的变量（包括 if、quote、printf 中的引用）视为错误，
+// 只有作为 default、required 的参数时允许缺失
+func Render(name, text string, values map[string]interface{}) (string, error) {
+	tmpl, err := template.New(name).Funcs(funcs()).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			allowMissing(t.Tree.Root)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, values); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// allowMissing 将 default、required 参数中的变量引用（如 .db.pool | default 10）
+// 改写为 lookup 调用，使缺失的变量交给这两个函数处理，而不是在求值时报错
+func allowMissing(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			allowMissing(child)
+		}
+	case *parse.ActionNode:
+		allowMissing(n.Pipe)
+	case *parse.IfNode:
+		allowMissing(&n.BranchNode)
+	case *parse.RangeNode:
+		allowMissing(&n.BranchNode)
+	case *parse.WithNode:
+		allowMissing(&n.BranchNode)
+	case *parse.BranchNode:
+		allowMissing(n.Pipe)
+		allowMissing(n.List)
+		allowMissing(n.ElseList)
+	case *parse.TemplateNode:
+		allowMissing(n.Pipe)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for i, cmd := range n.Cmds {
+			for _, arg := range cmd.Args {
+				allowMissing(arg)
+			}
+			if !isFallbackFunc(cmd) {
+				continue
+			}
+			for j, arg := range cmd.Args[1:] {
+				if lookup := lookupCommand(arg); lookup != nil {
+					cmd.Args[j+1] = &parse.PipeNode{NodeType: parse.NodePipe, Pos: arg.Position(), Cmds: []*parse.CommandNode{lookup}}
+				}
+			}
+			// 通过管道传入的变量
+			if i > 0 && len(n.Cmds[i-1].Args) == 1 {
+				if lookup := lookupCommand(n.Cmds[i-1].Args[0]); lookup != nil {
+					n.Cmds[i-1] = lookup
+				}
+			}
+		}
+	}
+}
+
+func isFallbackFunc(cmd *parse.CommandNode) bool {
+	if len(cmd.Args) == 0 {
+		return false
+	}
+	ident, ok := cmd.Args[0].(*parse.IdentifierNode)
+	return ok && (ident.Ident == "default" || ident.Ident == "required")
+}
+
+// lookupCommand 将 .a.b 或 $x.a.b 改写为 lookup . "a.b" 或 lookup $x "a.b"，其他节点返回 nil
+func lookupCommand(node parse.Node) *parse.CommandNode {
+	var base parse.Node
+	var path []string
+
+	switch n := node.(type) {
+	case *parse.FieldNode:
+		base, path = &parse.DotNode{NodeType: parse.NodeDot, Pos: n.Pos}, n.Ident
+	case *parse.VariableNode:
+		if len(n.Ident) < 2 {
+			return nil
+		}
+		base, path = &parse.VariableNode{NodeType: parse.NodeVariable, Pos: n.Pos, Ident: n.Ident[:1]}, n.Ident[1:]
+	default:
+		return nil
+	}
+
+	joined := strings.Join(path, ".")
+	return &parse.CommandNode{NodeType: parse.NodeCommand, Pos: node.Position(), Args: []parse.Node{
+		parse.NewIdentifier(lookupFunc).SetPos(node.Position()),
+		base,
+		&parse.StringNode{NodeType: parse.NodeString, Pos: node.Position(), Quoted: strconv.Quote(joined), Text: joined},
+	}}
+}
+
+// lookup 按 a.b.c 路径在嵌套 map 中查找变量，任意一级不存在时返回 nil
+func lookup(value interface{}, path string) interface{} {
+	for _, key := range strings.Split(path, ".") {
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		item := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+		if !item.IsValid() {
+			return nil
+		}
+		value = item.Interface()
+	}
+	return value
+}
+
+func funcs() template.FuncMap {
+	return template.FuncMap{
+		lookupFunc: lookup,
+		"default": func(def interface{}, given ...interface{}) interface{} {
+			if len(given) == 0 || empty(given[0]) {
+				return def
+			}
+			return given[0]
+		},
+		"required": func(msg string, value interface{}) (interface{}, error) {
+			if empty(value) {
+				return nil, errors.New(msg)
+			}
+			return value, nil
+		},
+		"env": os.Getenv,
+		"b64enc": func(s string) string {
+			return base64.StdEncoding.EncodeToString([]byte(s))
+		},
+		"b64dec": func(s string) (string, error) {
+			data, err := base64.StdEncoding.DecodeString(s)
+			return string(data), err
+		},
+		"quote": func(value interface{}) string {
+			return strconv.Quote(fmt.Sprint(value))
+		},
+	}
+}
+
+// empty 判断值是否为空：nil、零值、空字符串或空集合
+func empty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	}
+	return v.IsZero()
+}
+
+// LoadValues 依次合并 values 文件和 --set 参数，后者覆盖前者
+func LoadValues(files []string, sets []string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		fileValues := make(map[string]interface{})
+		if err := yaml.Unmarshal(data, &fileValues); err != nil {
+			return nil, fmt.Errorf("invalid values file %s: %w", file, err)
+		}
+		merge(values, fileValues)
+	}
+
+	for _, set := range sets {
+		if err := setValue(values, set); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// merge 将 src 深度合并到 dst
+func merge(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			merge(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// setValue 解析 a.b.c=value 并写入 values
+func setValue(values map[string]interface{}, set string) error {
+	path, raw, ok := strings.Cut(set, "=")
+	if !ok || path == "" {
+		return fmt.Errorf("invalid --set %q, expected key=value", set)
+	}
+
+	keys := strings.Split(path, ".")
+	current := values
+	for _, key := range keys[:len(keys)-1] {
+		if key == "" {
+			return fmt.Errorf("invalid --set %q, empty key", set)
+		}
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[key] = next
+		}
+		current = next
+	}
+
+	last := keys[len(keys)-1]
+	if last == "" {
+		return fmt.Errorf("invalid --set %q, empty key", set)
+	}
+	current[last] = parseScalar(raw)
+	return nil
+}
+
+// parseScalar 推断 --set 值的类型，使 true/false 和整数在模板条件中按预期工作
+func parseScalar(raw string) interface{} {
+	if raw == "true" || raw == "false" {
+		return raw == "true"
+	}
+	// 保留前导零等非规范写法的原始字符串
+	if i, err := strconv.ParseInt(raw, 10, 64); err == nil && strconv.FormatInt(i, 10) == raw {
+		return i
+	}
+	return raw
+}
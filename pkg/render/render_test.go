@@ -0,0 +1,74 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	t.Setenv("RENDER_TEST_REGION", "cn-hangzhou")
+
+	values := map[string]interface{}{
+		"db":    map[string]interface{}{"host": "db.prod", "port": int64(3306)},
+		"debug": false,
+	}
+	out, err := Render("app.yaml", `host: {{ .db.host }}:{{ .db.port }}
+pool: {{ .db.pool | default 10 }}
+region: {{ env "RENDER_TEST_REGION" }}
+secret: {{ "s3cr3t" | b64enc }}
+{{- if not .debug }}
+log: info
+{{- end }}
+`, values)
+	require.NoError(t, err)
+	assert.Equal(t, "host: db.prod:3306\npool: 10\nregion: cn-hangzhou\nsecret: czNjcjN0\nlog: info\n", out)
+
+	// 缺失的变量无论如何引用都报错
+	for _, text := range []string{
+		"a: 1\nhost: {{ .db.user }}\n",
+		`{{ .db.user | quote }}`,
+		`{{ printf "%v" .db.user }}`,
+		`{{ if .feature.enabled }}on{{ end }}`,
+		`{{ range $k, $v := .db }}{{ $.cache.size }}{{ end }}`,
+	} {
+		_, err = Render("app.yaml", text, values)
+		assert.ErrorContains(t, err, "map has no entry for key", text)
+	}
+
+	// default 和 required 处理缺失的变量，包括直接传参、管道后继续处理和 $ 变量
+	out, err = Render("app.yaml", `{{ default 10 .db.pool }} {{ .db.pool | default 5 | quote }} {{ $.cache.size | default 1 }} {{ .cache.size.max | default 2 }}`, values)
+	require.NoError(t, err)
+	assert.Equal(t, `10 "5" 1 2`, out)
+
+	_, err = Render("app.yaml", `{{ required "db.user is required" .db.user }}`, values)
+	assert.ErrorContains(t, err, "db.user is required")
+
+	_, err = Render("app.yaml", `{{ .db.user | required "db.user is required" }}`, values)
+	assert.ErrorContains(t, err, "db.user is required")
+
+	// 内容中的 <no value> 字面量不是缺失的变量
+	out, err = Render("app.yaml", "placeholder: <no value>\nhost: {{ .db.host }}", values)
+	require.NoError(t, err)
+	assert.Equal(t, "placeholder: <no value>\nhost: db.prod", out)
+}
+
+func TestLoadValues(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "prod.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("db:\n  host: a\n  port: 3306\nzone: \"007\"\n"), 0644))
+
+	values, err := LoadValues([]string{file}, []string{"db.host=b", "feature.enabled=true", "code=007"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"db":      map[string]interface{}{"host": "b", "port": 3306},
+		"zone":    "007",
+		"feature": map[string]interface{}{"enabled": true},
+		"code":    "007",
+	}, values)
+
+	_, err = LoadValues(nil, []string{"novalue"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,149 @@
+// Package secret 在发布前展开配置中的密钥引用。
+//
+// 引用格式为 ${scheme:ref}，例如 ${env:DB_PASS}、${file:/run/secrets/db}、${secret:vault/db#password}。
+// 只展开已注册 scheme 的引用，其他 ${...} 占位符（如 Spring 的 ${server.port:8080}）保持原样；
+// 需要保留字面量 ${env:X} 时写作 $${env:X}。
+package secret
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// HelperEnv 解析 ${secret:...} 引用的外部命令，命令以引用为唯一参数，标准输出作为密钥值
+const HelperEnv = "NACOS_SECRET_HELPER"
+
+// Masked 脱敏后的占位文本
+const Masked = "******"
+
+var referencePattern = regexp.MustCompile(`\$?\$\{([a-zA-Z][a-zA-Z0-9_-]*):([^}]*)\}`)
+
+// Provider 解析一种 scheme 的引用
+type Provider interface {
+	Resolve(ref string) (string, error)
+}
+
+// ProviderFunc 函数形式的 Provider
+type ProviderFunc func(ref string) (string, error)
+
+func (f ProviderFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+// Resolver 按 scheme 选择 Provider 展开文本中的引用
+type Resolver struct {
+	providers map[string]Provider
+}
+
+// NewResolver 创建没有任何 Provider 的解析器
+func NewResolver() *Resolver {
+	return &Resolver{providers: map[string]Provider{}}
+}
+
+// DefaultResolver 创建内置 env、file 和 secret（外部命令）Provider 的解析器
+func DefaultResolver() *Resolver {
+	return NewResolver().
+		Register("env", ProviderFunc(resolveEnv)).
+		Register("file", ProviderFunc(resolveFile)).
+		Register("secret", ProviderFunc(resolveHelper))
+}
+
+// Register 注册 scheme 的 Provider，已存在时覆盖
+func (r *Resolver) Register(scheme string, provider Provider) *Resolver {
+	r.providers[scheme] = provider
+	return r
+}
+
+// Expand 展开 text 中的引用，返回展开后的文本和解析出的密钥值（用于输出时脱敏）
+func (r *Resolver) Expand(text string) (string, []string, error) {
+	var (
+		values []string
+		errs   []error
+	)
+
+	expanded := referencePattern.ReplaceAllStringFunc(text, func(match string) string {
+		if strings.HasPrefix(match, "$$") {
+			if _, ok := r.providers[referencePattern.FindStringSubmatch(match)[1]]; ok {
+				return match[1:]
+			}
+			return match
+		}
+
+		groups := referencePattern.FindStringSubmatch(match)
+		provider, ok := r.providers[groups[1]]
+		if !ok {
+			return match
+		}
+
+		value, err := provider.Resolve(groups[2])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("resolve %s: %w", match, err))
+			return match
+		}
+		values = append(values, value)
+		return value
+	})
+
+	if len(errs) > 0 {
+		return "", nil, errors.Join(errs...)
+	}
+	return expanded, values, nil
+}
+
+// Mask 将 text 中出现的密钥值替换为 Masked
+func Mask(text string, values []string) string {
+	sorted := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			sorted = append(sorted, value)
+		}
+	}
+	// 先替换较长的值，避免其中包含的较短值被部分替换
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(sorted[i]) > len(sorted[j])
+	})
+
+	for _, value := range sorted {
+		text = strings.ReplaceAll(text, value, Masked)
+	}
+	return text
+}
+
+func resolveEnv(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+// resolveFile 读取文件内容，去掉结尾换行（如 Docker/Kubernetes secret 文件）
+func resolveFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func resolveHelper(ref string) (string, error) {
+	helper := os.Getenv(HelperEnv)
+	if helper == "" {
+		return "", fmt.Errorf("%s is not set", HelperEnv)
+	}
+
+	stderr := &bytes.Buffer{}
+	cmd := exec.Command(helper, ref)
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w: %s", helper, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
@@ -0,0 +1,49 @@
+package secret
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpand(t *testing.T) {
+	t.Setenv("DB_PASS", "p@ss")
+	secretFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(secretFile, []byte("t0ken\n"), 0600))
+
+	resolver := DefaultResolver().Register("secret", ProviderFunc(func(ref string) (string, error) {
+		assert.Equal(t, "vault/db#key", ref)
+		return "vault-value", nil
+	}))
+
+	text := "password: ${env:DB_PASS}\n" +
+		"token: ${file:" + secretFile + "}\n" +
+		"key: ${secret:vault/db#key}\n" +
+		"port: ${server.port:8080}\n" +
+		"home: ${unknown:HOME}\n" +
+		"literal: $${env:DB_PASS}\n"
+
+	expanded, values, err := resolver.Expand(text)
+	require.NoError(t, err)
+	assert.Equal(t, "password: p@ss\n"+
+		"token: t0ken\n"+
+		"key: vault-value\n"+
+		"port: ${server.port:8080}\n"+
+		"home: ${unknown:HOME}\n"+
+		"literal: ${env:DB_PASS}\n", expanded)
+	assert.Equal(t, []string{"p@ss", "t0ken", "vault-value"}, values)
+
+	assert.Equal(t, "password: ******\nkey: ******", Mask("password: p@ss\nkey: vault-value", values))
+}
+
+func TestExpandError(t *testing.T) {
+	_, _, err := DefaultResolver().Expand("a: ${env:NACOSCTL_MISSING_VAR}")
+	assert.ErrorContains(t, err, "NACOSCTL_MISSING_VAR")
+
+	t.Setenv(HelperEnv, "")
+	_, _, err = DefaultResolver().Expand("a: ${secret:vault/db#key}")
+	assert.ErrorContains(t, err, HelperEnv)
+}
@@ -0,0 +1,196 @@
+// Package selector 解析并计算基于配置标签的选择器，语法与 kubectl 的 -l 一致：
+//
+//	team=payments,tier!=dev    等于 / 不等于
+//	env in (dev,test)          属于集合
+//	env notin (prod)           不属于集合
+//	legacy, !legacy            存在 / 不存在
+//
+// 多个条件之间以逗号分隔，需要同时满足。
+// 配置标签（config_tags）中的 key=value 被视为标签 key 的值，不含 = 的标签值为空字符串。
+package selector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operator 条件运算符
+type Operator string
+
+const (
+	Equals       Operator = "="
+	NotEquals    Operator = "!="
+	In           Operator = "in"
+	NotIn        Operator = "notin"
+	Exists       Operator = "exists"
+	DoesNotExist Operator = "!"
+)
+
+// Requirement 单个条件
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+// Selector 多个条件的组合，空选择器匹配所有标签
+type Selector []Requirement
+
+// Parse 解析选择器表达式，空字符串返回匹配所有标签的空选择器。
+// 非空表达式中的空条件（如 ","、" "、"a,,b"）视为错误，避免拼写错误时匹配所有配置
+func Parse(s string) (Selector, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var selector Selector
+	for _, term := range splitTerms(s) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			return nil, fmt.Errorf("invalid selector %q: empty requirement", s)
+		}
+		requirement, err := parseRequirement(term)
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, requirement)
+	}
+	return selector, nil
+}
+
+// splitTerms 按顶层逗号分隔，忽略括号内的逗号
+func splitTerms(s string) []string {
+	var terms []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(terms, s[start:])
+}
+
+func parseRequirement(term string) (Requirement, error) {
+	if strings.HasPrefix(term, "!") {
+		key := strings.TrimSpace(term[1:])
+		if err := validateKey(key, term); err != nil {
+			return Requirement{}, err
+		}
+		return Requirement{Key: key, Operator: DoesNotExist}, nil
+	}
+
+	if i := strings.Index(term, "!="); i >= 0 {
+		return binary(term, term[:i], NotEquals, term[i+2:])
+	}
+	if i := strings.Index(term, "=="); i >= 0 {
+		return binary(term, term[:i], Equals, term[i+2:])
+	}
+	if i := strings.Index(term, "="); i >= 0 {
+		return binary(term, term[:i], Equals, term[i+1:])
+	}
+
+	if open := strings.Index(term, "("); open >= 0 {
+		if !strings.HasSuffix(term, ")") {
+			return Requirement{}, fmt.Errorf("invalid selector %q: missing )", term)
+		}
+		fields := strings.Fields(term[:open])
+		if len(fields) != 2 {
+			return Requirement{}, fmt.Errorf("invalid selector %q", term)
+		}
+		operator := Operator(strings.ToLower(fields[1]))
+		if operator != In && operator != NotIn {
+			return Requirement{}, fmt.Errorf("invalid selector %q: unknown operator %s", term, fields[1])
+		}
+		if err := validateKey(fields[0], term); err != nil {
+			return Requirement{}, err
+		}
+
+		var values []string
+		for _, value := range strings.Split(term[open+1:len(term)-1], ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		if len(values) == 0 {
+			return Requirement{}, fmt.Errorf("invalid selector %q: empty value set", term)
+		}
+		return Requirement{Key: fields[0], Operator: operator, Values: values}, nil
+	}
+
+	if err := validateKey(term, term); err != nil {
+		return Requirement{}, err
+	}
+	return Requirement{Key: term, Operator: Exists}, nil
+}
+
+func binary(term, key string, operator Operator, value string) (Requirement, error) {
+	key = strings.TrimSpace(key)
+	if err := validateKey(key, term); err != nil {
+		return Requirement{}, err
+	}
+	return Requirement{Key: key, Operator: operator, Values: []string{strings.TrimSpace(value)}}, nil
+}
+
+func validateKey(key, term string) error {
+	if key == "" || strings.ContainsAny(key, " \t=!(),") {
+		return fmt.Errorf("invalid selector %q: invalid key %q", term, key)
+	}
+	return nil
+}
+
+// Matches 判断标签是否满足所有条件
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches 判断标签是否满足条件。与 kubectl 一致，!= 和 notin 在 key 不存在时也视为满足
+func (r Requirement) Matches(labels map[string]string) bool {
+	value, ok := labels[r.Key]
+	switch r.Operator {
+	case Equals:
+		return ok && value == r.Values[0]
+	case NotEquals:
+		return !ok || value != r.Values[0]
+	case In:
+		return ok && contains(r.Values, value)
+	case NotIn:
+		return !ok || !contains(r.Values, value)
+	case Exists:
+		return ok
+	case DoesNotExist:
+		return !ok
+	}
+	return false
+}
+
+// Labels 将 config_tags 中的标签转换为 key/value
+func Labels(tags []string) map[string]string {
+	labels := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		key, value, _ := strings.Cut(tag, "=")
+		labels[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return labels
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	s, err := Parse("team=payments, tier!=dev,env in (test, prod),legacy,!deprecated")
+	require.NoError(t, err)
+	assert.Equal(t, Selector{
+		{Key: "team", Operator: Equals, Values: []string{"payments"}},
+		{Key: "tier", Operator: NotEquals, Values: []string{"dev"}},
+		{Key: "env", Operator: In, Values: []string{"test", "prod"}},
+		{Key: "legacy", Operator: Exists},
+		{Key: "deprecated", Operator: DoesNotExist},
+	}, s)
+
+	for _, invalid := range []string{"=x", "env in ()", "env in (a", "env like (a)", ",", " ", "team=payments,", "a,,b"} {
+		_, err := Parse(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	labels := Labels([]string{"team=payments", "tier=prod", "legacy"})
+
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"team=payments", true},
+		{"team==payments,tier!=dev", true},
+		{"tier!=prod", false},
+		{"owner!=alice", true},
+		{"tier in (test,prod)", true},
+		{"tier notin (prod)", false},
+		{"owner notin (alice)", true},
+		{"legacy", true},
+		{"!legacy", false},
+		{"team=payments,owner", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			s, err := Parse(tt.selector)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s.Matches(labels))
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package term
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/moby/term"
+)
+
+// Reader 按行读取输入流。同一个流上的多次读取必须共享一个 Reader：
+// 缓冲区可能已经读入了后面的行（如管道输入的用户名和密码），每次新建缓冲区会丢失这些内容
+type Reader struct {
+	in  io.Reader
+	buf *bufio.Reader
+}
+
+// NewReader 创建读取 in 的 Reader
+func NewReader(in io.Reader) *Reader {
+	return &Reader{in: in, buf: bufio.NewReader(in)}
+}
+
+// Read 实现 io.Reader，先返回缓冲区中已读入的内容
+func (r *Reader) Read(p []byte) (int, error) {
+	return r.buf.Read(p)
+}
+
+// ReadPassword 输出提示并读取一行密码。
+// 输入为终端时关闭回显，否则（如管道输入）直接读取一行
+func (r *Reader) ReadPassword(out io.Writer, prompt string) (string, error) {
+	if fd, isTerminal := term.GetFdInfo(r.in); isTerminal {
+		state, err := term.SaveState(fd)
+		if err != nil {
+			return "", err
+		}
+		if err := term.DisableEcho(fd, state); err != nil {
+			return "", err
+		}
+		defer func() {
+			_ = term.RestoreTerminal(fd, state)
+			// 回显关闭时用户输入的换行不会显示
+			fmt.Fprintln(out)
+		}()
+	}
+
+	fmt.Fprint(out, prompt)
+	return r.ReadLine()
+}
+
+// ReadLine 读取一行并去掉行尾换行符，输入结束时返回已读取的内容
+func (r *Reader) ReadLine() (string, error) {
+	line, err := r.buf.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
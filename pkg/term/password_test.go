@@ -0,0 +1,42 @@
+package term
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReaderSharesBuffer(t *testing.T) {
+	// 管道输入一次写入多行，第一次读取会把后面的行读入缓冲区
+	r := NewReader(strings.NewReader("nacos\r\ns3cret\nrest\n"))
+	out := &bytes.Buffer{}
+
+	name, err := r.ReadLine()
+	require.NoError(t, err)
+	assert.Equal(t, "nacos", name)
+
+	password, err := r.ReadPassword(out, "Password: ")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", password)
+	assert.Equal(t, "Password: ", out.String(), "非终端输入不输出额外的换行")
+
+	rest, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "rest\n", string(rest))
+}
+
+func TestReadLineEOF(t *testing.T) {
+	r := NewReader(strings.NewReader("last"))
+
+	line, err := r.ReadLine()
+	require.NoError(t, err)
+	assert.Equal(t, "last", line)
+
+	line, err = r.ReadLine()
+	require.NoError(t, err)
+	assert.Empty(t, line)
+}
@@ -0,0 +1,41 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.yaml")
+
+	require.NoError(t, WriteFileAtomic(path, []byte("a: 1\n"), 0600))
+	require.NoError(t, WriteFileAtomic(path, []byte("a: 2\n"), 0644))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "a: 2\n", string(data))
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+	}
+
+	// 不留下临时文件
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "app.yaml", entries[0].Name())
+}
+
+func TestWriteFileAtomicMissingDir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "app.yaml")
+	assert.Error(t, WriteFileAtomic(path, []byte("a: 1\n"), 0644))
+	assert.NoFileExists(t, path)
+}
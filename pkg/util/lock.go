@@ -0,0 +1,34 @@
+package util
+
+import (
+	"os"
+	"sync"
+)
+
+// processLocks 进程内按文件路径加锁，同一进程的多个 goroutine 不必都阻塞在系统调用上
+var processLocks sync.Map
+
+// LockFile 获取 path 的排他锁，阻塞直到获取成功，返回释放锁的函数。
+// 锁同时作用于当前进程内的其他 goroutine 和其他进程（建议锁，只约束同样调用 LockFile 的代码）
+func LockFile(path string) (func(), error) {
+	value, _ := processLocks.LoadOrStore(path, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		mu.Unlock()
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		mu.Unlock()
+		return nil, err
+	}
+
+	return func() {
+		_ = unlockFile(f)
+		f.Close()
+		mu.Unlock()
+	}, nil
+}